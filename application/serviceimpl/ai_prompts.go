@@ -0,0 +1,90 @@
+package serviceimpl
+
+import (
+	"fmt"
+
+	"gofiber-template/domain/services"
+)
+
+// searchResultContext represents context from search results
+type searchResultContext struct {
+	Title   string
+	Snippet string
+	URL     string
+}
+
+// travelSummarySystemPrompt returns the travel summary system prompt based on language
+func travelSummarySystemPrompt(lang string) string {
+	if lang == "en" {
+		return `You are a travel information assistant for students at Sukhothai Thammathirat Open University (STOU).
+Summarize information from the provided sources concisely, clearly, and usefully.
+
+Rules for responding:
+1. Respond in English
+2. Format as Markdown
+3. Summarize with main headings and bullet points
+4. Cite the source of information
+5. If there is price information, opening hours, or important details, include them
+6. Suggest 2-3 relevant follow-up questions`
+	}
+	return `คุณเป็นผู้ช่วยค้นหาข้อมูลท่องเที่ยวสำหรับนักศึกษามหาวิทยาลัยสุโขทัยธรรมาธิราช (มสธ.)
+ให้สรุปข้อมูลจาก sources ที่ได้รับอย่างกระชับ ชัดเจน และเป็นประโยชน์
+
+กฎในการตอบ:
+1. ตอบเป็นภาษาไทย
+2. จัดรูปแบบเป็น Markdown
+3. สรุปเป็นหัวข้อหลักๆ พร้อม bullet points
+4. ระบุ source ที่มาของข้อมูล
+5. หากมีข้อมูลราคา เวลาเปิด-ปิด หรือข้อมูลสำคัญ ให้ระบุด้วย
+6. เสนอคำถาม follow-up ที่เกี่ยวข้อง 2-3 ข้อ`
+}
+
+// chatSystemPrompt returns the chat system prompt based on language
+func chatSystemPrompt(lang string) string {
+	if lang == "en" {
+		return `You are a travel information assistant for STOU students.
+Answer travel-related questions in a friendly manner and provide useful information.
+Respond in English and use Markdown format.`
+	}
+	return `คุณเป็นผู้ช่วยค้นหาข้อมูลท่องเที่ยวสำหรับนักศึกษา มสธ.
+ตอบคำถามเกี่ยวกับการท่องเที่ยวอย่างเป็นมิตรและให้ข้อมูลที่เป็นประโยชน์
+ตอบเป็นภาษาไทยและใช้ Markdown format`
+}
+
+// buildTravelSummaryMessages builds the prompt used to summarize search results
+func buildTravelSummaryMessages(query string, searchResults []searchResultContext, lang string) []services.LLMMessage {
+	systemPrompt := travelSummarySystemPrompt(lang)
+
+	// Build user prompt with search results
+	var userPrompt string
+	if lang == "en" {
+		userPrompt = fmt.Sprintf("Search query: %s\n\nInformation from various sources:\n", query)
+		for i, result := range searchResults {
+			userPrompt += fmt.Sprintf("\n[Source %d: %s]\n%s\nURL: %s\n",
+				i+1, result.Title, result.Snippet, result.URL)
+		}
+		userPrompt += "\nPlease summarize the above information systematically."
+	} else {
+		userPrompt = fmt.Sprintf("คำค้นหา: %s\n\nข้อมูลจากแหล่งต่างๆ:\n", query)
+		for i, result := range searchResults {
+			userPrompt += fmt.Sprintf("\n[Source %d: %s]\n%s\nURL: %s\n",
+				i+1, result.Title, result.Snippet, result.URL)
+		}
+		userPrompt += "\nกรุณาสรุปข้อมูลข้างต้นอย่างเป็นระบบ"
+	}
+
+	return []services.LLMMessage{
+		{Role: services.LLMRoleSystem, Content: systemPrompt},
+		{Role: services.LLMRoleUser, Content: userPrompt},
+	}
+}
+
+// buildChatMessages builds the prompt for continuing a conversation
+func buildChatMessages(history []services.LLMMessage, newMessage string, lang string) []services.LLMMessage {
+	messages := []services.LLMMessage{
+		{Role: services.LLMRoleSystem, Content: chatSystemPrompt(lang)},
+	}
+	messages = append(messages, history...)
+	messages = append(messages, services.LLMMessage{Role: services.LLMRoleUser, Content: newMessage})
+	return messages
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 
@@ -17,7 +18,6 @@ import (
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/cache"
 	"gofiber-template/infrastructure/external/google"
-	"gofiber-template/infrastructure/external/openai"
 	"gofiber-template/pkg/logger"
 )
 
@@ -25,29 +25,63 @@ type AIServiceImpl struct {
 	sessionRepo  repositories.AIChatSessionRepository
 	messageRepo  repositories.AIChatMessageRepository
 	historyRepo  repositories.SearchHistoryRepository
-	aiClient     *openai.AIClient
+	chatLLM      services.LLMProvider
+	summaryLLM   services.LLMProvider
 	googleSearch *google.SearchClient
 	redisClient  *redis.Client
+	apiLogger    *APILoggerService
 }
 
 func NewAIService(
 	sessionRepo repositories.AIChatSessionRepository,
 	messageRepo repositories.AIChatMessageRepository,
 	historyRepo repositories.SearchHistoryRepository,
-	aiClient *openai.AIClient,
+	chatLLM services.LLMProvider,
+	summaryLLM services.LLMProvider,
 	googleSearch *google.SearchClient,
 	redisClient *redis.Client,
+	apiLogger *APILoggerService,
 ) services.AIService {
 	return &AIServiceImpl{
 		sessionRepo:  sessionRepo,
 		messageRepo:  messageRepo,
 		historyRepo:  historyRepo,
-		aiClient:     aiClient,
+		chatLLM:      chatLLM,
+		summaryLLM:   summaryLLM,
 		googleSearch: googleSearch,
 		redisClient:  redisClient,
+		apiLogger:    apiLogger,
 	}
 }
 
+// searchWeb runs the Google web search an AI answer is grounded on, within
+// the google_search budget, and logs its cost
+func (s *AIServiceImpl) searchWeb(ctx context.Context, userID uuid.UUID, endpoint, query string, perPage int) (*google.SearchResponse, error) {
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "google_search"); err != nil {
+			return nil, err
+		}
+	}
+
+	startTime := time.Now()
+	searchResponse, err := s.googleSearch.SearchAll(ctx, query, 1, perPage)
+	durationMs := int(time.Since(startTime).Milliseconds())
+
+	if s.apiLogger != nil {
+		success := err == nil
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+		}
+		s.apiLogger.LogAPICall(ctx, "google_search", endpoint, map[string]interface{}{
+			"query":    query,
+			"pageSize": perPage,
+		}, 0.005, durationMs, &userID, success, errMsg) // Google Custom Search: ~$5 per 1000 queries
+	}
+
+	return searchResponse, err
+}
+
 func (s *AIServiceImpl) AISearch(ctx context.Context, userID uuid.UUID, req *dto.AISearchRequest) (*dto.AISearchResponse, error) {
 	startTime := time.Now()
 
@@ -82,7 +116,7 @@ func (s *AIServiceImpl) AISearch(ctx context.Context, userID uuid.UUID, req *dto
 	}
 
 	// Cache miss - Get search results from Google
-	searchResponse, err := s.googleSearch.SearchAll(ctx, req.Query, 1, 5)
+	searchResponse, err := s.searchWeb(ctx, userID, "ai_search", req.Query, 5)
 	if err != nil {
 		logger.ErrorContext(ctx, "AI Search - Google Search failed",
 			"user_id", userID.String(),
@@ -101,14 +135,14 @@ func (s *AIServiceImpl) AISearch(ctx context.Context, userID uuid.UUID, req *dto
 
 	// Prepare sources and search context
 	var sources []dto.MessageSource
-	var searchContext []openai.SearchResultContext
+	var searchContext []searchResultContext
 	for _, r := range searchResponse.Items {
 		sources = append(sources, dto.MessageSource{
 			Title:   r.Title,
 			URL:     r.Link,
 			Snippet: r.Snippet,
 		})
-		searchContext = append(searchContext, openai.SearchResultContext{
+		searchContext = append(searchContext, searchResultContext{
 			Title:   r.Title,
 			URL:     r.Link,
 			Snippet: r.Snippet,
@@ -122,7 +156,7 @@ func (s *AIServiceImpl) AISearch(ctx context.Context, userID uuid.UUID, req *dto
 	}
 
 	// Generate AI summary
-	aiResponse, err := s.aiClient.GenerateTravelSummary(ctx, req.Query, searchContext, lang)
+	aiResponse, err := s.generateTravelSummary(ctx, req.Query, searchContext, lang)
 	if err != nil {
 		logger.ErrorContext(ctx, "AI Search - OpenAI failed",
 			"user_id", userID.String(),
@@ -135,10 +169,7 @@ func (s *AIServiceImpl) AISearch(ctx context.Context, userID uuid.UUID, req *dto
 	}
 
 	// Extract summary from response
-	summary := ""
-	if len(aiResponse.Choices) > 0 {
-		summary = aiResponse.Choices[0].Message.Content
-	}
+	summary := aiResponse.Content
 
 	response := &dto.AISearchResponse{
 		Query:   req.Query,
@@ -227,7 +258,7 @@ func (s *AIServiceImpl) CreateChatSession(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Get search results
-	searchResponse, err := s.googleSearch.SearchAll(ctx, req.Query, 1, 5)
+	searchResponse, err := s.searchWeb(ctx, userID, "ai_chat", req.Query, 5)
 	if err != nil {
 		logger.ErrorContext(ctx, "CreateChatSession - Google Search failed",
 			"user_id", userID.String(),
@@ -240,14 +271,14 @@ func (s *AIServiceImpl) CreateChatSession(ctx context.Context, userID uuid.UUID,
 
 	// Prepare sources and search context
 	var sources []models.MessageSource
-	var searchContext []openai.SearchResultContext
+	var searchContext []searchResultContext
 	for _, r := range searchResponse.Items {
 		sources = append(sources, models.MessageSource{
 			Title:   r.Title,
 			URL:     r.Link,
 			Snippet: r.Snippet,
 		})
-		searchContext = append(searchContext, openai.SearchResultContext{
+		searchContext = append(searchContext, searchResultContext{
 			Title:   r.Title,
 			URL:     r.Link,
 			Snippet: r.Snippet,
@@ -255,7 +286,7 @@ func (s *AIServiceImpl) CreateChatSession(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Generate AI response with language
-	aiResponse, err := s.aiClient.GenerateTravelSummary(ctx, req.Query, searchContext, lang)
+	aiResponse, err := s.generateTravelSummary(ctx, req.Query, searchContext, lang)
 	if err != nil {
 		logger.ErrorContext(ctx, "CreateChatSession - OpenAI failed",
 			"user_id", userID.String(),
@@ -269,10 +300,7 @@ func (s *AIServiceImpl) CreateChatSession(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Extract content from response
-	responseContent := ""
-	if len(aiResponse.Choices) > 0 {
-		responseContent = aiResponse.Choices[0].Message.Content
-	}
+	responseContent := aiResponse.Content
 
 	// Create assistant message
 	sourcesJSON, _ := json.Marshal(sources)
@@ -472,6 +500,64 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 		lang = "th"
 	}
 
+	session, chatHistory, sources, err := s.prepareChatTurn(ctx, userID, req, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate AI response with language
+	aiResponse, err := s.continueChat(ctx, chatHistory, req.Message, lang)
+	if err != nil {
+		logger.ErrorContext(ctx, "SendMessage - OpenAI failed",
+			"user_id", userID.String(),
+			"session_id", req.SessionID.String(),
+			"lang", lang,
+			"error", err.Error(),
+			"response_time_ms", time.Since(startTime).Milliseconds(),
+		)
+		return nil, err
+	}
+
+	// Extract content from response
+	responseContent := aiResponse.Content
+
+	// Create assistant message
+	sourcesJSON, _ := json.Marshal(sources)
+	assistantMessage := &models.AIChatMessage{
+		SessionID: req.SessionID,
+		Role:      models.MessageRoleAssistant,
+		Content:   responseContent,
+		Sources:   datatypes.JSON(sourcesJSON),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.messageRepo.Create(ctx, assistantMessage); err != nil {
+		logger.ErrorContext(ctx, "SendMessage - assistant message creation failed",
+			"user_id", userID.String(),
+			"session_id", req.SessionID.String(),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+
+	// Update session
+	session.UpdatedAt = time.Now()
+	_ = s.sessionRepo.Update(ctx, session.ID, session)
+
+	logger.InfoContext(ctx, "SendMessage completed",
+		"user_id", userID.String(),
+		"session_id", req.SessionID.String(),
+		"response_length", len(responseContent),
+		"response_time_ms", time.Since(startTime).Milliseconds(),
+	)
+
+	return dto.AIChatMessageToResponse(assistantMessage), nil
+}
+
+// prepareChatTurn validates session ownership, stores the user message and
+// gathers conversation history plus search sources for the next reply.
+// req.Message is extended with the search context.
+func (s *AIServiceImpl) prepareChatTurn(ctx context.Context, userID uuid.UUID, req *dto.SendAIChatMessageRequest, lang string) (*models.AIChatSession, []services.LLMMessage, []models.MessageSource, error) {
 	session, err := s.sessionRepo.GetByID(ctx, req.SessionID)
 	if err != nil {
 		logger.WarnContext(ctx, "SendMessage - session not found",
@@ -479,7 +565,7 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 			"session_id", req.SessionID.String(),
 			"error", err.Error(),
 		)
-		return nil, errors.New("session not found")
+		return nil, nil, nil, errChatSessionNotFound
 	}
 
 	if session.UserID != userID {
@@ -488,7 +574,7 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 			"session_id", req.SessionID.String(),
 			"session_owner", session.UserID.String(),
 		)
-		return nil, errors.New("unauthorized")
+		return nil, nil, nil, errChatUnauthorized
 	}
 
 	// Create user message
@@ -505,7 +591,7 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 			"session_id", req.SessionID.String(),
 			"error", err.Error(),
 		)
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// Get recent messages for context
@@ -516,7 +602,7 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 			"session_id", req.SessionID.String(),
 			"error", err.Error(),
 		)
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	logger.InfoContext(ctx, "SendMessage - context loaded",
@@ -526,9 +612,9 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 	)
 
 	// Build conversation history
-	var chatHistory []openai.ChatMessage
+	var chatHistory []services.LLMMessage
 	for _, msg := range recentMessages {
-		chatHistory = append(chatHistory, openai.ChatMessage{
+		chatHistory = append(chatHistory, services.LLMMessage{
 			Role:    msg.Role,
 			Content: msg.Content,
 		})
@@ -536,7 +622,7 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 
 	// Get additional search results if query seems like a new question
 	var sources []models.MessageSource
-	searchResponse, err := s.googleSearch.SearchAll(ctx, req.Message, 1, 3)
+	searchResponse, err := s.searchWeb(ctx, userID, "ai_chat", req.Message, 3)
 	if err == nil && searchResponse != nil && len(searchResponse.Items) > 0 {
 		var searchContext string
 		for _, r := range searchResponse.Items {
@@ -555,26 +641,77 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 		}
 	}
 
-	// Generate AI response with language
-	aiResponse, err := s.aiClient.ContinueChat(ctx, chatHistory, req.Message, lang)
+	return session, chatHistory, sources, nil
+}
+
+func (s *AIServiceImpl) SendMessageStream(ctx context.Context, userID uuid.UUID, req *dto.SendAIChatMessageRequest, writer io.Writer) error {
+	startTime := time.Now()
+
+	logger.InfoContext(ctx, "SendMessageStream started",
+		"user_id", userID.String(),
+		"session_id", req.SessionID.String(),
+		"message_length", len(req.Message),
+		"lang", req.Lang,
+	)
+
+	// Get language from request, default to Thai
+	lang := req.Lang
+	if lang == "" {
+		lang = "th"
+	}
+
+	session, chatHistory, sources, err := s.prepareChatTurn(ctx, userID, req, lang)
 	if err != nil {
-		logger.ErrorContext(ctx, "SendMessage - OpenAI failed",
+		logger.WarnContext(ctx, "SendMessageStream - chat turn not started",
+			"user_id", userID.String(),
+			"session_id", req.SessionID.String(),
+			"error", err.Error(),
+		)
+		_ = writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkError, Error: chatTurnStreamError(err)})
+		return err
+	}
+
+	for i := range sources {
+		source := dto.MessageSource{
+			Title:   sources[i].Title,
+			URL:     sources[i].URL,
+			Snippet: sources[i].Snippet,
+		}
+		if err := writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkSource, Source: &source}); err != nil {
+			return s.streamAborted(ctx, "SendMessageStream", userID, startTime, err)
+		}
+	}
+
+	// Stop the upstream request as soon as the client goes away
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	aiResponse, err := s.chatLLM.ChatStream(streamCtx, &services.LLMRequest{
+		Messages:    buildChatMessages(chatHistory, req.Message, lang),
+		MaxTokens:   1500,
+		Temperature: 0.7,
+		Feature:     services.LLMFeatureChat,
+	}, func(delta string) error {
+		return writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkContent, Content: delta})
+	})
+	if err != nil {
+		if isStreamWriteError(err) {
+			return s.streamAborted(ctx, "SendMessageStream", userID, startTime, err)
+		}
+		logger.ErrorContext(ctx, "SendMessageStream - OpenAI failed",
 			"user_id", userID.String(),
 			"session_id", req.SessionID.String(),
 			"lang", lang,
 			"error", err.Error(),
 			"response_time_ms", time.Since(startTime).Milliseconds(),
 		)
-		return nil, err
+		_ = writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkError, Error: llmStreamError(err)})
+		return err
 	}
 
-	// Extract content from response
-	responseContent := ""
-	if len(aiResponse.Choices) > 0 {
-		responseContent = aiResponse.Choices[0].Message.Content
-	}
+	responseContent := aiResponse.Content
 
-	// Create assistant message
+	// Only persist the assistant message once the full reply has been received
 	sourcesJSON, _ := json.Marshal(sources)
 	assistantMessage := &models.AIChatMessage{
 		SessionID: req.SessionID,
@@ -585,64 +722,282 @@ func (s *AIServiceImpl) SendMessage(ctx context.Context, userID uuid.UUID, req *
 	}
 
 	if err := s.messageRepo.Create(ctx, assistantMessage); err != nil {
-		logger.ErrorContext(ctx, "SendMessage - assistant message creation failed",
+		logger.ErrorContext(ctx, "SendMessageStream - assistant message creation failed",
 			"user_id", userID.String(),
 			"session_id", req.SessionID.String(),
 			"error", err.Error(),
 		)
-		return nil, err
+		_ = writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkError, Error: "failed to save message"})
+		return err
 	}
 
 	// Update session
 	session.UpdatedAt = time.Now()
 	_ = s.sessionRepo.Update(ctx, session.ID, session)
 
-	logger.InfoContext(ctx, "SendMessage completed",
+	logger.InfoContext(ctx, "SendMessageStream completed",
 		"user_id", userID.String(),
 		"session_id", req.SessionID.String(),
 		"response_length", len(responseContent),
 		"response_time_ms", time.Since(startTime).Milliseconds(),
 	)
 
-	return dto.AIChatMessageToResponse(assistantMessage), nil
+	return writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkDone, MessageID: &assistantMessage.ID})
 }
 
-func (s *AIServiceImpl) SendMessageStream(ctx context.Context, userID uuid.UUID, req *dto.SendAIChatMessageRequest, writer io.Writer) error {
-	// For streaming, we would need to implement SSE or WebSocket
-	// For now, fallback to regular response
-	response, err := s.SendMessage(ctx, userID, req)
+func (s *AIServiceImpl) AISearchStream(ctx context.Context, userID uuid.UUID, req *dto.AISearchRequest, writer io.Writer) error {
+	startTime := time.Now()
+
+	logger.InfoContext(ctx, "AI Search stream started",
+		"user_id", userID.String(),
+		"query", req.Query,
+		"lang", req.Language,
+	)
+
+	// Cached summaries are replayed as a single content event
+	cacheKey := cache.SearchAIKey(req.Query)
+	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
+		var cachedResult dto.AISearchResponse
+		if json.Unmarshal([]byte(cached), &cachedResult) == nil {
+			logger.InfoContext(ctx, "AI Search stream cache hit",
+				"user_id", userID.String(),
+				"query", req.Query,
+				"response_time_ms", time.Since(startTime).Milliseconds(),
+			)
+			for i := range cachedResult.Sources {
+				if err := writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkSource, Source: &cachedResult.Sources[i]}); err != nil {
+					return s.streamAborted(ctx, "AISearchStream", userID, startTime, err)
+				}
+			}
+			if err := writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkContent, Content: cachedResult.Summary}); err != nil {
+				return s.streamAborted(ctx, "AISearchStream", userID, startTime, err)
+			}
+			s.saveAISearchHistory(ctx, userID, req.Query, len(cachedResult.Sources))
+			return writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkDone})
+		}
+	}
+
+	searchResponse, err := s.searchWeb(ctx, userID, "ai_search", req.Query, 5)
 	if err != nil {
+		logger.ErrorContext(ctx, "AI Search stream - Google Search failed",
+			"user_id", userID.String(),
+			"query", req.Query,
+			"error", err.Error(),
+			"response_time_ms", time.Since(startTime).Milliseconds(),
+		)
+		_ = writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkError, Error: "search failed"})
 		return err
 	}
 
-	// Write response as JSON
-	data, err := json.Marshal(response)
+	// Send sources first so the UI can render them while the summary streams
+	var sources []dto.MessageSource
+	var searchContext []searchResultContext
+	for _, r := range searchResponse.Items {
+		source := dto.MessageSource{
+			Title:   r.Title,
+			URL:     r.Link,
+			Snippet: r.Snippet,
+		}
+		sources = append(sources, source)
+		searchContext = append(searchContext, searchResultContext{
+			Title:   r.Title,
+			URL:     r.Link,
+			Snippet: r.Snippet,
+		})
+		if err := writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkSource, Source: &source}); err != nil {
+			return s.streamAborted(ctx, "AISearchStream", userID, startTime, err)
+		}
+	}
+
+	// Get language from request, default to Thai
+	lang := req.Language
+	if lang == "" {
+		lang = "th"
+	}
+
+	// Stop the upstream request as soon as the client goes away
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	aiResponse, err := s.summaryLLM.ChatStream(streamCtx, &services.LLMRequest{
+		Messages:    buildTravelSummaryMessages(req.Query, searchContext, lang),
+		MaxTokens:   2000,
+		Temperature: 0.7,
+		Feature:     services.LLMFeatureAISearch,
+	}, func(delta string) error {
+		return writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkContent, Content: delta})
+	})
 	if err != nil {
+		if isStreamWriteError(err) {
+			return s.streamAborted(ctx, "AISearchStream", userID, startTime, err)
+		}
+		logger.ErrorContext(ctx, "AI Search stream - OpenAI failed",
+			"user_id", userID.String(),
+			"query", req.Query,
+			"lang", lang,
+			"error", err.Error(),
+			"response_time_ms", time.Since(startTime).Milliseconds(),
+		)
+		_ = writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkError, Error: llmStreamError(err)})
 		return err
 	}
 
-	_, err = writer.Write(data)
-	return err
+	// Only complete summaries are cached
+	response := &dto.AISearchResponse{
+		Query:   req.Query,
+		Summary: aiResponse.Content,
+		Sources: sources,
+	}
+	if jsonData, err := json.Marshal(response); err == nil {
+		s.redisClient.Set(ctx, cacheKey, jsonData, cache.TTLSearchAI)
+	}
+
+	s.saveAISearchHistory(ctx, userID, req.Query, len(sources))
+
+	logger.InfoContext(ctx, "AI Search stream completed",
+		"user_id", userID.String(),
+		"query", req.Query,
+		"sources_count", len(sources),
+		"response_time_ms", time.Since(startTime).Milliseconds(),
+	)
+
+	return writeStreamChunk(writer, dto.AIStreamChunk{Type: dto.StreamChunkDone})
 }
 
-func (s *AIServiceImpl) AISearchStream(ctx context.Context, userID uuid.UUID, req *dto.AISearchRequest, writer io.Writer) error {
-	// For streaming, we would need to implement SSE or WebSocket
-	// For now, fallback to regular response
-	response, err := s.AISearch(ctx, userID, req)
+// generateTravelSummary summarizes search results with the travel summary provider
+func (s *AIServiceImpl) generateTravelSummary(ctx context.Context, query string, searchResults []searchResultContext, lang string) (*services.LLMResponse, error) {
+	logger.InfoContext(ctx, "GenerateTravelSummary started",
+		"provider", s.summaryLLM.Name(),
+		"query", query,
+		"lang", lang,
+		"search_results_count", len(searchResults),
+	)
+
+	response, err := s.summaryLLM.Chat(ctx, &services.LLMRequest{
+		Messages:    buildTravelSummaryMessages(query, searchResults, lang),
+		MaxTokens:   2000,
+		Temperature: 0.7,
+		Feature:     services.LLMFeatureAISearch,
+	})
 	if err != nil {
-		return err
+		logger.ErrorContext(ctx, "GenerateTravelSummary failed",
+			"query", query,
+			"lang", lang,
+			"error", err.Error(),
+		)
+		return nil, err
 	}
 
-	// Write response as JSON
-	data, err := json.Marshal(response)
+	return response, nil
+}
+
+// continueChat generates the next assistant reply with the chat provider
+func (s *AIServiceImpl) continueChat(ctx context.Context, history []services.LLMMessage, newMessage string, lang string) (*services.LLMResponse, error) {
+	logger.InfoContext(ctx, "ContinueChat started",
+		"provider", s.chatLLM.Name(),
+		"lang", lang,
+		"history_count", len(history),
+		"new_message_length", len(newMessage),
+	)
+
+	response, err := s.chatLLM.Chat(ctx, &services.LLMRequest{
+		Messages:    buildChatMessages(history, newMessage, lang),
+		MaxTokens:   1500,
+		Temperature: 0.7,
+		Feature:     services.LLMFeatureChat,
+	})
 	if err != nil {
-		return err
+		logger.ErrorContext(ctx, "ContinueChat failed",
+			"lang", lang,
+			"history_count", len(history),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *AIServiceImpl) saveAISearchHistory(ctx context.Context, userID uuid.UUID, query string, resultCount int) {
+	if userID == uuid.Nil {
+		return
+	}
+	history := &models.SearchHistory{
+		UserID:      userID,
+		Query:       query,
+		SearchType:  models.SearchTypeAI,
+		ResultCount: resultCount,
 	}
+	_ = s.historyRepo.Create(ctx, history)
+}
 
-	_, err = writer.Write(data)
+func (s *AIServiceImpl) streamAborted(ctx context.Context, operation string, userID uuid.UUID, startTime time.Time, err error) error {
+	logger.WarnContext(ctx, operation+" - client disconnected",
+		"user_id", userID.String(),
+		"error", err.Error(),
+		"response_time_ms", time.Since(startTime).Milliseconds(),
+	)
 	return err
 }
 
+// streamWriteError marks failures writing to the client, i.e. an aborted stream
+type streamWriteError struct {
+	err error
+}
+
+func (e *streamWriteError) Error() string { return "stream write: " + e.err.Error() }
+func (e *streamWriteError) Unwrap() error { return e.err }
+
+func isStreamWriteError(err error) bool {
+	var writeErr *streamWriteError
+	return errors.As(err, &writeErr)
+}
+
+// llmStreamError is the error chunk text for a failed completion; only the
+// API budget is worth telling the client about
+func llmStreamError(err error) string {
+	if errors.Is(err, services.ErrAPIBudgetDegraded) || errors.Is(err, services.ErrAPIBudgetExhausted) {
+		return err.Error()
+	}
+	return "AI response failed"
+}
+
+// Errors of prepareChatTurn that the client may see
+var (
+	errChatSessionNotFound = errors.New("session not found")
+	errChatUnauthorized    = errors.New("unauthorized")
+)
+
+// chatTurnStreamError is the error event for a chat turn that couldn't start;
+// database errors stay in the log like they do for SendMessage
+func chatTurnStreamError(err error) string {
+	if errors.Is(err, errChatSessionNotFound) || errors.Is(err, errChatUnauthorized) {
+		return err.Error()
+	}
+	return "Failed to send message"
+}
+
+// writeStreamChunk writes a chunk as a Server-Sent Event and flushes it
+// when the writer supports flushing.
+func writeStreamChunk(writer io.Writer, chunk dto.AIStreamChunk) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", chunk.Type, data); err != nil {
+		return &streamWriteError{err: err}
+	}
+
+	if flusher, ok := writer.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return &streamWriteError{err: err}
+		}
+	}
+
+	return nil
+}
+
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
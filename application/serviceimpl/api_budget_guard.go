@@ -0,0 +1,385 @@
+package serviceimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/infrastructure/websocket"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
+)
+
+// API budget states
+const (
+	APIBudgetOK       = "ok"
+	APIBudgetDegraded = "degraded" // answers only from cache and the database
+	APIBudgetBlocked  = "blocked"
+)
+
+const (
+	// apiBudgetStateTTL is how long a replica trusts a service's state before
+	// reading the shared counters again; its own calls update it at once
+	apiBudgetStateTTL = 5 * time.Second
+	// apiBudgetAlertTimeout bounds sending one alert to admins and the webhook
+	apiBudgetAlertTimeout = 10 * time.Second
+)
+
+// APIBudgetStore keeps spend counters shared by all replicas
+type APIBudgetStore interface {
+	AddSpend(ctx context.Context, key string, amount float64, ttl time.Duration) (float64, error)
+	RaiseSpend(ctx context.Context, key string, amount float64, ttl time.Duration) (float64, float64, error)
+	GetSpend(ctx context.Context, keys ...string) ([]float64, error)
+}
+
+// APIBudgetGuard tracks the estimated spend of each external API against its
+// daily and monthly budget. From the soft threshold on, a service answers
+// only from cache and the database; at the limit it is blocked. Admins are
+// alerted when a service crosses either.
+type APIBudgetGuard struct {
+	store    APIBudgetStore
+	logRepo  repositories.APIRequestLogRepository
+	userRepo repositories.UserRepository
+	config   config.BudgetConfig
+	client   *http.Client
+
+	mu     sync.Mutex
+	states map[string]apiBudgetState
+}
+
+type apiBudgetState struct {
+	state     string
+	checkedAt time.Time
+}
+
+// apiBudgetPeriod is the day or month spend is currently counted for
+type apiBudgetPeriod struct {
+	name  string // daily or monthly
+	date  string
+	start time.Time
+	ttl   time.Duration // keeps the counter a little past the period's end
+}
+
+// APIBudgetAlert tells admins a service degraded or was blocked
+type APIBudgetAlert struct {
+	Service string    `json:"service"`
+	Period  string    `json:"period"` // daily or monthly
+	State   string    `json:"state"`  // degraded or blocked
+	Spend   float64   `json:"spend"`
+	Limit   float64   `json:"limit"`
+	At      time.Time `json:"at"`
+}
+
+// APIBudgetStatus is a service's current spend against its budget
+type APIBudgetStatus struct {
+	Service string         `json:"service"`
+	State   string         `json:"state"` // ok, degraded or blocked
+	Daily   APIBudgetSpend `json:"daily"`
+	Monthly APIBudgetSpend `json:"monthly"`
+}
+
+type APIBudgetSpend struct {
+	Spend   float64 `json:"spend"`
+	Limit   float64 `json:"limit"`   // 0 means no limit
+	Percent float64 `json:"percent"` // of the limit used
+}
+
+// NewAPIBudgetGuard creates a budget guard for the services in cfg
+func NewAPIBudgetGuard(store APIBudgetStore, logRepo repositories.APIRequestLogRepository, userRepo repositories.UserRepository, cfg config.BudgetConfig) *APIBudgetGuard {
+	return &APIBudgetGuard{
+		store:    store,
+		logRepo:  logRepo,
+		userRepo: userRepo,
+		config:   cfg,
+		client:   &http.Client{Timeout: apiBudgetAlertTimeout},
+		states:   make(map[string]apiBudgetState),
+	}
+}
+
+func apiBudgetPeriods(now time.Time) []apiBudgetPeriod {
+	year, month, day := now.Date()
+	return []apiBudgetPeriod{
+		{
+			name:  "daily",
+			date:  now.Format("2006-01-02"),
+			start: time.Date(year, month, day, 0, 0, 0, 0, now.Location()),
+			ttl:   48 * time.Hour,
+		},
+		{
+			name:  "monthly",
+			date:  now.Format("2006-01"),
+			start: time.Date(year, month, 1, 0, 0, 0, 0, now.Location()),
+			ttl:   32 * 24 * time.Hour,
+		},
+	}
+}
+
+func apiBudgetLimit(budget config.ServiceBudget, period string) float64 {
+	if period == "daily" {
+		return budget.Daily
+	}
+	return budget.Monthly
+}
+
+func apiBudgetKeys(service string, periods []apiBudgetPeriod) []string {
+	keys := make([]string, len(periods))
+	for i, p := range periods {
+		keys[i] = cache.APIBudgetSpendKey(service, p.name, p.date)
+	}
+	return keys
+}
+
+// State returns the service's budget state. Services without a budget are
+// always ok, and so is every service while the counters can't be read:
+// a Redis outage shouldn't take search down with it.
+func (g *APIBudgetGuard) State(ctx context.Context, service string) string {
+	budget, ok := g.config.Services[service]
+	if !ok {
+		return APIBudgetOK
+	}
+
+	g.mu.Lock()
+	cached, ok := g.states[service]
+	g.mu.Unlock()
+	if ok && time.Since(cached.checkedAt) < apiBudgetStateTTL {
+		return cached.state
+	}
+
+	periods := apiBudgetPeriods(time.Now())
+	spend, err := g.store.GetSpend(ctx, apiBudgetKeys(service, periods)...)
+	if err != nil {
+		logger.WarnContext(ctx, "API budget check failed",
+			"service", service,
+			"error", err.Error(),
+		)
+		return APIBudgetOK
+	}
+
+	state := g.evaluate(budget, periods, spend)
+	g.setState(service, state)
+	return state
+}
+
+func (g *APIBudgetGuard) evaluate(budget config.ServiceBudget, periods []apiBudgetPeriod, spend []float64) string {
+	state := APIBudgetOK
+	for i, p := range periods {
+		limit := apiBudgetLimit(budget, p.name)
+		if limit <= 0 {
+			continue
+		}
+		if spend[i] >= limit {
+			return APIBudgetBlocked
+		}
+		if spend[i] >= limit*g.config.SoftPercent/100 {
+			state = APIBudgetDegraded
+		}
+	}
+	return state
+}
+
+func (g *APIBudgetGuard) setState(service, state string) {
+	g.mu.Lock()
+	g.states[service] = apiBudgetState{state: state, checkedAt: time.Now()}
+	g.mu.Unlock()
+}
+
+// Record adds the estimated cost of one call to the service's spend
+func (g *APIBudgetGuard) Record(ctx context.Context, service string, cost float64) {
+	budget, ok := g.config.Services[service]
+	if !ok || cost <= 0 {
+		return
+	}
+
+	periods := apiBudgetPeriods(time.Now())
+	keys := apiBudgetKeys(service, periods)
+	spend := make([]float64, len(periods))
+	for i, p := range periods {
+		after, err := g.store.AddSpend(ctx, keys[i], cost, p.ttl)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to record API spend",
+				"service", service,
+				"error", err.Error(),
+			)
+			return
+		}
+		spend[i] = after
+		g.checkThresholds(ctx, service, p.name, apiBudgetLimit(budget, p.name), after-cost, after)
+	}
+
+	g.setState(service, g.evaluate(budget, periods, spend))
+}
+
+// Sync raises the counters to the spend recorded in api_request_logs, so
+// budgets hold when Redis lost its counters. Logs are written in batches, so
+// the counters are usually ahead and left alone.
+func (g *APIBudgetGuard) Sync(ctx context.Context) error {
+	if len(g.config.Services) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, p := range apiBudgetPeriods(now) {
+		costs, err := g.logRepo.GetServiceCosts(ctx, p.start, now)
+		if err != nil {
+			return err
+		}
+		for _, cost := range costs {
+			budget, ok := g.config.Services[cost.ServiceName]
+			if !ok || cost.TotalCost <= 0 {
+				continue
+			}
+			key := cache.APIBudgetSpendKey(cost.ServiceName, p.name, p.date)
+			before, after, err := g.store.RaiseSpend(ctx, key, cost.TotalCost, p.ttl)
+			if err != nil {
+				return err
+			}
+			g.checkThresholds(ctx, cost.ServiceName, p.name, apiBudgetLimit(budget, p.name), before, after)
+		}
+	}
+
+	// The next check reads the synced counters
+	g.mu.Lock()
+	g.states = make(map[string]apiBudgetState)
+	g.mu.Unlock()
+	return nil
+}
+
+// Status returns the current spend of every service with a budget
+func (g *APIBudgetGuard) Status(ctx context.Context) ([]APIBudgetStatus, error) {
+	periods := apiBudgetPeriods(time.Now())
+	statuses := make([]APIBudgetStatus, 0, len(g.config.Services))
+	for service, budget := range g.config.Services {
+		spend, err := g.store.GetSpend(ctx, apiBudgetKeys(service, periods)...)
+		if err != nil {
+			return nil, err
+		}
+
+		state := g.evaluate(budget, periods, spend)
+		g.setState(service, state)
+
+		status := APIBudgetStatus{Service: service, State: state}
+		for i, p := range periods {
+			usage := APIBudgetSpend{Spend: spend[i], Limit: apiBudgetLimit(budget, p.name)}
+			if usage.Limit > 0 {
+				usage.Percent = spend[i] / usage.Limit * 100
+			}
+			if p.name == "daily" {
+				status.Daily = usage
+			} else {
+				status.Monthly = usage
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Service < statuses[j].Service
+	})
+	return statuses, nil
+}
+
+// checkThresholds alerts when spend went from before to after across the
+// soft threshold or the limit. Counters change atomically, so only the
+// call that crossed it sees it.
+func (g *APIBudgetGuard) checkThresholds(ctx context.Context, service, period string, limit, before, after float64) {
+	if limit <= 0 {
+		return
+	}
+
+	state := ""
+	soft := limit * g.config.SoftPercent / 100
+	switch {
+	case before < limit && after >= limit:
+		state = APIBudgetBlocked
+	case before < soft && after >= soft:
+		state = APIBudgetDegraded
+	default:
+		return
+	}
+
+	alert := APIBudgetAlert{
+		Service: service,
+		Period:  period,
+		State:   state,
+		Spend:   after,
+		Limit:   limit,
+		At:      time.Now(),
+	}
+
+	logger.WarnContext(ctx, "API budget threshold crossed",
+		"service", service,
+		"period", period,
+		"state", state,
+		"spend", after,
+		"limit", limit,
+	)
+
+	// The request context (a fasthttp.RequestCtx) is recycled once the
+	// handler returns, so only the request ID is carried over
+	alertCtx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), logger.GetRequestID(ctx)), apiBudgetAlertTimeout)
+	go func() {
+		defer cancel()
+		g.notifyAdmins(alertCtx, alert)
+		g.postWebhook(alertCtx, alert)
+	}()
+}
+
+// notifyAdmins sends the alert over WebSocket to everyone allowed to see API stats
+func (g *APIBudgetGuard) notifyAdmins(ctx context.Context, alert APIBudgetAlert) {
+	ids, err := g.userRepo.ListIDsWithPermission(ctx, models.PermStatsRead)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to list admins for API budget alert", "error", err.Error())
+		return
+	}
+	for _, id := range ids {
+		websocket.Manager.BroadcastToUser(id, "api_budget_alert", alert)
+	}
+}
+
+// postWebhook sends the alert to the configured webhook. "text" makes it
+// readable in Slack-style incoming webhooks; the other fields are the alert.
+func (g *APIBudgetGuard) postWebhook(ctx context.Context, alert APIBudgetAlert) {
+	if g.config.AlertWebhookURL == "" {
+		return
+	}
+
+	effect := "answering from cache and database only"
+	if alert.State == APIBudgetBlocked {
+		effect = "blocked until the period ends"
+	}
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+		APIBudgetAlert
+	}{
+		Text:           fmt.Sprintf("%s %s spend $%.2f of $%.2f budget: %s", alert.Service, alert.Period, alert.Spend, alert.Limit, effect),
+		APIBudgetAlert: alert,
+	})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.config.AlertWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		logger.WarnContext(ctx, "Invalid API budget webhook URL", "error", err.Error())
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		logger.WarnContext(ctx, "API budget webhook failed", "error", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		logger.WarnContext(ctx, "API budget webhook failed", "status", resp.StatusCode)
+	}
+}
@@ -10,20 +10,26 @@ import (
 
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
 )
 
 // APILoggerService handles logging of external API requests
 type APILoggerService struct {
 	repo    repositories.APIRequestLogRepository
+	budget  *APIBudgetGuard
 	buffer  []*models.APIRequestLog
 	mu      sync.Mutex
 	maxSize int
 }
 
-// NewAPILoggerService creates a new API logger service
-func NewAPILoggerService(repo repositories.APIRequestLogRepository) *APILoggerService {
+// NewAPILoggerService creates a new API logger service. The costs of API
+// calls count against budget; nil disables budgets.
+func NewAPILoggerService(repo repositories.APIRequestLogRepository, budget *APIBudgetGuard) *APILoggerService {
 	service := &APILoggerService{
 		repo:    repo,
+		budget:  budget,
 		buffer:  make([]*models.APIRequestLog, 0, 100),
 		maxSize: 100, // Flush every 100 logs
 	}
@@ -34,9 +40,13 @@ func NewAPILoggerService(repo repositories.APIRequestLogRepository) *APILoggerSe
 	return service
 }
 
-// LogRequest logs an API request
+// LogRequest logs an API request. Requests made with an API key are
+// attributed to the key.
 func (s *APILoggerService) LogRequest(ctx context.Context, log *models.APIRequestLog) {
 	log.CreatedAt = time.Now()
+	if apiKey := utils.GetAPIKeyFromContext(ctx); apiKey != nil && log.APIKeyID == nil {
+		log.APIKeyID = &apiKey.ID
+	}
 
 	s.mu.Lock()
 	s.buffer = append(s.buffer, log)
@@ -48,6 +58,13 @@ func (s *APILoggerService) LogRequest(ctx context.Context, log *models.APIReques
 	}
 }
 
+// BufferSize returns how many logs are waiting to be flushed
+func (s *APILoggerService) BufferSize() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buffer)
+}
+
 // LogAPICall logs an external API call (source = "api")
 func (s *APILoggerService) LogAPICall(ctx context.Context, serviceName, endpoint string, params interface{}, cost float64, durationMs int, userID *uuid.UUID, success bool, errMsg string) {
 	paramsJSON := ""
@@ -70,6 +87,80 @@ func (s *APILoggerService) LogAPICall(ctx context.Context, serviceName, endpoint
 	}
 
 	s.LogRequest(ctx, log)
+
+	if s.budget != nil {
+		s.budget.Record(ctx, serviceName, cost)
+	}
+}
+
+// LogLLMCall logs an LLM call with its token usage. The feature goes in
+// Endpoint; estimated marks token counts guessed from the text because the
+// provider didn't report usage.
+func (s *APILoggerService) LogLLMCall(ctx context.Context, provider, feature, model string, promptTokens, completionTokens int, estimated bool, cost float64, durationMs int, success bool, errMsg string) {
+	if feature == "" {
+		feature = "unknown"
+	}
+	paramsJSON := ""
+	if estimated {
+		paramsJSON = `{"estimatedTokens":true}`
+	}
+
+	log := &models.APIRequestLog{
+		ServiceName:      provider,
+		Endpoint:         feature,
+		Source:           "api",
+		RequestParams:    paramsJSON,
+		EstimatedCost:    cost,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		DurationMs:       durationMs,
+		UserID:           utils.GetUserIDFromContext(ctx),
+		Success:          success,
+		ErrorMessage:     errMsg,
+	}
+
+	s.LogRequest(ctx, log)
+
+	if s.budget != nil {
+		s.budget.Record(ctx, provider, cost)
+	}
+}
+
+// CheckBudget fails once serviceName is blocked by its budget. Services
+// backed by it check this before answering at all, even from cache.
+func (s *APILoggerService) CheckBudget(ctx context.Context, serviceName string) error {
+	if s.budget == nil {
+		return nil
+	}
+	if s.budget.State(ctx, serviceName) == APIBudgetBlocked {
+		return services.ErrAPIBudgetExhausted
+	}
+	return nil
+}
+
+// CheckCallBudget fails once serviceName is degraded or blocked. It's checked
+// right before calling the API, after cache and database had no answer.
+func (s *APILoggerService) CheckCallBudget(ctx context.Context, serviceName string) error {
+	if s.budget == nil {
+		return nil
+	}
+	switch s.budget.State(ctx, serviceName) {
+	case APIBudgetBlocked:
+		return services.ErrAPIBudgetExhausted
+	case APIBudgetDegraded:
+		return services.ErrAPIBudgetDegraded
+	}
+	return nil
+}
+
+// BudgetDegraded reports whether serviceName answers only from cache and the
+// database, in which case stale stored data is better than no answer
+func (s *APILoggerService) BudgetDegraded(ctx context.Context, serviceName string) bool {
+	if s.budget == nil {
+		return false
+	}
+	return s.budget.State(ctx, serviceName) != APIBudgetOK
 }
 
 // LogCacheHit logs a cache hit (source = "cache")
@@ -209,6 +300,14 @@ func (s *APILoggerService) GetSummary(ctx context.Context, days int) (*APISummar
 		cacheHits += stat.CacheHits
 	}
 
+	// Budgets come from Redis; the summary is still useful without them
+	var budgets []APIBudgetStatus
+	if s.budget != nil {
+		if budgets, err = s.budget.Status(ctx); err != nil {
+			logger.WarnContext(ctx, "Failed to read API budgets", "error", err.Error())
+		}
+	}
+
 	return &APISummary{
 		Period:        days,
 		TotalRequests: totalRequests,
@@ -219,9 +318,53 @@ func (s *APILoggerService) GetSummary(ctx context.Context, days int) (*APISummar
 		CostSaved:     totalCost * (cacheHitRate / 100), // Estimated savings from cache
 		ServiceStats:  stats,
 		ServiceCosts:  costs,
+		Budgets:       budgets,
 	}, nil
 }
 
+// GetLLMUsage returns token usage and cost of LLM calls per feature and model
+func (s *APILoggerService) GetLLMUsage(ctx context.Context, days int) (*LLMUsageSummary, error) {
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+
+	stats, err := s.repo.GetLLMUsage(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &LLMUsageSummary{
+		Period:   days,
+		Features: []LLMFeatureUsage{},
+		Models:   stats,
+	}
+	if summary.Models == nil {
+		summary.Models = []models.LLMUsageStats{}
+	}
+
+	// Index into summary.Features: pointers would go stale when append grows it
+	byFeature := make(map[string]int)
+	for _, stat := range stats {
+		idx, ok := byFeature[stat.Feature]
+		if !ok {
+			summary.Features = append(summary.Features, LLMFeatureUsage{Feature: stat.Feature})
+			idx = len(summary.Features) - 1
+			byFeature[stat.Feature] = idx
+		}
+		feature := &summary.Features[idx]
+		feature.TotalRequests += stat.TotalRequests
+		feature.PromptTokens += stat.PromptTokens
+		feature.CompletionTokens += stat.CompletionTokens
+		feature.TotalCost += stat.TotalCost
+
+		summary.TotalRequests += stat.TotalRequests
+		summary.PromptTokens += stat.PromptTokens
+		summary.CompletionTokens += stat.CompletionTokens
+		summary.TotalCost += stat.TotalCost
+	}
+
+	return summary, nil
+}
+
 // CleanupOldLogs removes logs older than specified days
 func (s *APILoggerService) CleanupOldLogs(ctx context.Context, days int) (int64, error) {
 	before := time.Now().AddDate(0, 0, -days)
@@ -239,4 +382,25 @@ type APISummary struct {
 	CostSaved     float64                `json:"costSaved"`
 	ServiceStats  []models.APIRequestStats `json:"serviceStats"`
 	ServiceCosts  []models.ServiceCost     `json:"serviceCosts"`
+	Budgets       []APIBudgetStatus        `json:"budgets"` // current day and month, whatever the period
+}
+
+// LLMUsageSummary represents LLM token usage and cost over a period
+type LLMUsageSummary struct {
+	Period           int                    `json:"period"` // Days
+	TotalRequests    int64                  `json:"totalRequests"`
+	PromptTokens     int64                  `json:"promptTokens"`
+	CompletionTokens int64                  `json:"completionTokens"`
+	TotalCost        float64                `json:"totalCost"`
+	Features         []LLMFeatureUsage      `json:"features"`
+	Models           []models.LLMUsageStats `json:"models"` // per feature and model, most expensive first
+}
+
+// LLMFeatureUsage represents LLM usage of one feature across models
+type LLMFeatureUsage struct {
+	Feature          string  `json:"feature"`
+	TotalRequests    int64   `json:"totalRequests"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalCost        float64 `json:"totalCost"`
 }
@@ -19,16 +19,18 @@ import (
 )
 
 type FileServiceImpl struct {
-	fileRepo repositories.FileRepository
-	userRepo repositories.UserRepository
-	storage  storage.R2Storage
+	fileRepo    repositories.FileRepository
+	userRepo    repositories.UserRepository
+	storage     storage.R2Storage
+	permissions services.PermissionService
 }
 
-func NewFileService(fileRepo repositories.FileRepository, userRepo repositories.UserRepository, storage storage.R2Storage) services.FileService {
+func NewFileService(fileRepo repositories.FileRepository, userRepo repositories.UserRepository, storage storage.R2Storage, permissions services.PermissionService) services.FileService {
 	return &FileServiceImpl{
-		fileRepo: fileRepo,
-		userRepo: userRepo,
-		storage:  storage,
+		fileRepo:    fileRepo,
+		userRepo:    userRepo,
+		storage:     storage,
+		permissions: permissions,
 	}
 }
 
@@ -108,10 +110,14 @@ func (s *FileServiceImpl) UploadFile(ctx context.Context, userID uuid.UUID, file
 	return fileModel, nil
 }
 
-func (s *FileServiceImpl) GetFile(ctx context.Context, fileID uuid.UUID) (*models.File, error) {
+func (s *FileServiceImpl) GetFile(ctx context.Context, actor services.Actor, fileID uuid.UUID) (*models.File, error) {
 	file, err := s.fileRepo.GetByID(ctx, fileID)
 	if err != nil {
-		return nil, errors.New("file not found")
+		return nil, services.ErrFileNotFound
+	}
+
+	if err := authorizeOwner(ctx, s.permissions, actor, file.UserID, models.PermFilesReadAny); err != nil {
+		return nil, err
 	}
 	return file, nil
 }
@@ -130,10 +136,14 @@ func (s *FileServiceImpl) GetUserFiles(ctx context.Context, userID uuid.UUID, of
 	return files, count, nil
 }
 
-func (s *FileServiceImpl) DeleteFile(ctx context.Context, fileID uuid.UUID) error {
+func (s *FileServiceImpl) DeleteFile(ctx context.Context, actor services.Actor, fileID uuid.UUID) error {
 	file, err := s.fileRepo.GetByID(ctx, fileID)
 	if err != nil {
-		return errors.New("file not found")
+		return services.ErrFileNotFound
+	}
+
+	if err := authorizeOwner(ctx, s.permissions, actor, file.UserID, models.PermFilesDeleteAny); err != nil {
+		return err
 	}
 
 	err = s.storage.DeleteFile(file.CDNPath)
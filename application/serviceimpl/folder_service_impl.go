@@ -52,12 +52,12 @@ func (s *FolderServiceImpl) CreateFolder(ctx context.Context, userID uuid.UUID,
 func (s *FolderServiceImpl) GetFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) (*dto.FolderDetailResponse, error) {
 	folder, err := s.folderRepo.GetByIDWithItems(ctx, folderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	// Check ownership or public access
 	if folder.UserID != userID && !folder.IsPublic {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	return dto.FolderToFolderDetailResponse(folder), nil
@@ -101,11 +101,11 @@ func (s *FolderServiceImpl) GetFolders(ctx context.Context, userID uuid.UUID, re
 func (s *FolderServiceImpl) UpdateFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, req *dto.UpdateFolderRequest) (*dto.FolderResponse, error) {
 	folder, err := s.folderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	if req.Name != "" {
@@ -132,11 +132,11 @@ func (s *FolderServiceImpl) UpdateFolder(ctx context.Context, userID uuid.UUID,
 func (s *FolderServiceImpl) DeleteFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID) error {
 	folder, err := s.folderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return errors.New("folder not found")
+		return services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return errors.New("unauthorized")
+		return services.ErrForbidden
 	}
 
 	return s.folderRepo.Delete(ctx, folderID)
@@ -145,11 +145,11 @@ func (s *FolderServiceImpl) DeleteFolder(ctx context.Context, userID uuid.UUID,
 func (s *FolderServiceImpl) AddItemToFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, req *dto.AddFolderItemRequest) (*dto.FolderItemResponse, error) {
 	folder, err := s.folderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	// Check if item already exists
@@ -178,11 +178,11 @@ func (s *FolderServiceImpl) AddItemToFolder(ctx context.Context, userID uuid.UUI
 func (s *FolderServiceImpl) GetFolderItems(ctx context.Context, userID uuid.UUID, req *dto.GetFolderItemsRequest) (*dto.FolderItemListResponse, error) {
 	folder, err := s.folderRepo.GetByID(ctx, req.FolderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID && !folder.IsPublic {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	if req.Page == 0 {
@@ -227,16 +227,16 @@ func (s *FolderServiceImpl) GetFolderItems(ctx context.Context, userID uuid.UUID
 func (s *FolderServiceImpl) UpdateFolderItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID, req *dto.UpdateFolderItemRequest) (*dto.FolderItemResponse, error) {
 	item, err := s.folderItemRepo.GetByID(ctx, itemID)
 	if err != nil {
-		return nil, errors.New("item not found")
+		return nil, services.ErrFolderItemNotFound
 	}
 
 	folder, err := s.folderRepo.GetByID(ctx, item.FolderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	if req.Title != "" {
@@ -259,16 +259,16 @@ func (s *FolderServiceImpl) UpdateFolderItem(ctx context.Context, userID uuid.UU
 func (s *FolderServiceImpl) RemoveItemFromFolder(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error {
 	item, err := s.folderItemRepo.GetByID(ctx, itemID)
 	if err != nil {
-		return errors.New("item not found")
+		return services.ErrFolderItemNotFound
 	}
 
 	folder, err := s.folderRepo.GetByID(ctx, item.FolderID)
 	if err != nil {
-		return errors.New("folder not found")
+		return services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return errors.New("unauthorized")
+		return services.ErrForbidden
 	}
 
 	if err := s.folderItemRepo.Delete(ctx, itemID); err != nil {
@@ -284,11 +284,11 @@ func (s *FolderServiceImpl) RemoveItemFromFolder(ctx context.Context, userID uui
 func (s *FolderServiceImpl) ReorderFolderItems(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, req *dto.ReorderFolderItemsRequest) error {
 	folder, err := s.folderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return errors.New("folder not found")
+		return services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return errors.New("unauthorized")
+		return services.ErrForbidden
 	}
 
 	itemOrders := make(map[uuid.UUID]int)
@@ -302,11 +302,11 @@ func (s *FolderServiceImpl) ReorderFolderItems(ctx context.Context, userID uuid.
 func (s *FolderServiceImpl) ShareFolder(ctx context.Context, userID uuid.UUID, folderID uuid.UUID, req *dto.ShareFolderRequest) (*dto.FolderShareResponse, error) {
 	folder, err := s.folderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	if req.IsPublic != nil {
@@ -333,7 +333,7 @@ func (s *FolderServiceImpl) ShareFolder(ctx context.Context, userID uuid.UUID, f
 func (s *FolderServiceImpl) GetPublicFolder(ctx context.Context, folderID uuid.UUID) (*dto.FolderDetailResponse, error) {
 	folder, err := s.folderRepo.GetByIDWithItems(ctx, folderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if !folder.IsPublic {
@@ -430,11 +430,11 @@ func (s *FolderServiceImpl) UploadItemToFolder(ctx context.Context, userID uuid.
 	// Check folder ownership
 	folder, err := s.folderRepo.GetByID(ctx, folderID)
 	if err != nil {
-		return nil, errors.New("folder not found")
+		return nil, services.ErrFolderNotFound
 	}
 
 	if folder.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, services.ErrForbidden
 	}
 
 	// Check if R2 storage is available
@@ -0,0 +1,143 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
+)
+
+// appVersion is reported by the health endpoints
+const appVersion = "1.0.0"
+
+type HealthServiceImpl struct {
+	probes    []services.HealthProbe
+	config    config.HealthConfig
+	startedAt time.Time
+
+	mu     sync.Mutex
+	cached map[string]dto.HealthCheckResult // last result of probes with CacheFor
+}
+
+func NewHealthService(probes []services.HealthProbe, cfg config.HealthConfig) services.HealthService {
+	return &HealthServiceImpl{
+		probes:    probes,
+		config:    cfg,
+		startedAt: time.Now(),
+		cached:    make(map[string]dto.HealthCheckResult),
+	}
+}
+
+func (s *HealthServiceImpl) Liveness(ctx context.Context) *dto.HealthReport {
+	return s.report(dto.HealthOK, nil)
+}
+
+func (s *HealthServiceImpl) Readiness(ctx context.Context) *dto.HealthReport {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]dto.HealthCheckResult, len(s.probes))
+	)
+
+	for _, probe := range s.probes {
+		wg.Add(1)
+		go func(probe services.HealthProbe) {
+			defer wg.Done()
+			result := s.run(ctx, probe)
+			mu.Lock()
+			results[probe.Name] = result
+			mu.Unlock()
+		}(probe)
+	}
+	wg.Wait()
+
+	status := dto.HealthOK
+	for name, result := range results {
+		switch {
+		case result.Status == dto.HealthUnhealthy && result.Critical:
+			status = dto.HealthUnhealthy
+		case result.Status != dto.HealthOK && status == dto.HealthOK:
+			status = dto.HealthDegraded
+		}
+		if result.Status != dto.HealthOK && !result.Cached {
+			logger.WarnContext(ctx, "Readiness check not ok",
+				"check", name,
+				"status", result.Status,
+				"error", result.Cause,
+				"latency_ms", result.LatencyMs,
+			)
+		}
+	}
+
+	return s.report(status, results)
+}
+
+// run runs one probe, or reuses its last result while that is fresh
+func (s *HealthServiceImpl) run(ctx context.Context, probe services.HealthProbe) dto.HealthCheckResult {
+	if probe.CacheFor > 0 {
+		s.mu.Lock()
+		last, ok := s.cached[probe.Name]
+		s.mu.Unlock()
+		if ok && time.Since(last.CheckedAt) < probe.CacheFor {
+			last.Cached = true
+			return last
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, s.config.CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := probe.Check(checkCtx)
+	latency := time.Since(start)
+
+	result := dto.HealthCheckResult{
+		Status:    dto.HealthOK,
+		Critical:  probe.Critical,
+		LatencyMs: latency.Milliseconds(),
+		Detail:    detail,
+		CheckedAt: start,
+	}
+	switch {
+	case err != nil && probe.Critical:
+		result.Status = dto.HealthUnhealthy
+		result.Error, result.Cause = healthCheckError(err), err
+	case err != nil:
+		// The API still serves what doesn't need this dependency
+		result.Status = dto.HealthDegraded
+		result.Error, result.Cause = healthCheckError(err), err
+	case latency > s.config.SlowThreshold:
+		result.Status = dto.HealthDegraded
+	}
+
+	if probe.CacheFor > 0 {
+		s.mu.Lock()
+		s.cached[probe.Name] = result
+		s.mu.Unlock()
+	}
+	return result
+}
+
+// healthCheckError is what callers of /readyz see of a failed check: error
+// texts can carry hosts, addresses and credentials, so they are only logged
+func healthCheckError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "check timed out"
+	}
+	return "check failed"
+}
+
+func (s *HealthServiceImpl) report(status string, checks map[string]dto.HealthCheckResult) *dto.HealthReport {
+	return &dto.HealthReport{
+		Status:    status,
+		Version:   appVersion,
+		Timestamp: time.Now(),
+		Uptime:    time.Since(s.startedAt).Round(time.Second).String(),
+		Checks:    checks,
+	}
+}
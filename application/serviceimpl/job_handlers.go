@@ -0,0 +1,229 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+// JobRegistry holds the handlers jobs can name
+type JobRegistry struct {
+	handlers map[string]services.JobHandler
+}
+
+func NewJobRegistry(handlers ...services.JobHandler) *JobRegistry {
+	r := &JobRegistry{handlers: make(map[string]services.JobHandler, len(handlers))}
+	for _, h := range handlers {
+		r.handlers[h.Name()] = h
+	}
+	return r
+}
+
+func (r *JobRegistry) Get(name string) (services.JobHandler, bool) {
+	h, ok := r.handlers[name]
+	return h, ok
+}
+
+// Names lists the registered handler names, sorted
+func (r *JobRegistry) Names() []string {
+	names := make([]string, 0, len(r.handlers))
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeJobPayload decodes a JSON payload over the defaults already in v and
+// validates the result. Unknown fields are rejected so typos don't silently
+// fall back to defaults.
+func decodeJobPayload(payload string, v interface{}) error {
+	if strings.TrimSpace(payload) != "" {
+		decoder := json.NewDecoder(strings.NewReader(payload))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+	}
+	return utils.ValidateStruct(v)
+}
+
+// CacheWarmPayload lists what cache_warm fetches into the cache
+type CacheWarmPayload struct {
+	Queries  []string `json:"queries" validate:"dive,min=1,max=500"`
+	PlaceIDs []string `json:"placeIds" validate:"dive,min=1,max=255"`
+	Lang     string   `json:"lang" validate:"omitempty,len=2"`
+}
+
+// CacheWarmJob runs place searches and place details lookups so the first
+// users after a cache expiry don't pay for the Google calls
+type CacheWarmJob struct {
+	searchService services.SearchService
+}
+
+func NewCacheWarmJob(searchService services.SearchService) *CacheWarmJob {
+	return &CacheWarmJob{searchService: searchService}
+}
+
+func (j *CacheWarmJob) Name() string { return "cache_warm" }
+
+func (j *CacheWarmJob) ParsePayload(payload string) (interface{}, error) {
+	p := CacheWarmPayload{Lang: "th"}
+	if err := decodeJobPayload(payload, &p); err != nil {
+		return nil, err
+	}
+	if len(p.Queries) == 0 && len(p.PlaceIDs) == 0 {
+		return nil, errors.New("queries or placeIds is required")
+	}
+	return p, nil
+}
+
+func (j *CacheWarmJob) Run(ctx context.Context, payload interface{}) (string, error) {
+	p := payload.(CacheWarmPayload)
+
+	var errs []error
+	for _, query := range p.Queries {
+		// No user, so nothing goes into search history
+		_, err := j.searchService.SearchPlaces(ctx, uuid.Nil, &dto.PlaceSearchRequest{Query: query, Lang: p.Lang})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("query %q: %w", query, err))
+		}
+	}
+	for _, placeID := range p.PlaceIDs {
+		if _, err := j.searchService.GetPlaceDetails(ctx, placeID, 0, 0, p.Lang); err != nil {
+			errs = append(errs, fmt.Errorf("place %s: %w", placeID, err))
+		}
+	}
+
+	output := fmt.Sprintf("warmed %d queries and %d places, %d failed", len(p.Queries), len(p.PlaceIDs), len(errs))
+	return output, errors.Join(errs...)
+}
+
+// CleanupAPILogsPayload sets how long API request logs are kept
+type CleanupAPILogsPayload struct {
+	RetentionDays int `json:"retentionDays" validate:"min=1"`
+}
+
+// CleanupAPILogsJob deletes API request logs past their retention
+type CleanupAPILogsJob struct {
+	apiLogger *APILoggerService
+}
+
+func NewCleanupAPILogsJob(apiLogger *APILoggerService) *CleanupAPILogsJob {
+	return &CleanupAPILogsJob{apiLogger: apiLogger}
+}
+
+func (j *CleanupAPILogsJob) Name() string { return "cleanup_api_logs" }
+
+func (j *CleanupAPILogsJob) ParsePayload(payload string) (interface{}, error) {
+	p := CleanupAPILogsPayload{RetentionDays: 90}
+	if err := decodeJobPayload(payload, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (j *CleanupAPILogsJob) Run(ctx context.Context, payload interface{}) (string, error) {
+	p := payload.(CleanupAPILogsPayload)
+	deleted, err := j.apiLogger.CleanupOldLogs(ctx, p.RetentionDays)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d logs older than %d days", deleted, p.RetentionDays), nil
+}
+
+// RefreshPlaceAIContentPayload selects which place AI content is regenerated
+type RefreshPlaceAIContentPayload struct {
+	WithinDays int `json:"withinDays" validate:"min=0"` // also refresh content expiring this soon
+	Limit      int `json:"limit" validate:"min=1,max=500"`
+}
+
+// RefreshPlaceAIContentJob regenerates place AI content that has expired or is
+// about to, oldest first, so place pages keep serving it from the database
+type RefreshPlaceAIContentJob struct {
+	placeAIContentRepo repositories.PlaceAIContentRepository
+	searchService      services.SearchService
+}
+
+func NewRefreshPlaceAIContentJob(placeAIContentRepo repositories.PlaceAIContentRepository, searchService services.SearchService) *RefreshPlaceAIContentJob {
+	return &RefreshPlaceAIContentJob{
+		placeAIContentRepo: placeAIContentRepo,
+		searchService:      searchService,
+	}
+}
+
+func (j *RefreshPlaceAIContentJob) Name() string { return "refresh_place_ai_content" }
+
+func (j *RefreshPlaceAIContentJob) ParsePayload(payload string) (interface{}, error) {
+	p := RefreshPlaceAIContentPayload{WithinDays: 3, Limit: 20}
+	if err := decodeJobPayload(payload, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (j *RefreshPlaceAIContentJob) Run(ctx context.Context, payload interface{}) (string, error) {
+	p := payload.(RefreshPlaceAIContentPayload)
+
+	contents, err := j.placeAIContentRepo.ListExpiring(ctx, time.Now().AddDate(0, 0, p.WithinDays), p.Limit)
+	if err != nil {
+		return "", err
+	}
+
+	var errs []error
+	for _, content := range contents {
+		if err := j.searchService.RefreshPlaceAIContent(ctx, content.PlaceID, content.Language); err != nil {
+			errs = append(errs, fmt.Errorf("place %s (%s): %w", content.PlaceID, content.Language, err))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	output := fmt.Sprintf("refreshed %d of %d place contents", len(contents)-len(errs), len(contents))
+	return output, errors.Join(errs...)
+}
+
+// PurgeSearchHistoryPayload sets how long search history is kept
+type PurgeSearchHistoryPayload struct {
+	OlderThanDays int `json:"olderThanDays" validate:"min=1"`
+}
+
+// PurgeSearchHistoryJob deletes search history of all users past its retention
+type PurgeSearchHistoryJob struct {
+	searchHistoryRepo repositories.SearchHistoryRepository
+}
+
+func NewPurgeSearchHistoryJob(searchHistoryRepo repositories.SearchHistoryRepository) *PurgeSearchHistoryJob {
+	return &PurgeSearchHistoryJob{searchHistoryRepo: searchHistoryRepo}
+}
+
+func (j *PurgeSearchHistoryJob) Name() string { return "purge_search_history" }
+
+func (j *PurgeSearchHistoryJob) ParsePayload(payload string) (interface{}, error) {
+	p := PurgeSearchHistoryPayload{OlderThanDays: 180}
+	if err := decodeJobPayload(payload, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (j *PurgeSearchHistoryJob) Run(ctx context.Context, payload interface{}) (string, error) {
+	p := payload.(PurgeSearchHistoryPayload)
+	deleted, err := j.searchHistoryRepo.DeleteOlderThan(ctx, time.Now().AddDate(0, 0, -p.OlderThanDays))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d search history entries older than %d days", deleted, p.OlderThanDays), nil
+}
@@ -8,21 +8,34 @@ import (
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/metrics"
 	"gofiber-template/pkg/scheduler"
+	"gofiber-template/pkg/tracing"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type JobServiceImpl struct {
-	jobRepo   repositories.JobRepository
-	scheduler scheduler.EventScheduler
+	jobRepo    repositories.JobRepository
+	jobRunRepo repositories.JobRunRepository
+	scheduler  scheduler.EventScheduler
+	registry   *JobRegistry
+
+	mu      sync.Mutex
+	running map[uuid.UUID]bool // jobs with a run in progress on this instance
 }
 
-func NewJobService(jobRepo repositories.JobRepository, scheduler scheduler.EventScheduler) services.JobService {
+func NewJobService(jobRepo repositories.JobRepository, jobRunRepo repositories.JobRunRepository, scheduler scheduler.EventScheduler, registry *JobRegistry) services.JobService {
 	return &JobServiceImpl{
-		jobRepo:   jobRepo,
-		scheduler: scheduler,
+		jobRepo:    jobRepo,
+		jobRunRepo: jobRunRepo,
+		scheduler:  scheduler,
+		registry:   registry,
+		running:    make(map[uuid.UUID]bool),
 	}
 }
 
@@ -36,6 +49,13 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *dto.CreateJobReques
 		return nil, errors.New("job with this name already exists")
 	}
 
+	if req.Payload == "" {
+		req.Payload = "{}"
+	}
+	if err := s.validateHandler(req.Handler, req.Payload); err != nil {
+		return nil, err
+	}
+
 	nextRun, err := scheduler.GetNextRunTime(req.CronExpr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate next run time: %v", err)
@@ -44,6 +64,7 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *dto.CreateJobReques
 	job := &models.Job{
 		ID:        uuid.New(),
 		Name:      req.Name,
+		Handler:   req.Handler,
 		CronExpr:  req.CronExpr,
 		Payload:   req.Payload,
 		Status:    "active",
@@ -98,6 +119,14 @@ func (s *JobServiceImpl) UpdateJob(ctx context.Context, jobID uuid.UUID, req *dt
 	if req.Payload != "" {
 		job.Payload = req.Payload
 	}
+	if req.Handler != "" {
+		job.Handler = req.Handler
+	}
+	if req.Handler != "" || req.Payload != "" {
+		if err := s.validateHandler(job.Handler, job.Payload); err != nil {
+			return nil, err
+		}
+	}
 	if req.IsActive != job.IsActive {
 		job.IsActive = req.IsActive
 		needsReschedule = true
@@ -203,23 +232,163 @@ func (s *JobServiceImpl) StopJob(ctx context.Context, jobID uuid.UUID) error {
 	return s.jobRepo.Update(ctx, jobID, job)
 }
 
+// ExecuteJob runs a job on its schedule
 func (s *JobServiceImpl) ExecuteJob(ctx context.Context, job *models.Job) error {
-	now := time.Now()
+	current, run, err := s.beginRun(ctx, job.ID, models.JobTriggerSchedule)
+	if err != nil {
+		logger.WarnContext(ctx, "Scheduled job run skipped", "job_id", job.ID, "job", job.Name, "error", err)
+		return err
+	}
+	return s.finishRun(ctx, current, run)
+}
 
-	fmt.Printf("Executing job: %s at %s\n", job.Name, now.Format(time.RFC3339))
+func (s *JobServiceImpl) RunJob(ctx context.Context, jobID uuid.UUID) (*models.JobRun, error) {
+	job, run, err := s.beginRun(ctx, jobID, models.JobTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	started := *run
 
-	job.LastRun = &now
-	job.Status = "running"
+	go func() {
+		s.finishRun(tracing.Detach(ctx), job, run)
+		metrics.ObserveJobRun(job.ID.String(), time.Since(run.StartedAt))
+	}()
 
-	nextRun, err := scheduler.GetNextRunTime(job.CronExpr)
-	if err == nil {
-		job.NextRun = nextRun
+	return &started, nil
+}
+
+func (s *JobServiceImpl) ListJobRuns(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, int64, error) {
+	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+		return nil, 0, errors.New("job not found")
+	}
+
+	runs, err := s.jobRunRepo.ListByJobID(ctx, jobID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count, err := s.jobRunRepo.CountByJobID(ctx, jobID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return runs, count, nil
+}
+
+// beginRun claims a job against overlapping runs and records a running
+// job_runs row. The job is reloaded so the run sees its current handler and
+// payload rather than those it was scheduled with.
+func (s *JobServiceImpl) beginRun(ctx context.Context, jobID uuid.UUID, trigger string) (*models.Job, *models.JobRun, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, nil, errors.New("job not found")
+	}
+
+	s.mu.Lock()
+	if s.running[jobID] {
+		s.mu.Unlock()
+		return nil, nil, services.ErrJobRunning
 	}
+	s.running[jobID] = true
+	s.mu.Unlock()
 
+	now := time.Now()
+	run := &models.JobRun{
+		ID:        uuid.New(),
+		JobID:     job.ID,
+		Handler:   job.Handler,
+		Trigger:   trigger,
+		Status:    models.JobRunRunning,
+		StartedAt: now,
+		CreatedAt: now,
+	}
+	if err := s.jobRunRepo.Create(ctx, run); err != nil {
+		s.release(jobID)
+		return nil, nil, fmt.Errorf("failed to record job run: %v", err)
+	}
+
+	job.LastRun = &now
+	job.Status = "running"
 	s.jobRepo.Update(ctx, job.ID, job)
 
+	return job, run, nil
+}
+
+// finishRun runs the job's handler and records the outcome on the run and the job
+func (s *JobServiceImpl) finishRun(ctx context.Context, job *models.Job, run *models.JobRun) error {
+	defer s.release(job.ID)
+
+	output, runErr := s.runHandler(ctx, job)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Output = output
+	run.Status = models.JobRunSucceeded
 	job.Status = "completed"
+	if runErr != nil {
+		run.Status = models.JobRunFailed
+		run.Error = runErr.Error()
+		job.Status = "failed"
+		logger.ErrorContext(ctx, "Job run failed", "job_id", job.ID, "job", job.Name, "handler", job.Handler, "duration_ms", run.DurationMs, "error", runErr)
+	} else {
+		logger.InfoContext(ctx, "Job run succeeded", "job_id", job.ID, "job", job.Name, "handler", job.Handler, "duration_ms", run.DurationMs, "output", output)
+	}
+
+	if err := s.jobRunRepo.Update(ctx, run); err != nil {
+		logger.ErrorContext(ctx, "Failed to record job run result", "job_id", job.ID, "run_id", run.ID, "error", err)
+	}
+
+	if job.IsActive {
+		if nextRun, err := scheduler.GetNextRunTime(job.CronExpr); err == nil {
+			job.NextRun = nextRun
+		}
+	}
 	job.UpdatedAt = time.Now()
+	if err := s.jobRepo.Update(ctx, job.ID, job); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+// runHandler parses the job's payload for its handler and runs it
+func (s *JobServiceImpl) runHandler(ctx context.Context, job *models.Job) (output string, err error) {
+	handler, ok := s.registry.Get(job.Handler)
+	if !ok {
+		return "", fmt.Errorf("unknown job handler %q", job.Handler)
+	}
+	payload, err := handler.ParsePayload(job.Payload)
+	if err != nil {
+		return "", fmt.Errorf("invalid payload: %v", err)
+	}
 
-	return s.jobRepo.Update(ctx, job.ID, job)
+	ctx, span := tracing.Start(ctx, "job "+job.Handler)
+	defer span.End()
+	defer func() {
+		if r := recover(); r != nil {
+			err = tracing.RecordError(span, fmt.Errorf("job handler panicked: %v", r))
+		}
+	}()
+
+	output, err = handler.Run(ctx, payload)
+	return output, tracing.RecordError(span, err)
+}
+
+func (s *JobServiceImpl) release(jobID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.running, jobID)
+	s.mu.Unlock()
+}
+
+// validateHandler checks that a handler is registered and accepts the payload
+func (s *JobServiceImpl) validateHandler(name, payload string) error {
+	handler, ok := s.registry.Get(name)
+	if !ok {
+		return fmt.Errorf("unknown job handler %q, available: %s", name, strings.Join(s.registry.Names(), ", "))
+	}
+	if _, err := handler.ParsePayload(payload); err != nil {
+		return fmt.Errorf("invalid payload for %s: %v", name, err)
+	}
+	return nil
 }
@@ -0,0 +1,170 @@
+package serviceimpl
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/tracing"
+)
+
+// runesPerToken is a rough average used when a provider doesn't report usage
+const runesPerToken = 3
+
+// meteredLLM logs every call of the wrapped provider with its token usage
+// and price, and stops calls once the provider's API budget runs low
+type meteredLLM struct {
+	provider  services.LLMProvider
+	apiLogger *APILoggerService
+	prices    map[string]config.LLMPrice
+	unpriced  *sync.Map // models already warned about
+}
+
+// NewMeteredLLM wraps provider so each call is logged per feature. Prices are
+// matched by the longest model name prefix; models without a price cost 0.
+func NewMeteredLLM(provider services.LLMProvider, apiLogger *APILoggerService, prices map[string]config.LLMPrice) services.LLMProvider {
+	return &meteredLLM{
+		provider:  provider,
+		apiLogger: apiLogger,
+		prices:    prices,
+		unpriced:  &sync.Map{},
+	}
+}
+
+func (m *meteredLLM) Name() string {
+	return m.provider.Name()
+}
+
+func (m *meteredLLM) Chat(ctx context.Context, req *services.LLMRequest) (*services.LLMResponse, error) {
+	if m.apiLogger != nil {
+		if err := m.apiLogger.CheckCallBudget(ctx, m.Name()); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, span := m.startSpan(ctx, req)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := m.provider.Chat(ctx, req)
+	m.log(ctx, req, resp, err, start)
+	endLLMSpan(span, resp, err)
+	return resp, err
+}
+
+func (m *meteredLLM) ChatStream(ctx context.Context, req *services.LLMRequest, onDelta services.LLMStreamHandler) (*services.LLMResponse, error) {
+	if m.apiLogger != nil {
+		if err := m.apiLogger.CheckCallBudget(ctx, m.Name()); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, span := m.startSpan(ctx, req)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := m.provider.ChatStream(ctx, req, onDelta)
+	m.log(ctx, req, resp, err, start)
+	endLLMSpan(span, resp, err)
+	return resp, err
+}
+
+// startSpan starts the span of one LLM call, named after the feature
+func (m *meteredLLM) startSpan(ctx context.Context, req *services.LLMRequest) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "llm.chat "+req.Feature, trace.WithAttributes(
+		attribute.String("llm.provider", m.Name()),
+		attribute.String("llm.feature", req.Feature),
+	))
+}
+
+// endLLMSpan records the model and token usage of the call on its span
+func endLLMSpan(span trace.Span, resp *services.LLMResponse, err error) {
+	if resp != nil {
+		span.SetAttributes(
+			attribute.String("llm.model", resp.Model),
+			attribute.Int("llm.prompt_tokens", resp.PromptTokens),
+			attribute.Int("llm.completion_tokens", resp.CompletionTokens),
+		)
+	}
+	tracing.RecordError(span, err)
+}
+
+// log records the call. A failed stream still logs what was received, since
+// the tokens generated before the error are billed.
+func (m *meteredLLM) log(ctx context.Context, req *services.LLMRequest, resp *services.LLMResponse, err error, start time.Time) {
+	if m.apiLogger == nil {
+		return
+	}
+
+	model := req.Model
+	promptTokens, completionTokens := 0, 0
+	if resp != nil {
+		if resp.Model != "" {
+			model = resp.Model
+		}
+		promptTokens, completionTokens = resp.PromptTokens, resp.CompletionTokens
+	}
+
+	estimated := false
+	if promptTokens == 0 && completionTokens == 0 && (err == nil || (resp != nil && resp.Content != "")) {
+		estimated = true
+		for _, msg := range req.Messages {
+			promptTokens += estimateTokens(msg.Content)
+		}
+		if resp != nil {
+			completionTokens = estimateTokens(resp.Content)
+		}
+	}
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	m.apiLogger.LogLLMCall(ctx, m.Name(), req.Feature, model, promptTokens, completionTokens, estimated,
+		m.cost(ctx, model, promptTokens, completionTokens), int(time.Since(start).Milliseconds()), err == nil, errMsg)
+}
+
+// cost prices the tokens in USD
+func (m *meteredLLM) cost(ctx context.Context, model string, promptTokens, completionTokens int) float64 {
+	price, ok := lookupLLMPrice(m.prices, model)
+	if !ok {
+		if _, warned := m.unpriced.LoadOrStore(model, true); !warned {
+			logger.WarnContext(ctx, "No price configured for LLM model, logging its calls at no cost",
+				"provider", m.Name(),
+				"model", model,
+			)
+		}
+		return 0
+	}
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1_000_000
+}
+
+// lookupLLMPrice finds the price of the longest model name prefix, so dated
+// snapshots like gpt-4o-mini-2024-07-18 use the gpt-4o-mini price
+func lookupLLMPrice(prices map[string]config.LLMPrice, model string) (config.LLMPrice, bool) {
+	var best config.LLMPrice
+	bestLen := -1
+	for prefix, price := range prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > bestLen {
+			best, bestLen = price, len(prefix)
+		}
+	}
+	return best, bestLen >= 0
+}
+
+func estimateTokens(text string) int {
+	runes := utf8.RuneCountInString(text)
+	if runes == 0 {
+		return 0
+	}
+	return (runes + runesPerToken - 1) / runesPerToken
+}
@@ -0,0 +1,179 @@
+package serviceimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+)
+
+// permissionCacheTTL is how long grants are kept in memory. Changes made on
+// this instance apply at once; other replicas pick them up within the TTL.
+const permissionCacheTTL = time.Minute
+
+type PermissionServiceImpl struct {
+	permissionRepo repositories.PermissionRepository
+
+	mu       sync.RWMutex
+	grants   map[string]map[string]bool // role -> permission -> granted
+	loadedAt time.Time
+}
+
+func NewPermissionService(permissionRepo repositories.PermissionRepository) services.PermissionService {
+	return &PermissionServiceImpl{
+		permissionRepo: permissionRepo,
+	}
+}
+
+func (s *PermissionServiceImpl) HasPermission(ctx context.Context, role, permission string) bool {
+	grants, err := s.loadGrants(ctx)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to load permissions",
+			"role", role,
+			"permission", permission,
+			"error", err.Error(),
+		)
+		return false
+	}
+	return grants[role][permission]
+}
+
+func (s *PermissionServiceImpl) ListPermissions(ctx context.Context) (*dto.PermissionListResponse, error) {
+	permissions, err := s.permissionRepo.ListPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := s.permissionRepo.ListRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PermissionListResponse{
+		Permissions: make([]dto.PermissionResponse, 0, len(permissions)),
+		Roles:       []dto.RolePermissionsResponse{},
+	}
+	for _, p := range permissions {
+		resp.Permissions = append(resp.Permissions, dto.PermissionResponse{
+			Name:        p.Name,
+			Description: p.Description,
+		})
+	}
+
+	// Grants come sorted by role, so each role's permissions are adjacent
+	for _, g := range grants {
+		last := len(resp.Roles) - 1
+		if last < 0 || resp.Roles[last].Role != g.Role {
+			resp.Roles = append(resp.Roles, dto.RolePermissionsResponse{Role: g.Role})
+			last++
+		}
+		resp.Roles[last].Permissions = append(resp.Roles[last].Permissions, g.Permission)
+	}
+	return resp, nil
+}
+
+func (s *PermissionServiceImpl) GrantPermission(ctx context.Context, role, permission string) error {
+	if err := s.checkPermissionExists(ctx, permission); err != nil {
+		return err
+	}
+	if err := s.permissionRepo.Grant(ctx, role, permission); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	logger.InfoContext(ctx, "Permission granted",
+		"role", role,
+		"permission", permission,
+	)
+	return nil
+}
+
+func (s *PermissionServiceImpl) RevokePermission(ctx context.Context, role, permission string) error {
+	// Otherwise nobody would be able to grant it back without touching the database
+	if role == models.RoleAdmin && permission == models.PermRolesManage {
+		return services.ErrProtectedPermission
+	}
+
+	revoked, err := s.permissionRepo.Revoke(ctx, role, permission)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return services.ErrPermissionNotGranted
+	}
+	s.invalidate()
+
+	logger.InfoContext(ctx, "Permission revoked",
+		"role", role,
+		"permission", permission,
+	)
+	return nil
+}
+
+func (s *PermissionServiceImpl) checkPermissionExists(ctx context.Context, permission string) error {
+	permissions, err := s.permissionRepo.ListPermissions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		if p.Name == permission {
+			return nil
+		}
+	}
+	return services.ErrUnknownPermission
+}
+
+// loadGrants returns the cached grants, reloading them once they are older
+// than permissionCacheTTL. If a reload fails the stale grants keep being
+// used, so a database hiccup doesn't lock admins out.
+func (s *PermissionServiceImpl) loadGrants(ctx context.Context) (map[string]map[string]bool, error) {
+	s.mu.RLock()
+	grants, loadedAt := s.grants, s.loadedAt
+	s.mu.RUnlock()
+	if grants != nil && time.Since(loadedAt) < permissionCacheTTL {
+		return grants, nil
+	}
+
+	rows, err := s.permissionRepo.ListRolePermissions(ctx)
+	if err != nil {
+		if grants != nil {
+			logger.WarnContext(ctx, "Failed to reload permissions, using cached grants", "error", err.Error())
+			return grants, nil
+		}
+		return nil, err
+	}
+
+	fresh := make(map[string]map[string]bool)
+	for _, row := range rows {
+		if fresh[row.Role] == nil {
+			fresh[row.Role] = make(map[string]bool)
+		}
+		fresh[row.Role][row.Permission] = true
+	}
+
+	s.mu.Lock()
+	s.grants, s.loadedAt = fresh, time.Now()
+	s.mu.Unlock()
+	return fresh, nil
+}
+
+func (s *PermissionServiceImpl) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// authorizeOwner lets the owner of a resource through, and anyone else whose
+// role has anyPermission
+func authorizeOwner(ctx context.Context, permissions services.PermissionService, actor services.Actor, ownerID uuid.UUID, anyPermission string) error {
+	if actor.UserID == ownerID || permissions.HasPermission(ctx, actor.Role, anyPermission) {
+		return nil
+	}
+	return services.ErrForbidden
+}
@@ -0,0 +1,271 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/infrastructure/external/google"
+	"gofiber-template/pkg/logger"
+)
+
+// placeStaleAfter returns how long a stored place is served without asking Google
+func (s *SearchServiceImpl) placeStaleAfter() time.Duration {
+	if s.searchConfig.PlaceStaleAfter <= 0 {
+		return cache.TTLPlaceDetails
+	}
+	return s.searchConfig.PlaceStaleAfter
+}
+
+// searchPlacesLocal answers a text place search from the places table. ok is
+// false when fewer than LocalMinResults fresh places match, so Google is
+// needed. degraded (the Places budget allows no calls) answers with whatever
+// is stored.
+func (s *SearchServiceImpl) searchPlacesLocal(ctx context.Context, userID uuid.UUID, req *dto.PlaceSearchRequest, lang string, degraded bool) (*dto.PlaceSearchResponse, bool, error) {
+	filter := repositories.PlaceFilter{Text: req.Query, Type: req.PlaceType}
+	if !degraded {
+		freshSince := time.Now().Add(-s.placeStaleAfter())
+		filter.FetchedAfter = &freshSince
+	}
+
+	startTime := time.Now()
+	total, err := s.placeRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, false, err
+	}
+	minResults := s.searchConfig.LocalMinResults
+	if minResults < 1 {
+		minResults = google.PlacesPageSize
+	}
+	if total < int64(minResults) && !degraded {
+		return nil, false, nil
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 || pageSize > google.PlacesPageSize {
+		pageSize = google.PlacesPageSize
+	}
+	offset := (page - 1) * pageSize
+
+	places, err := s.placeRepo.Find(ctx, filter, offset, pageSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if s.apiLogger != nil {
+		s.apiLogger.LogDatabaseHit(ctx, "google_places", "text_search", &userID)
+	}
+
+	logger.InfoContext(ctx, "Text place search served from catalogue",
+		"query", req.Query,
+		"type", req.PlaceType,
+		"total", total,
+		"response_time_ms", time.Since(startTime).Milliseconds(),
+	)
+
+	results := s.toLocalPlaceResults(places, lang, 0, 0)
+	return &dto.PlaceSearchResponse{
+		Query:      req.Query,
+		Results:    results,
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+		HasMore:    int64(offset+len(results)) < total,
+		Source:     dto.PlaceSourceLocal,
+	}, true, nil
+}
+
+// storeSearchPlaces upserts Text/Nearby Search results into the places table.
+// Failures are logged only: the results are still served, the next lookup just misses.
+func (s *SearchServiceImpl) storeSearchPlaces(ctx context.Context, places []google.Place, lang string) {
+	if len(places) == 0 {
+		return
+	}
+
+	now := time.Now()
+	records := make([]*models.Place, 0, len(places))
+	for _, p := range places {
+		address := p.FormattedAddress
+		if address == "" {
+			address = p.Vicinity
+		}
+		var photos []models.PlacePhotoRef
+		for _, photo := range p.Photos {
+			photos = append(photos, models.PlacePhotoRef{Reference: photo.PhotoReference, Width: photo.Width, Height: photo.Height})
+		}
+		records = append(records, &models.Place{
+			PlaceID:       p.PlaceID,
+			Name:          p.Name,
+			Address:       address,
+			Lat:           p.Geometry.Location.Lat,
+			Lng:           p.Geometry.Location.Lng,
+			Types:         mustJSON(p.Types, "[]"),
+			Rating:        p.Rating,
+			ReviewCount:   p.UserRatingsTotal,
+			PriceLevel:    p.PriceLevel,
+			Photos:        mustJSON(photos, "[]"),
+			Localized:     mustJSON(map[string]models.PlaceLocalized{lang: {Name: p.Name, Address: address}}, "{}"),
+			LastFetchedAt: now,
+		})
+	}
+
+	if err := s.placeRepo.UpsertSearchResults(ctx, records, lang); err != nil {
+		logger.WarnContext(ctx, "Failed to store search places",
+			"count", len(records),
+			"language", lang,
+			"error", err.Error(),
+		)
+	}
+}
+
+// storePlaceDetails upserts a Place Details result into the places table
+func (s *SearchServiceImpl) storePlaceDetails(ctx context.Context, details google.PlaceDetails, lang string) {
+	now := time.Now()
+
+	var photos []models.PlacePhotoRef
+	for _, photo := range details.Photos {
+		photos = append(photos, models.PlacePhotoRef{Reference: photo.PhotoReference, Width: photo.Width, Height: photo.Height})
+	}
+
+	localized := models.PlaceLocalized{
+		Name:             details.Name,
+		Address:          details.FormattedAddress,
+		DetailsFetchedAt: &now,
+	}
+	if details.OpeningHours != nil {
+		localized.OpeningHours = details.OpeningHours.WeekdayText
+	}
+	for _, r := range details.Reviews {
+		localized.Reviews = append(localized.Reviews, models.PlaceReview{
+			Author:   r.AuthorName,
+			Rating:   r.Rating,
+			Text:     r.Text,
+			Time:     r.RelativeTimeDescription,
+			PhotoURL: r.ProfilePhotoURL,
+		})
+	}
+
+	place := &models.Place{
+		PlaceID:       details.PlaceID,
+		Name:          details.Name,
+		Address:       details.FormattedAddress,
+		Lat:           details.Geometry.Location.Lat,
+		Lng:           details.Geometry.Location.Lng,
+		Types:         mustJSON(details.Types, "[]"),
+		Rating:        details.Rating,
+		ReviewCount:   details.UserRatingsTotal,
+		PriceLevel:    details.PriceLevel,
+		Phone:         details.FormattedPhoneNumber,
+		Website:       details.Website,
+		GoogleMapsURL: details.URL,
+		Photos:        mustJSON(photos, "[]"),
+		Localized:     mustJSON(map[string]models.PlaceLocalized{lang: localized}, "{}"),
+		LastFetchedAt: now,
+	}
+
+	if err := s.placeRepo.UpsertDetails(ctx, place, lang); err != nil {
+		logger.WarnContext(ctx, "Failed to store place details",
+			"place_id", details.PlaceID,
+			"language", lang,
+			"error", err.Error(),
+		)
+	}
+}
+
+// getStoredPlaceDetails returns place details from the places table when
+// Place Details was fetched in lang within the staleness window, or at all
+// with allowStale
+func (s *SearchServiceImpl) getStoredPlaceDetails(ctx context.Context, placeID, lang string, allowStale bool) (*dto.PlaceDetailResponse, bool) {
+	place, err := s.placeRepo.GetByPlaceID(ctx, placeID)
+	if err != nil {
+		return nil, false
+	}
+
+	var localized map[string]models.PlaceLocalized
+	if json.Unmarshal(place.Localized, &localized) != nil {
+		return nil, false
+	}
+	entry, ok := localized[lang]
+	if !ok || entry.DetailsFetchedAt == nil || (!allowStale && time.Since(*entry.DetailsFetchedAt) > s.placeStaleAfter()) {
+		return nil, false
+	}
+
+	var types []string
+	_ = json.Unmarshal(place.Types, &types)
+	var photos []models.PlacePhotoRef
+	_ = json.Unmarshal(place.Photos, &photos)
+
+	response := &dto.PlaceDetailResponse{
+		PlaceID:          place.PlaceID,
+		Name:             entry.Name,
+		FormattedAddress: entry.Address,
+		Lat:              place.Lat,
+		Lng:              place.Lng,
+		Rating:           place.Rating,
+		ReviewCount:      place.ReviewCount,
+		PriceLevel:       place.PriceLevel,
+		Types:            types,
+		Phone:            place.Phone,
+		Website:          place.Website,
+		GoogleMapsURL:    place.GoogleMapsURL,
+		OpeningHours:     entry.OpeningHours,
+	}
+	for _, r := range entry.Reviews {
+		response.Reviews = append(response.Reviews, dto.PlaceReview{
+			Author:   r.Author,
+			Rating:   r.Rating,
+			Text:     r.Text,
+			Time:     r.Time,
+			PhotoURL: r.PhotoURL,
+		})
+	}
+	for _, p := range photos {
+		response.Photos = append(response.Photos, dto.PlacePhoto{
+			URL:    s.googlePlaces.GetPhotoURL(p.Reference, 800),
+			Width:  p.Width,
+			Height: p.Height,
+		})
+	}
+
+	return response, true
+}
+
+// storedPlaceWebsites returns the websites of stored places by place ID.
+// Places without one are left out; lookup failures only mean no websites.
+func (s *SearchServiceImpl) storedPlaceWebsites(ctx context.Context, placeIDs []string) map[string]string {
+	websites := make(map[string]string)
+	places, err := s.placeRepo.GetByPlaceIDs(ctx, placeIDs)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to load place websites",
+			"count", len(placeIDs),
+			"error", err.Error(),
+		)
+		return websites
+	}
+	for _, place := range places {
+		if place.Website != "" {
+			websites[place.PlaceID] = place.Website
+		}
+	}
+	return websites
+}
+
+// mustJSON marshals v for a jsonb column, falling back to empty when v is nil
+func mustJSON(v interface{}, empty string) datatypes.JSON {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return datatypes.JSON(empty)
+	}
+	return datatypes.JSON(data)
+}
@@ -0,0 +1,185 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/infrastructure/external/google"
+	"gofiber-template/pkg/logger"
+)
+
+// searchNearbyLocal answers a nearby search from the places table. ok is false
+// when fewer than LocalMinResults fresh places are in range, so Google is needed.
+// degraded (the Places budget allows no calls) answers with whatever is stored.
+func (s *SearchServiceImpl) searchNearbyLocal(ctx context.Context, req *dto.NearbyPlacesRequest, lang string, degraded bool) (*dto.PlaceSearchResponse, bool, error) {
+	filter := repositories.PlaceFilter{Type: req.PlaceType}
+	if !degraded {
+		freshSince := time.Now().Add(-s.placeStaleAfter())
+		filter.FetchedAfter = &freshSince
+	}
+
+	startTime := time.Now()
+	total, err := s.placeRepo.CountWithinRadius(ctx, req.Lat, req.Lng, req.Radius, filter)
+	if err != nil {
+		return nil, false, err
+	}
+	minResults := s.searchConfig.LocalMinResults
+	if minResults < 1 {
+		minResults = google.PlacesPageSize
+	}
+	if total < int64(minResults) && !degraded {
+		return nil, false, nil
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 || pageSize > google.PlacesPageSize {
+		pageSize = google.PlacesPageSize
+	}
+	offset := (page - 1) * pageSize
+
+	places, err := s.placeRepo.FindWithinRadius(ctx, req.Lat, req.Lng, req.Radius, filter, offset, pageSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if s.apiLogger != nil {
+		s.apiLogger.LogDatabaseHit(ctx, "google_places", "nearby_search", nil)
+	}
+
+	logger.InfoContext(ctx, "Nearby places served from catalogue",
+		"lat", req.Lat,
+		"lng", req.Lng,
+		"radius", req.Radius,
+		"type", req.PlaceType,
+		"total", total,
+		"response_time_ms", time.Since(startTime).Milliseconds(),
+	)
+
+	results := s.toLocalPlaceResults(places, lang, req.Lat, req.Lng)
+	return &dto.PlaceSearchResponse{
+		Query:      req.Keyword,
+		Results:    results,
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+		HasMore:    int64(offset+len(results)) < total,
+		Source:     dto.PlaceSourceLocal,
+	}, true, nil
+}
+
+func (s *SearchServiceImpl) SearchPlacesInBounds(ctx context.Context, req *dto.PlacesInBoundsRequest) (*dto.PlaceSearchResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+	lang := req.Lang
+	if lang == "" {
+		lang = "th"
+	}
+
+	bounds := repositories.GeoBounds{South: req.South, West: req.West, North: req.North, East: req.East}
+	filter := repositories.PlaceFilter{Type: req.PlaceType, MinRating: req.MinRating}
+
+	places, err := s.placeRepo.FindInBounds(ctx, bounds, filter, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := s.toLocalPlaceResults(places, lang, 0, 0)
+	return &dto.PlaceSearchResponse{
+		Results:    results,
+		TotalCount: int64(len(results)),
+		Page:       1,
+		PageSize:   req.Limit,
+		Source:     dto.PlaceSourceLocal,
+	}, nil
+}
+
+func (s *SearchServiceImpl) SearchNearestPlaces(ctx context.Context, req *dto.NearestPlacesRequest) (*dto.PlaceSearchResponse, error) {
+	if req.K == 0 {
+		req.K = 10
+	}
+	lang := req.Lang
+	if lang == "" {
+		lang = "th"
+	}
+
+	filter := repositories.PlaceFilter{Type: req.PlaceType, MinRating: req.MinRating}
+
+	places, err := s.placeRepo.FindNearest(ctx, req.Lat, req.Lng, req.K, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := s.toLocalPlaceResults(places, lang, req.Lat, req.Lng)
+	return &dto.PlaceSearchResponse{
+		Results:    results,
+		TotalCount: int64(len(results)),
+		Page:       1,
+		PageSize:   req.K,
+		Source:     dto.PlaceSourceLocal,
+	}, nil
+}
+
+// toLocalPlaceResults maps stored places, preferring names and addresses in lang
+func (s *SearchServiceImpl) toLocalPlaceResults(places []*models.Place, lang string, lat, lng float64) []dto.PlaceResult {
+	results := make([]dto.PlaceResult, 0, len(places))
+	for _, place := range places {
+		name, address := place.Name, place.Address
+		var localized map[string]models.PlaceLocalized
+		if json.Unmarshal(place.Localized, &localized) == nil {
+			if entry, ok := localized[lang]; ok {
+				name, address = entry.Name, entry.Address
+			}
+		}
+
+		var types []string
+		_ = json.Unmarshal(place.Types, &types)
+
+		photoURL := ""
+		var photos []models.PlacePhotoRef
+		if json.Unmarshal(place.Photos, &photos) == nil && len(photos) > 0 {
+			photoURL = s.googlePlaces.GetPhotoURL(photos[0].Reference, 400)
+		}
+
+		result := dto.PlaceResult{
+			PlaceID:      place.PlaceID,
+			Name:         name,
+			Address:      address,
+			Lat:          place.Lat,
+			Lng:          place.Lng,
+			Rating:       place.Rating,
+			ReviewCount:  place.ReviewCount,
+			PriceLevel:   place.PriceLevel,
+			Types:        types,
+			PhotoURL:     photoURL,
+			ProvinceCode: s.provinceCode(place.Lat, place.Lng),
+		}
+		if lat != 0 && lng != 0 {
+			distance := google.CalculateDistance(lat, lng, place.Lat, place.Lng)
+			result.Distance = distance
+			result.DistanceText = formatDistance(distance)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// provinceCode returns the DOPA code of the province containing a point,
+// or "" when the boundaries aren't loaded or the point is outside Thailand
+func (s *SearchServiceImpl) provinceCode(lat, lng float64) string {
+	if s.boundaries == nil {
+		return ""
+	}
+	if province, ok := s.boundaries.ProvinceAt(lat, lng); ok {
+		return province.Code
+	}
+	return ""
+}
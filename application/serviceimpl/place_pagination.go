@@ -0,0 +1,364 @@
+package serviceimpl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/infrastructure/external/google"
+)
+
+// maxPlacePages is the number of pages the Places API serves per search (3 x 20 results)
+const maxPlacePages = 3
+
+// placePage is one Places API result page, cached together with the token of the next page
+type placePage struct {
+	Results       []dto.PlaceResult `json:"results"`
+	NextPageToken string            `json:"nextPageToken,omitempty"`
+}
+
+// placeCursor is the opaque pagination cursor handed to clients.
+// It carries the Google token for the page it points into, so a page that
+// dropped out of the cache can still be fetched. Cursors are signed: the
+// token decides what gets cached under the search's shared key, so clients
+// must not be able to supply their own.
+type placeCursor struct {
+	Key    string `json:"k"` // base cache key, binds the cursor to one search
+	Page   int    `json:"p"` // our page number the cursor resumes at
+	GPage  int    `json:"g"` // Places API page the next result lives on
+	Offset int    `json:"o"` // index inside that Places API page
+	Token  string `json:"t"` // next_page_token that fetches GPage (empty for page 1)
+}
+
+func encodePlaceCursor(c placeCursor, key []byte) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(signPlaceCursor(data, key))
+}
+
+func decodePlaceCursor(raw, baseKey string, key []byte) (*placeCursor, error) {
+	payload, signature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return nil, services.ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, services.ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, signPlaceCursor(data, key)) {
+		return nil, services.ErrInvalidCursor
+	}
+	var c placeCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, services.ErrInvalidCursor
+	}
+	if c.Key != baseKey || c.GPage < 1 || c.GPage > maxPlacePages || c.Offset < 0 || c.Offset >= google.PlacesPageSize {
+		return nil, services.ErrInvalidCursor
+	}
+	if c.GPage > 1 && c.Token == "" {
+		return nil, services.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+func signPlaceCursor(data, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// placeSearchQuery describes one paged place search
+type placeSearchQuery struct {
+	baseKey  string
+	endpoint string // text_search or nearby_search, used for API logging
+	params   map[string]interface{}
+	userID   uuid.UUID
+	lang     string
+	lat, lng float64 // user location for distances, zero when unknown
+	fetch    func(ctx context.Context, pageToken string) (*google.NearbySearchResponse, error)
+}
+
+// placeSearchPage is the outcome of a paged place search
+type placeSearchPage struct {
+	results     []dto.PlaceResult
+	page        int
+	totalLoaded int64
+	hasMore     bool
+	nextCursor  string
+	fromCache   bool
+	apiCalled   bool
+}
+
+// paginatePlaces returns one page of place results. Places API pages are
+// fetched in order (each needs the previous page's token) and every page is
+// cached with its next token, so later pages and cursors resolve from Redis.
+func (s *SearchServiceImpl) paginatePlaces(ctx context.Context, q placeSearchQuery, page, pageSize int, rawCursor string) (*placeSearchPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > google.PlacesPageSize {
+		pageSize = google.PlacesPageSize
+	}
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_places"); err != nil {
+			return nil, err
+		}
+	}
+
+	// tokens[g] fetches Places API page g; pages[g] holds it once loaded
+	tokens := map[int]string{1: ""}
+	pages := make(map[int]*placePage)
+	out := &placeSearchPage{page: page, fromCache: true}
+
+	start := (page - 1) * pageSize
+	if rawCursor != "" {
+		cursor, err := decodePlaceCursor(rawCursor, q.baseKey, s.cursorKey)
+		if err != nil {
+			return nil, err
+		}
+		out.page = cursor.Page
+		start = (cursor.GPage-1)*google.PlacesPageSize + cursor.Offset
+		tokens[cursor.GPage] = cursor.Token
+	}
+
+	// loadPage returns Places API page g from cache, or fetches it after
+	// walking earlier pages to discover its token. nil means no such page.
+	var loadPage func(g int) (*placePage, error)
+	loadPage = func(g int) (*placePage, error) {
+		if p, ok := pages[g]; ok {
+			return p, nil
+		}
+		p, ok := s.getCachedPlacePage(ctx, q, g)
+		if !ok {
+			token, known := tokens[g]
+			if !known {
+				prev, err := loadPage(g - 1)
+				if err != nil {
+					return nil, err
+				}
+				if prev == nil || prev.NextPageToken == "" {
+					return nil, nil
+				}
+				token = prev.NextPageToken
+			}
+			var err error
+			if p, err = s.fetchPlacePage(ctx, q, g, token); err != nil {
+				return nil, err
+			}
+			out.fromCache = false
+			out.apiCalled = out.apiCalled || g == 1
+		}
+		pages[g] = p
+		if p.NextPageToken != "" {
+			tokens[g+1] = p.NextPageToken
+		}
+		return p, nil
+	}
+
+	var results []dto.PlaceResult
+	idx := start
+	for len(results) < pageSize {
+		g := idx/google.PlacesPageSize + 1
+		if g > maxPlacePages {
+			break
+		}
+		p, err := loadPage(g)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			break
+		}
+		offset := idx % google.PlacesPageSize
+		if offset >= len(p.Results) {
+			break
+		}
+		need := pageSize - len(results)
+		end := offset + need
+		if end > len(p.Results) {
+			end = len(p.Results)
+		}
+		results = append(results, p.Results[offset:end]...)
+		idx += end - offset
+		if end < len(p.Results) || p.NextPageToken == "" {
+			break
+		}
+	}
+
+	out.results = results
+
+	// Work out where the next page starts and whether it exists
+	nextG := idx/google.PlacesPageSize + 1
+	nextOffset := idx % google.PlacesPageSize
+	if nextG <= maxPlacePages {
+		if p, ok := pages[nextG]; ok {
+			out.hasMore = nextOffset < len(p.Results)
+		} else if token, ok := tokens[nextG]; ok && token != "" {
+			out.hasMore = true
+		}
+	}
+	if out.hasMore {
+		out.nextCursor = encodePlaceCursor(placeCursor{
+			Key:    q.baseKey,
+			Page:   out.page + 1,
+			GPage:  nextG,
+			Offset: nextOffset,
+			Token:  tokens[nextG],
+		}, s.cursorKey)
+	}
+
+	for g := 1; g <= maxPlacePages; g++ {
+		if p, ok := pages[g]; ok {
+			out.totalLoaded = int64((g-1)*google.PlacesPageSize + len(p.Results))
+		}
+	}
+	if out.totalLoaded < int64(idx) {
+		out.totalLoaded = int64(idx)
+	}
+
+	return out, nil
+}
+
+// getCachedPlacePage returns Places API page g of a search from Redis
+func (s *SearchServiceImpl) getCachedPlacePage(ctx context.Context, q placeSearchQuery, g int) (*placePage, bool) {
+	cacheKey := cache.PlaceSearchPageKey(q.baseKey, g)
+	cached, err := s.redisClient.Get(ctx, cacheKey).Result()
+	if err != nil {
+		return nil, false
+	}
+	var p placePage
+	if json.Unmarshal([]byte(cached), &p) != nil {
+		return nil, false
+	}
+	if s.apiLogger != nil {
+		s.apiLogger.LogCacheHit(ctx, "google_places", q.endpoint, cacheKey, &q.userID)
+	}
+	return &p, true
+}
+
+// fetchPlacePage calls the Places API for page g, stores the places and
+// caches the page with its next token
+func (s *SearchServiceImpl) fetchPlacePage(ctx context.Context, q placeSearchQuery, g int, token string) (*placePage, error) {
+	if g > 1 && token == "" {
+		return nil, services.ErrInvalidCursor
+	}
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "google_places"); err != nil {
+			return nil, err
+		}
+	}
+
+	startTime := time.Now()
+	searchResponse, err := q.fetch(ctx, token)
+	durationMs := int(time.Since(startTime).Milliseconds())
+
+	// Log API call
+	if s.apiLogger != nil {
+		success := err == nil
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+		}
+		params := map[string]interface{}{"page": g}
+		for k, v := range q.params {
+			params[k] = v
+		}
+		s.apiLogger.LogAPICall(ctx, "google_places", q.endpoint, params, 0.032, durationMs, &q.userID, success, errMsg) // $32 per 1000 requests
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.storeSearchPlaces(ctx, searchResponse.Results, q.lang)
+
+	p := &placePage{
+		Results:       s.toPlaceResults(searchResponse.Results, q.lat, q.lng),
+		NextPageToken: searchResponse.NextPageToken,
+	}
+
+	if jsonData, err := json.Marshal(p); err == nil {
+		s.redisClient.Set(ctx, cache.PlaceSearchPageKey(q.baseKey, g), jsonData, cache.TTLNearbyPlaces)
+	}
+
+	return p, nil
+}
+
+// toPlaceSearchResponse builds the API response for one page of place results
+func (s *SearchServiceImpl) toPlaceSearchResponse(query string, pageSize int, out *placeSearchPage) *dto.PlaceSearchResponse {
+	if pageSize < 1 || pageSize > google.PlacesPageSize {
+		pageSize = google.PlacesPageSize
+	}
+	results := out.results
+	if results == nil {
+		results = []dto.PlaceResult{}
+	}
+	return &dto.PlaceSearchResponse{
+		Query:      query,
+		Results:    results,
+		TotalCount: out.totalLoaded,
+		Page:       out.page,
+		PageSize:   pageSize,
+		HasMore:    out.hasMore,
+		NextCursor: out.nextCursor,
+		Source:     dto.PlaceSourceGoogle,
+		FromCache:  out.fromCache,
+	}
+}
+
+// toPlaceResults maps Places API results, adding distance when the user location is known
+func (s *SearchServiceImpl) toPlaceResults(places []google.Place, lat, lng float64) []dto.PlaceResult {
+	placeResults := make([]dto.PlaceResult, 0, len(places))
+	for _, place := range places {
+		photoURL := ""
+		if len(place.Photos) > 0 {
+			photoURL = s.googlePlaces.GetPhotoURL(place.Photos[0].PhotoReference, 400)
+		}
+
+		var isOpen *bool
+		if place.OpeningHours != nil {
+			openNow := place.OpeningHours.OpenNow
+			isOpen = &openNow
+		}
+
+		// Use Vicinity for nearby search, FormattedAddress for text search
+		address := place.Vicinity
+		if address == "" {
+			address = place.FormattedAddress
+		}
+
+		placeResult := dto.PlaceResult{
+			PlaceID:      place.PlaceID,
+			Name:         place.Name,
+			Address:      address,
+			Lat:          place.Geometry.Location.Lat,
+			Lng:          place.Geometry.Location.Lng,
+			Rating:       place.Rating,
+			ReviewCount:  place.UserRatingsTotal,
+			PriceLevel:   place.PriceLevel,
+			Types:        place.Types,
+			PhotoURL:     photoURL,
+			IsOpen:       isOpen,
+			ProvinceCode: s.provinceCode(place.Geometry.Location.Lat, place.Geometry.Location.Lng),
+		}
+
+		// Calculate distance if user location provided
+		if lat != 0 && lng != 0 {
+			distance := google.CalculateDistance(lat, lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
+			placeResult.Distance = distance
+			placeResult.DistanceText = formatDistance(distance)
+		}
+
+		placeResults = append(placeResults, placeResult)
+	}
+	return placeResults
+}
@@ -0,0 +1,301 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/external/google"
+)
+
+func TestDecodePlaceCursor(t *testing.T) {
+	key := []byte("cursor-key")
+	valid := placeCursor{Key: "search:1", Page: 2, GPage: 2, Offset: 5, Token: "token-2"}
+	encoded := encodePlaceCursor(valid, key)
+	payload, signature, _ := strings.Cut(encoded, ".")
+
+	tamperedPayload := base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"k":"search:1","p":2,"g":2,"o":5,"t":"attacker-token"}`)) + "." + signature
+	signedGarbage := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+	signedGarbage += "." + base64.RawURLEncoding.EncodeToString(signPlaceCursor([]byte("not json"), key))
+
+	tests := []struct {
+		name    string
+		raw     string
+		baseKey string
+		key     []byte
+		want    *placeCursor
+	}{
+		{name: "valid", raw: encoded, baseKey: "search:1", key: key, want: &valid},
+		{name: "first page without token", raw: encodePlaceCursor(placeCursor{Key: "search:1", Page: 2, GPage: 1, Offset: 10}, key), baseKey: "search:1", key: key,
+			want: &placeCursor{Key: "search:1", Page: 2, GPage: 1, Offset: 10}},
+		{name: "tampered payload", raw: tamperedPayload, baseKey: "search:1", key: key},
+		{name: "tampered signature", raw: payload + "." + base64.RawURLEncoding.EncodeToString([]byte("forged")), baseKey: "search:1", key: key},
+		{name: "signed with another key", raw: encodePlaceCursor(valid, []byte("other-key")), baseKey: "search:1", key: key},
+		{name: "another search", raw: encoded, baseKey: "search:2", key: key},
+		{name: "no signature", raw: payload, baseKey: "search:1", key: key},
+		{name: "not base64", raw: "!!!." + signature, baseKey: "search:1", key: key},
+		{name: "signed but not json", raw: signedGarbage, baseKey: "search:1", key: key},
+		{name: "empty", raw: "", baseKey: "search:1", key: key},
+		{name: "google page zero", raw: encodePlaceCursor(placeCursor{Key: "search:1", Page: 2, GPage: 0, Token: "t"}, key), baseKey: "search:1", key: key},
+		{name: "google page past the last", raw: encodePlaceCursor(placeCursor{Key: "search:1", Page: 2, GPage: maxPlacePages + 1, Token: "t"}, key), baseKey: "search:1", key: key},
+		{name: "negative offset", raw: encodePlaceCursor(placeCursor{Key: "search:1", Page: 2, GPage: 2, Offset: -1, Token: "t"}, key), baseKey: "search:1", key: key},
+		{name: "offset past the page", raw: encodePlaceCursor(placeCursor{Key: "search:1", Page: 2, GPage: 2, Offset: google.PlacesPageSize, Token: "t"}, key), baseKey: "search:1", key: key},
+		{name: "later page without token", raw: encodePlaceCursor(placeCursor{Key: "search:1", Page: 2, GPage: 2}, key), baseKey: "search:1", key: key},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePlaceCursor(tt.raw, tt.baseKey, tt.key)
+			if tt.want == nil {
+				if !errors.Is(err, services.ErrInvalidCursor) {
+					t.Fatalf("decodePlaceCursor() error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodePlaceCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodePlaceCursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakePlacesAPI serves numbered results over Places API pages chained by tokens
+type fakePlacesAPI struct {
+	pageSizes []int    // results on each page
+	calls     []string // tokens in the order they were fetched
+}
+
+func (f *fakePlacesAPI) fetch(ctx context.Context, token string) (*google.NearbySearchResponse, error) {
+	f.calls = append(f.calls, token)
+
+	g := 1
+	if token != "" {
+		if _, err := fmt.Sscanf(token, "token-%d", &g); err != nil || g > len(f.pageSizes) {
+			return nil, errors.New("INVALID_REQUEST")
+		}
+	}
+
+	resp := &google.NearbySearchResponse{}
+	for i := 0; i < f.pageSizes[g-1]; i++ {
+		resp.Results = append(resp.Results, google.Place{PlaceID: fmt.Sprintf("r%d", (g-1)*google.PlacesPageSize+i)})
+	}
+	if g < len(f.pageSizes) {
+		resp.NextPageToken = fmt.Sprintf("token-%d", g+1)
+	}
+	return resp, nil
+}
+
+// discardPlaceRepository drops the places search results would store
+type discardPlaceRepository struct {
+	repositories.PlaceRepository
+}
+
+func (discardPlaceRepository) UpsertSearchResults(ctx context.Context, places []*models.Place, lang string) error {
+	return nil
+}
+
+// newPaginationTestService returns a service whose Redis is unreachable, so
+// every page is a cache miss and has to come from the Places API
+func newPaginationTestService(t *testing.T) *SearchServiceImpl {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+	return &SearchServiceImpl{
+		placeRepo:   discardPlaceRepository{},
+		redisClient: rdb,
+		cursorKey:   []byte("cursor-key"),
+	}
+}
+
+func placeIDRange(from, to int) []string {
+	var ids []string
+	for i := from; i < to; i++ {
+		ids = append(ids, fmt.Sprintf("r%d", i))
+	}
+	return ids
+}
+
+func TestPaginatePlaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		pageSizes   []int
+		page        int
+		pageSize    int
+		wantIDs     []string
+		wantCalls   []string
+		wantHasMore bool
+		wantTotal   int64
+	}{
+		{
+			name:        "first page",
+			pageSizes:   []int{20, 20, 5},
+			page:        1,
+			pageSize:    10,
+			wantIDs:     placeIDRange(0, 10),
+			wantCalls:   []string{""},
+			wantHasMore: true,
+			wantTotal:   20,
+		},
+		{
+			name:        "later page walks the tokens",
+			pageSizes:   []int{20, 20, 5},
+			page:        3,
+			pageSize:    10,
+			wantIDs:     placeIDRange(20, 30),
+			wantCalls:   []string{"", "token-2"},
+			wantHasMore: true,
+			wantTotal:   40,
+		},
+		{
+			name:        "page spanning two Places API pages",
+			pageSizes:   []int{20, 20, 5},
+			page:        2,
+			pageSize:    15,
+			wantIDs:     placeIDRange(15, 30),
+			wantCalls:   []string{"", "token-2"},
+			wantHasMore: true,
+			wantTotal:   40,
+		},
+		{
+			name:        "last page",
+			pageSizes:   []int{20, 20, 5},
+			page:        3,
+			pageSize:    20,
+			wantIDs:     placeIDRange(40, 45),
+			wantCalls:   []string{"", "token-2", "token-3"},
+			wantHasMore: false,
+			wantTotal:   45,
+		},
+		{
+			name:        "short last page",
+			pageSizes:   []int{20, 5},
+			page:        2,
+			pageSize:    20,
+			wantIDs:     placeIDRange(20, 25),
+			wantCalls:   []string{"", "token-2"},
+			wantHasMore: false,
+			wantTotal:   25,
+		},
+		{
+			name:        "no results",
+			pageSizes:   []int{0},
+			page:        1,
+			pageSize:    20,
+			wantCalls:   []string{""},
+			wantHasMore: false,
+			wantTotal:   0,
+		},
+		{
+			name:        "single short page",
+			pageSizes:   []int{7},
+			page:        1,
+			pageSize:    20,
+			wantIDs:     placeIDRange(0, 7),
+			wantCalls:   []string{""},
+			wantHasMore: false,
+			wantTotal:   7,
+		},
+		{
+			name:        "oversized page size is capped",
+			pageSizes:   []int{20, 20},
+			page:        1,
+			pageSize:    100,
+			wantIDs:     placeIDRange(0, 20),
+			wantCalls:   []string{""},
+			wantHasMore: true,
+			wantTotal:   20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPaginationTestService(t)
+			api := &fakePlacesAPI{pageSizes: tt.pageSizes}
+			q := placeSearchQuery{baseKey: "search:test", endpoint: "text_search", fetch: api.fetch}
+
+			out, err := s.paginatePlaces(context.Background(), q, tt.page, tt.pageSize, "")
+			if err != nil {
+				t.Fatalf("paginatePlaces() error = %v", err)
+			}
+
+			var ids []string
+			for _, r := range out.results {
+				ids = append(ids, r.PlaceID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("results = %v, want %v", ids, tt.wantIDs)
+			}
+			if !reflect.DeepEqual(api.calls, tt.wantCalls) {
+				t.Errorf("fetched tokens = %q, want %q", api.calls, tt.wantCalls)
+			}
+			if out.hasMore != tt.wantHasMore {
+				t.Errorf("hasMore = %v, want %v", out.hasMore, tt.wantHasMore)
+			}
+			if out.hasMore != (out.nextCursor != "") {
+				t.Errorf("nextCursor = %q with hasMore %v", out.nextCursor, out.hasMore)
+			}
+			if out.totalLoaded != tt.wantTotal {
+				t.Errorf("totalLoaded = %d, want %d", out.totalLoaded, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestPaginatePlacesFollowsCursor(t *testing.T) {
+	s := newPaginationTestService(t)
+	api := &fakePlacesAPI{pageSizes: []int{20, 20, 5}}
+	q := placeSearchQuery{baseKey: "search:test", endpoint: "text_search", fetch: api.fetch}
+	ctx := context.Background()
+
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("cursor walk did not end")
+		}
+		out, err := s.paginatePlaces(ctx, q, 1, 15, cursor)
+		if err != nil {
+			t.Fatalf("paginatePlaces() page %d error = %v", pages+1, err)
+		}
+		if out.page != pages+1 {
+			t.Errorf("page = %d, want %d", out.page, pages+1)
+		}
+		for _, r := range out.results {
+			ids = append(ids, r.PlaceID)
+		}
+		if !out.hasMore {
+			break
+		}
+		cursor = out.nextCursor
+	}
+
+	if want := placeIDRange(0, 45); !reflect.DeepEqual(ids, want) {
+		t.Errorf("results = %v, want %v", ids, want)
+	}
+	// Without a cache a cursor refetches the Places API page it resumes in,
+	// with the token it carries, but never walks the pages before it again
+	if want := []string{"", "", "token-2", "token-2", "token-3"}; !reflect.DeepEqual(api.calls, want) {
+		t.Errorf("fetched tokens = %q, want %q", api.calls, want)
+	}
+
+	other := placeSearchQuery{baseKey: "search:other", endpoint: "text_search", fetch: api.fetch}
+	first, err := s.paginatePlaces(ctx, q, 1, 15, "")
+	if err != nil {
+		t.Fatalf("paginatePlaces() error = %v", err)
+	}
+	if _, err := s.paginatePlaces(ctx, other, 1, 15, first.nextCursor); !errors.Is(err, services.ErrInvalidCursor) {
+		t.Errorf("cursor of another search: error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,263 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/tracing"
+)
+
+// Source names used in the per-source status block of a mixed search
+const (
+	searchSourcePlaces   = "places"
+	searchSourceVideos   = "videos"
+	searchSourceWebsites = "websites"
+)
+
+// Default per-source deadlines when none are configured
+const defaultSearchSourceTimeout = 4 * time.Second
+
+// sourceOutcome is the result of one source lookup in a mixed search
+type sourceOutcome struct {
+	results    []dto.SearchResult
+	totalCount int64
+	fromCache  bool
+	err        error
+	duration   time.Duration
+	timedOut   bool
+}
+
+// searchLookup is one source of a mixed search and its deadline
+type searchLookup struct {
+	timeout time.Duration
+	run     func(ctx context.Context) sourceOutcome
+}
+
+// searchAllSources runs the place, video and website lookups concurrently,
+// each with its own deadline, and returns whatever finished in time.
+// It only fails when every source failed.
+func (s *SearchServiceImpl) searchAllSources(ctx context.Context, userID uuid.UUID, req *dto.SearchRequest) ([]dto.SearchResult, int64, map[string]dto.SearchSourceStatus, error) {
+	outcomes := runSearchSources(ctx, map[string]searchLookup{
+		searchSourcePlaces: {
+			timeout: s.searchConfig.PlacesTimeout,
+			run: func(ctx context.Context) sourceOutcome {
+				return s.searchPlacesSource(ctx, userID, req)
+			},
+		},
+		searchSourceVideos: {
+			timeout: s.searchConfig.VideosTimeout,
+			run: func(ctx context.Context) sourceOutcome {
+				return s.searchVideosSource(ctx, userID, req)
+			},
+		},
+		searchSourceWebsites: {
+			timeout: s.searchConfig.WebsitesTimeout,
+			run: func(ctx context.Context) sourceOutcome {
+				return s.searchWebsitesSource(ctx, userID, req)
+			},
+		},
+	})
+	return mergeSourceOutcomes(ctx, req.Query, outcomes)
+}
+
+// runSearchSources runs every lookup in its own goroutine under its own
+// deadline and waits for all of them
+func runSearchSources(ctx context.Context, lookups map[string]searchLookup) map[string]sourceOutcome {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		outcomes = make(map[string]sourceOutcome, len(lookups))
+	)
+
+	for name, lookup := range lookups {
+		wg.Add(1)
+		go func(name string, timeout time.Duration, run func(ctx context.Context) sourceOutcome) {
+			defer wg.Done()
+
+			if timeout <= 0 {
+				timeout = defaultSearchSourceTimeout
+			}
+			sourceCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			sourceCtx, span := tracing.Start(sourceCtx, "search.source "+name)
+			defer span.End()
+
+			start := time.Now()
+			outcome := run(sourceCtx)
+			outcome.duration = time.Since(start)
+			if outcome.err != nil && (errors.Is(outcome.err, context.DeadlineExceeded) || errors.Is(sourceCtx.Err(), context.DeadlineExceeded)) {
+				outcome.timedOut = true
+			}
+			span.SetAttributes(
+				attribute.Int("search.results", len(outcome.results)),
+				attribute.Bool("search.from_cache", outcome.fromCache),
+				attribute.Bool("search.timed_out", outcome.timedOut),
+			)
+			tracing.RecordError(span, outcome.err)
+
+			mu.Lock()
+			outcomes[name] = outcome
+			mu.Unlock()
+		}(name, lookup.timeout, lookup.run)
+	}
+
+	wg.Wait()
+	return outcomes
+}
+
+// mergeSourceOutcomes concatenates the results of the sources that answered
+// and reports each source's status. It only fails when every source failed.
+func mergeSourceOutcomes(ctx context.Context, query string, outcomes map[string]sourceOutcome) ([]dto.SearchResult, int64, map[string]dto.SearchSourceStatus, error) {
+	// Keep the original ordering: places, videos, then websites
+	var results []dto.SearchResult
+	var totalCount int64
+	status := make(map[string]dto.SearchSourceStatus, len(outcomes))
+	failed := 0
+	var lastErr error
+
+	for _, name := range []string{searchSourcePlaces, searchSourceVideos, searchSourceWebsites} {
+		outcome, ok := outcomes[name]
+		if !ok {
+			continue
+		}
+		sourceStatus := dto.SearchSourceStatus{
+			Status:     dto.SearchSourceOK,
+			Count:      len(outcome.results),
+			DurationMs: outcome.duration.Milliseconds(),
+		}
+
+		switch {
+		case outcome.timedOut:
+			sourceStatus.Status = dto.SearchSourceTimeout
+			sourceStatus.Error = "source timed out"
+		case outcome.err != nil:
+			// Upstream errors can carry request URLs with API keys and raw
+			// response bodies; the detail only goes to the log below
+			sourceStatus.Status = dto.SearchSourceError
+			sourceStatus.Error = "source failed"
+		case outcome.fromCache:
+			sourceStatus.Status = dto.SearchSourceCached
+		}
+		status[name] = sourceStatus
+
+		if outcome.err != nil {
+			failed++
+			lastErr = outcome.err
+			logger.WarnContext(ctx, "Search source failed",
+				"source", name,
+				"query", query,
+				"status", sourceStatus.Status,
+				"error", outcome.err.Error(),
+				"response_time_ms", sourceStatus.DurationMs,
+			)
+			continue
+		}
+
+		results = append(results, outcome.results...)
+		if name == searchSourceWebsites {
+			totalCount = outcome.totalCount
+		}
+	}
+
+	if failed == len(outcomes) {
+		return nil, 0, status, lastErr
+	}
+
+	if totalCount == 0 {
+		totalCount = int64(len(results))
+	}
+
+	return results, totalCount, status, nil
+}
+
+func (s *SearchServiceImpl) searchPlacesSource(ctx context.Context, userID uuid.UUID, req *dto.SearchRequest) sourceOutcome {
+	placeReq := &dto.PlaceSearchRequest{
+		Query:    req.Query,
+		Page:     1,
+		PageSize: 8,
+		Lang:     req.Language,
+	}
+	placeResp, err := s.SearchPlaces(ctx, userID, placeReq)
+	if err != nil {
+		return sourceOutcome{err: err}
+	}
+
+	// Search results carry no website; the catalogue has it once details were fetched
+	placeIDs := make([]string, 0, len(placeResp.Results))
+	for _, r := range placeResp.Results {
+		placeIDs = append(placeIDs, r.PlaceID)
+	}
+	websites := s.storedPlaceWebsites(ctx, placeIDs)
+
+	var results []dto.SearchResult
+	for _, r := range placeResp.Results {
+		results = append(results, dto.SearchResult{
+			Type:         "place",
+			PlaceID:      r.PlaceID,
+			Title:        r.Name,
+			URL:          websites[r.PlaceID],
+			Snippet:      r.Address,
+			ThumbnailURL: r.PhotoURL,
+			Lat:          r.Lat,
+			Lng:          r.Lng,
+			Rating:       r.Rating,
+			ReviewCount:  r.ReviewCount,
+			Types:        r.Types,
+		})
+	}
+	return sourceOutcome{results: results, totalCount: placeResp.TotalCount, fromCache: placeResp.FromCache}
+}
+
+func (s *SearchServiceImpl) searchVideosSource(ctx context.Context, userID uuid.UUID, req *dto.SearchRequest) sourceOutcome {
+	vidReq := &dto.VideoSearchRequest{
+		Query:    req.Query,
+		Page:     1,
+		PageSize: 4,
+	}
+	vidResp, err := s.SearchVideos(ctx, userID, vidReq)
+	if err != nil {
+		return sourceOutcome{err: err}
+	}
+
+	var results []dto.SearchResult
+	for _, r := range vidResp.Results {
+		results = append(results, dto.SearchResult{
+			Type:         "video",
+			VideoID:      r.VideoID,
+			Title:        r.Title,
+			Snippet:      r.Description,
+			ThumbnailURL: r.ThumbnailURL,
+			Source:       r.ChannelTitle,
+			PublishedAt:  r.PublishedAt,
+			Duration:     r.Duration,
+			ViewCount:    r.ViewCount,
+		})
+	}
+	return sourceOutcome{results: results, totalCount: vidResp.TotalCount, fromCache: vidResp.FromCache}
+}
+
+func (s *SearchServiceImpl) searchWebsitesSource(ctx context.Context, userID uuid.UUID, req *dto.SearchRequest) sourceOutcome {
+	webResp, err := s.SearchWebsites(ctx, userID, req)
+	if err != nil {
+		return sourceOutcome{err: err}
+	}
+
+	var results []dto.SearchResult
+	for _, r := range webResp.Results {
+		results = append(results, dto.SearchResult{
+			Type:    "website",
+			Title:   r.Title,
+			URL:     r.URL,
+			Snippet: r.Snippet,
+			Source:  r.DisplayLink,
+		})
+	}
+	return sourceOutcome{results: results, totalCount: webResp.TotalCount, fromCache: webResp.FromCache}
+}
@@ -0,0 +1,162 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gofiber-template/domain/dto"
+)
+
+// answer is a lookup that returns outcome straight away
+func answer(outcome sourceOutcome) searchLookup {
+	return searchLookup{
+		timeout: time.Second,
+		run:     func(ctx context.Context) sourceOutcome { return outcome },
+	}
+}
+
+// hang is a lookup that only returns once its deadline has passed
+func hang() searchLookup {
+	return searchLookup{
+		timeout: 10 * time.Millisecond,
+		run: func(ctx context.Context) sourceOutcome {
+			<-ctx.Done()
+			return sourceOutcome{err: ctx.Err()}
+		},
+	}
+}
+
+func titled(kind string, titles ...string) []dto.SearchResult {
+	var results []dto.SearchResult
+	for _, title := range titles {
+		results = append(results, dto.SearchResult{Type: kind, Title: title})
+	}
+	return results
+}
+
+func TestSearchAllSourcesFanOut(t *testing.T) {
+	places := titled("place", "p1", "p2")
+	videos := titled("video", "v1")
+	websites := titled("website", "w1", "w2", "w3")
+	upstream := errors.New("googleapi: Error 403 key=secret")
+
+	tests := []struct {
+		name       string
+		lookups    map[string]searchLookup
+		wantTitles []string
+		wantTotal  int64
+		wantStatus map[string]string
+		wantErr    bool
+	}{
+		{
+			name: "every source answers",
+			lookups: map[string]searchLookup{
+				searchSourceWebsites: answer(sourceOutcome{results: websites, totalCount: 120}),
+				searchSourceVideos:   answer(sourceOutcome{results: videos}),
+				searchSourcePlaces:   answer(sourceOutcome{results: places, fromCache: true}),
+			},
+			wantTitles: []string{"p1", "p2", "v1", "w1", "w2", "w3"},
+			wantTotal:  120,
+			wantStatus: map[string]string{
+				searchSourcePlaces:   dto.SearchSourceCached,
+				searchSourceVideos:   dto.SearchSourceOK,
+				searchSourceWebsites: dto.SearchSourceOK,
+			},
+		},
+		{
+			name: "slow source times out and the rest still answer",
+			lookups: map[string]searchLookup{
+				searchSourcePlaces:   answer(sourceOutcome{results: places}),
+				searchSourceVideos:   hang(),
+				searchSourceWebsites: answer(sourceOutcome{results: websites, totalCount: 3}),
+			},
+			wantTitles: []string{"p1", "p2", "w1", "w2", "w3"},
+			wantTotal:  3,
+			wantStatus: map[string]string{
+				searchSourcePlaces:   dto.SearchSourceOK,
+				searchSourceVideos:   dto.SearchSourceTimeout,
+				searchSourceWebsites: dto.SearchSourceOK,
+			},
+		},
+		{
+			name: "failed websites source leaves the total to the results",
+			lookups: map[string]searchLookup{
+				searchSourcePlaces:   answer(sourceOutcome{results: places}),
+				searchSourceVideos:   answer(sourceOutcome{results: videos}),
+				searchSourceWebsites: answer(sourceOutcome{err: upstream}),
+			},
+			wantTitles: []string{"p1", "p2", "v1"},
+			wantTotal:  3,
+			wantStatus: map[string]string{
+				searchSourcePlaces:   dto.SearchSourceOK,
+				searchSourceVideos:   dto.SearchSourceOK,
+				searchSourceWebsites: dto.SearchSourceError,
+			},
+		},
+		{
+			name: "every source fails",
+			lookups: map[string]searchLookup{
+				searchSourcePlaces:   answer(sourceOutcome{err: upstream}),
+				searchSourceVideos:   hang(),
+				searchSourceWebsites: answer(sourceOutcome{err: upstream}),
+			},
+			wantStatus: map[string]string{
+				searchSourcePlaces:   dto.SearchSourceError,
+				searchSourceVideos:   dto.SearchSourceTimeout,
+				searchSourceWebsites: dto.SearchSourceError,
+			},
+			wantErr: true,
+		},
+		{
+			name: "sources with nothing found are not failures",
+			lookups: map[string]searchLookup{
+				searchSourcePlaces:   answer(sourceOutcome{}),
+				searchSourceVideos:   answer(sourceOutcome{}),
+				searchSourceWebsites: answer(sourceOutcome{}),
+			},
+			wantTotal: 0,
+			wantStatus: map[string]string{
+				searchSourcePlaces:   dto.SearchSourceOK,
+				searchSourceVideos:   dto.SearchSourceOK,
+				searchSourceWebsites: dto.SearchSourceOK,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			results, total, status, err := mergeSourceOutcomes(ctx, "bangkok", runSearchSources(ctx, tt.lookups))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+
+			var titles []string
+			for _, r := range results {
+				titles = append(titles, r.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("results = %v, want %v", titles, tt.wantTitles)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("totalCount = %d, want %d", total, tt.wantTotal)
+			}
+
+			gotStatus := make(map[string]string, len(status))
+			for name, s := range status {
+				gotStatus[name] = s.Status
+				// Upstream errors can carry API keys, so only fixed messages go out
+				if s.Error != "" && s.Error != "source timed out" && s.Error != "source failed" {
+					t.Errorf("%s error = %q, want a fixed message", name, s.Error)
+				}
+			}
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status = %v, want %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package serviceimpl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/infrastructure/external/google"
+	"gofiber-template/pkg/config"
+)
+
+// trackingParams are query parameters stripped during URL normalization
+var trackingParams = map[string]bool{
+	"fbclid": true,
+	"gclid":  true,
+	"ref":    true,
+	"source": true,
+}
+
+// titleSeparators split site names off page titles ("Wat Arun - Wikipedia")
+var titleSeparators = []string{" - ", " | ", " – ", " — ", " : ", " :: "}
+
+// searchRanker de-duplicates and interleaves mixed search results
+type searchRanker struct {
+	weights map[string]float64
+}
+
+func newSearchRanker(cfg config.SearchConfig) *searchRanker {
+	weights := map[string]float64{
+		"place":   cfg.PlaceWeight,
+		"video":   cfg.VideoWeight,
+		"website": cfg.WebsiteWeight,
+	}
+	for t, w := range weights {
+		if w <= 0 {
+			weights[t] = 1
+		}
+	}
+	return &searchRanker{weights: weights}
+}
+
+// rankedResult carries the ranking inputs of one result
+type rankedResult struct {
+	result   dto.SearchResult
+	score    float64
+	distance float64 // -1 when unknown
+	order    int     // original position, keeps the sort stable
+}
+
+// Rank merges duplicates and orders results by weighted provider rank.
+// Ties are broken by rating, review count and, when lat/lng is given, distance.
+func (r *searchRanker) Rank(results []dto.SearchResult, lat, lng float64) []dto.SearchResult {
+	merged := r.dedupe(results)
+	hasLocation := lat != 0 || lng != 0
+
+	// Position within its own type is the provider's relevance rank
+	typeRank := make(map[string]int)
+	ranked := make([]rankedResult, 0, len(merged))
+	for i, res := range merged {
+		rank := typeRank[res.Type]
+		typeRank[res.Type] = rank + 1
+
+		weight, ok := r.weights[res.Type]
+		if !ok {
+			weight = 1
+		}
+
+		item := rankedResult{
+			result:   res,
+			score:    weight / float64(rank+1),
+			distance: -1,
+			order:    i,
+		}
+		if hasLocation && (res.Lat != 0 || res.Lng != 0) {
+			item.distance = google.CalculateDistance(lat, lng, res.Lat, res.Lng)
+			item.result.Distance = item.distance
+			item.result.DistanceText = formatDistance(item.distance)
+		}
+		ranked = append(ranked, item)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.result.Rating != b.result.Rating {
+			return a.result.Rating > b.result.Rating
+		}
+		if a.result.ReviewCount != b.result.ReviewCount {
+			return a.result.ReviewCount > b.result.ReviewCount
+		}
+		if a.distance >= 0 && b.distance >= 0 && a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		return a.order < b.order
+	})
+
+	out := make([]dto.SearchResult, 0, len(ranked))
+	for _, item := range ranked {
+		out = append(out, item.result)
+	}
+	return out
+}
+
+// dedupe merges results pointing at the same resource.
+// Results sharing a normalized URL collapse into the first one; web pages at a
+// place's website or whose title names a place are folded into that place as
+// related links.
+func (r *searchRanker) dedupe(results []dto.SearchResult) []dto.SearchResult {
+	var out []dto.SearchResult
+	byURL := make(map[string]int)
+	byPlaceName := make(map[string]int)
+
+	for _, res := range results {
+		key := resultKey(res)
+		if key != "" {
+			if idx, ok := byURL[key]; ok {
+				out[idx] = mergeResults(out[idx], res)
+				continue
+			}
+		}
+
+		// A place whose website is already listed takes that entry over
+		website := ""
+		if res.Type == "place" && res.URL != "" {
+			website = normalizeURL(res.URL)
+			if idx, ok := byURL[website]; ok && out[idx].Type != "place" {
+				out[idx] = mergeResults(res, out[idx])
+				byURL[key] = idx
+				if name := normalizeTitle(res.Title); name != "" {
+					byPlaceName[name] = idx
+				}
+				continue
+			}
+		}
+
+		if res.Type == "website" {
+			if idx, ok := byPlaceName[normalizeTitle(res.Title)]; ok {
+				out[idx] = mergeResults(out[idx], res)
+				if key != "" {
+					byURL[key] = idx
+				}
+				continue
+			}
+		}
+
+		out = append(out, res)
+		idx := len(out) - 1
+		if key != "" {
+			byURL[key] = idx
+		}
+		if _, ok := byURL[website]; website != "" && !ok {
+			byURL[website] = idx
+		}
+		if res.Type == "place" {
+			if name := normalizeTitle(res.Title); name != "" {
+				byPlaceName[name] = idx
+			}
+		}
+	}
+
+	return out
+}
+
+// mergeResults folds dup into keep, keeping the richer fields of both
+func mergeResults(keep, dup dto.SearchResult) dto.SearchResult {
+	if dup.URL != "" && normalizeURL(dup.URL) != normalizeURL(keep.URL) {
+		if keep.URL == "" {
+			keep.URL = dup.URL
+		} else if !containsString(keep.RelatedURLs, dup.URL) {
+			keep.RelatedURLs = append(keep.RelatedURLs, dup.URL)
+		}
+	}
+	if keep.Snippet == "" {
+		keep.Snippet = dup.Snippet
+	}
+	if keep.ThumbnailURL == "" {
+		keep.ThumbnailURL = dup.ThumbnailURL
+	}
+	if dup.Rating > keep.Rating {
+		keep.Rating = dup.Rating
+	}
+	if dup.ReviewCount > keep.ReviewCount {
+		keep.ReviewCount = dup.ReviewCount
+	}
+	return keep
+}
+
+// resultKey returns the identity used to detect duplicates
+func resultKey(res dto.SearchResult) string {
+	switch {
+	case res.PlaceID != "":
+		return "place:" + res.PlaceID
+	case res.VideoID != "":
+		return "youtube:" + res.VideoID
+	case res.URL != "":
+		return normalizeURL(res.URL)
+	}
+	return ""
+}
+
+// normalizeURL reduces a URL to a comparable form: no scheme, www/m prefixes,
+// fragment, tracking parameters or trailing slash. YouTube links map to the video ID.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(raw), "/"))
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	if host == "youtube.com" && u.Query().Get("v") != "" {
+		return "youtube:" + u.Query().Get("v")
+	}
+	if host == "youtu.be" {
+		return "youtube:" + strings.Trim(u.Path, "/")
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+
+	normalized := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
+
+// normalizeTitle strips the site name suffix and case from a title
+func normalizeTitle(title string) string {
+	title = strings.ToLower(strings.TrimSpace(title))
+	for _, sep := range titleSeparators {
+		if idx := strings.Index(title, sep); idx > 0 {
+			title = title[:idx]
+		}
+	}
+	return strings.TrimSpace(title)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
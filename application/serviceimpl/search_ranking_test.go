@@ -0,0 +1,228 @@
+package serviceimpl
+
+import (
+	"reflect"
+	"testing"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/pkg/config"
+)
+
+func titles(results []dto.SearchResult) []string {
+	out := make([]string, 0, len(results))
+	for _, res := range results {
+		out = append(out, res.Title)
+	}
+	return out
+}
+
+func TestSearchRankerRank(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.SearchConfig
+		results  []dto.SearchResult
+		lat, lng float64
+		want     []string
+	}{
+		{
+			name: "equal weights interleave by provider rank",
+			results: []dto.SearchResult{
+				{Type: "place", Title: "p1", PlaceID: "p1"},
+				{Type: "place", Title: "p2", PlaceID: "p2"},
+				{Type: "website", Title: "w1", URL: "https://a.example/1"},
+				{Type: "website", Title: "w2", URL: "https://a.example/2"},
+			},
+			want: []string{"p1", "w1", "p2", "w2"},
+		},
+		{
+			name: "weight pulls a type up",
+			cfg:  config.SearchConfig{PlaceWeight: 2, WebsiteWeight: 1},
+			results: []dto.SearchResult{
+				{Type: "website", Title: "w1", URL: "https://a.example/1"},
+				{Type: "website", Title: "w2", URL: "https://a.example/2"},
+				{Type: "place", Title: "p1", PlaceID: "p1"},
+				{Type: "place", Title: "p2", PlaceID: "p2"},
+				{Type: "place", Title: "p3", PlaceID: "p3"},
+			},
+			want: []string{"p1", "w1", "p2", "p3", "w2"},
+		},
+		{
+			name: "rating breaks a score tie",
+			results: []dto.SearchResult{
+				{Type: "website", Title: "w1", URL: "https://a.example/1"},
+				{Type: "place", Title: "p1", PlaceID: "p1", Rating: 4.5},
+			},
+			want: []string{"p1", "w1"},
+		},
+		{
+			name: "review count breaks a rating tie",
+			results: []dto.SearchResult{
+				{Type: "place", Title: "p1", PlaceID: "p1", Rating: 4, ReviewCount: 10},
+				{Type: "event", Title: "e1", URL: "https://e.example/1", Rating: 4, ReviewCount: 200},
+			},
+			want: []string{"e1", "p1"},
+		},
+		{
+			name: "distance breaks a tie when a location is given",
+			results: []dto.SearchResult{
+				{Type: "place", Title: "far", PlaceID: "far", Lat: 18.79, Lng: 98.98},
+				{Type: "event", Title: "near", URL: "https://e.example/near", Lat: 13.75, Lng: 100.49},
+			},
+			lat:  13.74,
+			lng:  100.50,
+			want: []string{"near", "far"},
+		},
+		{
+			name: "input order is kept without a location",
+			results: []dto.SearchResult{
+				{Type: "place", Title: "far", PlaceID: "far", Lat: 18.79, Lng: 98.98},
+				{Type: "event", Title: "near", URL: "https://e.example/near", Lat: 13.75, Lng: 100.49},
+			},
+			want: []string{"far", "near"},
+		},
+		{
+			name: "duplicates are merged before ranking",
+			results: []dto.SearchResult{
+				{Type: "website", Title: "w1", URL: "https://a.example/page?utm_source=x"},
+				{Type: "website", Title: "w1 copy", URL: "http://www.a.example/page/"},
+				{Type: "website", Title: "w2", URL: "https://b.example/"},
+			},
+			want: []string{"w1", "w2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := titles(newSearchRanker(tt.cfg).Rank(tt.results, tt.lat, tt.lng))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRankerRankSetsDistance(t *testing.T) {
+	results := []dto.SearchResult{
+		{Type: "place", Title: "p1", PlaceID: "p1", Lat: 13.75, Lng: 100.49},
+		{Type: "website", Title: "w1", URL: "https://a.example/"},
+	}
+
+	got := newSearchRanker(config.SearchConfig{}).Rank(results, 13.74, 100.50)
+	for _, res := range got {
+		switch res.Title {
+		case "p1":
+			if res.Distance <= 0 || res.DistanceText == "" {
+				t.Errorf("place distance = %v %q, want it set", res.Distance, res.DistanceText)
+			}
+		case "w1":
+			if res.Distance != 0 || res.DistanceText != "" {
+				t.Errorf("website distance = %v %q, want none", res.Distance, res.DistanceText)
+			}
+		}
+	}
+}
+
+func TestSearchRankerDedupe(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []dto.SearchResult
+		want    []dto.SearchResult
+	}{
+		{
+			name: "distinct results are kept",
+			results: []dto.SearchResult{
+				{Type: "website", Title: "a", URL: "https://a.example/"},
+				{Type: "website", Title: "b", URL: "https://b.example/"},
+			},
+			want: []dto.SearchResult{
+				{Type: "website", Title: "a", URL: "https://a.example/"},
+				{Type: "website", Title: "b", URL: "https://b.example/"},
+			},
+		},
+		{
+			name: "same normalized URL merges into the first",
+			results: []dto.SearchResult{
+				{Type: "website", Title: "a", URL: "https://a.example/page"},
+				{Type: "website", Title: "a again", URL: "http://www.a.example/page/?fbclid=1", Snippet: "from the copy"},
+			},
+			want: []dto.SearchResult{
+				{Type: "website", Title: "a", URL: "https://a.example/page", Snippet: "from the copy"},
+			},
+		},
+		{
+			name: "a place takes over the entry of its website",
+			results: []dto.SearchResult{
+				{Type: "website", Title: "Official site", URL: "https://www.watarun.org/", Snippet: "temple"},
+				{Type: "place", Title: "Wat Arun", PlaceID: "wat-arun", URL: "https://watarun.org", Rating: 4.7},
+			},
+			want: []dto.SearchResult{
+				{Type: "place", Title: "Wat Arun", PlaceID: "wat-arun", URL: "https://watarun.org", Rating: 4.7, Snippet: "temple"},
+			},
+		},
+		{
+			name: "a page titled after a place becomes a related link",
+			results: []dto.SearchResult{
+				{Type: "place", Title: "Wat Arun", PlaceID: "wat-arun"},
+				{Type: "website", Title: "Wat Arun - Wikipedia", URL: "https://en.wikipedia.org/wiki/Wat_Arun"},
+			},
+			want: []dto.SearchResult{
+				{Type: "place", Title: "Wat Arun", PlaceID: "wat-arun", URL: "https://en.wikipedia.org/wiki/Wat_Arun"},
+			},
+		},
+		{
+			name: "a second page about a place is added to its related links",
+			results: []dto.SearchResult{
+				{Type: "place", Title: "Wat Arun", PlaceID: "wat-arun", URL: "https://watarun.org"},
+				{Type: "website", Title: "Wat Arun | Tourism Thailand", URL: "https://tourism.example/wat-arun"},
+			},
+			want: []dto.SearchResult{
+				{Type: "place", Title: "Wat Arun", PlaceID: "wat-arun", URL: "https://watarun.org", RelatedURLs: []string{"https://tourism.example/wat-arun"}},
+			},
+		},
+		{
+			name: "a YouTube link merges with the video",
+			results: []dto.SearchResult{
+				{Type: "video", Title: "Tour", VideoID: "abc123"},
+				{Type: "website", Title: "Tour - YouTube", URL: "https://m.youtube.com/watch?v=abc123"},
+			},
+			want: []dto.SearchResult{
+				{Type: "video", Title: "Tour", VideoID: "abc123", URL: "https://m.youtube.com/watch?v=abc123"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newSearchRanker(config.SearchConfig{}).dedupe(tt.results)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dedupe() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://example.com/page", "example.com/page"},
+		{"http://www.Example.com/page/", "example.com/page"},
+		{"https://m.example.com/page#section", "example.com/page"},
+		{"https://example.com/page?utm_source=x&UTM_Medium=y&id=7", "example.com/page?id=7"},
+		{"https://example.com/?fbclid=1&gclid=2&ref=3&source=4", "example.com"},
+		{"https://example.com/search?q=b&a=1", "example.com/search?a=1&q=b"},
+		{"https://www.youtube.com/watch?v=abc123&t=30", "youtube:abc123"},
+		{"https://youtu.be/abc123", "youtube:abc123"},
+		{"https://www.youtube.com/channel/xyz", "youtube.com/channel/xyz"},
+		{"  Not A URL/ ", "not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := normalizeURL(tt.raw); got != tt.want {
+				t.Errorf("normalizeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,39 +18,56 @@ import (
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/cache"
 	"gofiber-template/infrastructure/external/google"
-	"gofiber-template/infrastructure/external/openai"
+	"gofiber-template/infrastructure/thaigeo"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
 )
 
 type SearchServiceImpl struct {
 	searchHistoryRepo    repositories.SearchHistoryRepository
 	placeAIContentRepo   repositories.PlaceAIContentRepository
+	placeRepo            repositories.PlaceRepository
 	googleSearch         *google.SearchClient
 	googlePlaces         *google.PlacesClient
 	googleYouTube        *google.YouTubeClient
-	openaiClient         *openai.AIClient
+	placeOverviewLLM     services.LLMProvider
+	boundaries           *thaigeo.Boundaries
 	redisClient          *redis.Client
 	apiLogger            *APILoggerService
+	searchConfig         config.SearchConfig
+	cursorKey            []byte // signs place pagination cursors
+	ranker               *searchRanker
 }
 
 func NewSearchService(
 	searchHistoryRepo repositories.SearchHistoryRepository,
 	placeAIContentRepo repositories.PlaceAIContentRepository,
+	placeRepo repositories.PlaceRepository,
 	googleSearch *google.SearchClient,
 	googlePlaces *google.PlacesClient,
 	googleYouTube *google.YouTubeClient,
-	openaiClient *openai.AIClient,
+	placeOverviewLLM services.LLMProvider,
+	boundaries *thaigeo.Boundaries,
 	redisClient *redis.Client,
 	apiLogger *APILoggerService,
+	searchConfig config.SearchConfig,
+	jwtConfig config.JWTConfig,
 ) services.SearchService {
 	return &SearchServiceImpl{
 		searchHistoryRepo:  searchHistoryRepo,
 		placeAIContentRepo: placeAIContentRepo,
+		placeRepo:          placeRepo,
 		googleSearch:       googleSearch,
 		googlePlaces:       googlePlaces,
 		googleYouTube:      googleYouTube,
-		openaiClient:       openaiClient,
+		placeOverviewLLM:   placeOverviewLLM,
+		boundaries:         boundaries,
 		redisClient:        redisClient,
 		apiLogger:          apiLogger,
+		searchConfig:       searchConfig,
+		cursorKey:          []byte(jwtConfig.Secret + ":place_cursor"),
+		ranker:             newSearchRanker(searchConfig),
 	}
 }
 
@@ -67,6 +84,7 @@ func (s *SearchServiceImpl) Search(ctx context.Context, userID uuid.UUID, req *d
 
 	var results []dto.SearchResult
 	var totalCount int64
+	var sourceStatus map[string]dto.SearchSourceStatus
 
 	switch req.Type {
 	case "website":
@@ -127,70 +145,13 @@ func (s *SearchServiceImpl) Search(ctx context.Context, userID uuid.UUID, req *d
 		}
 		totalCount = vidResp.TotalCount
 	default:
-		// All - search multiple types in parallel
-		// Search places
-		placeReq := &dto.PlaceSearchRequest{
-			Query:    req.Query,
-			Page:     1,
-			PageSize: 8,
-			Lang:     req.Language,
-		}
-		placeResp, _ := s.SearchPlaces(ctx, userID, placeReq)
-		if placeResp != nil {
-			for _, r := range placeResp.Results {
-				results = append(results, dto.SearchResult{
-					Type:         "place",
-					PlaceID:      r.PlaceID,
-					Title:        r.Name,
-					Snippet:      r.Address,
-					ThumbnailURL: r.PhotoURL,
-					Lat:          r.Lat,
-					Lng:          r.Lng,
-					Rating:       r.Rating,
-					ReviewCount:  r.ReviewCount,
-					Types:        r.Types,
-				})
-			}
-		}
-
-		// Search videos
-		vidReq := &dto.VideoSearchRequest{
-			Query:    req.Query,
-			Page:     1,
-			PageSize: 4,
-		}
-		vidResp, _ := s.SearchVideos(ctx, userID, vidReq)
-		if vidResp != nil {
-			for _, r := range vidResp.Results {
-				results = append(results, dto.SearchResult{
-					Type:         "video",
-					VideoID:      r.VideoID,
-					Title:        r.Title,
-					Snippet:      r.Description,
-					ThumbnailURL: r.ThumbnailURL,
-					Source:       r.ChannelTitle,
-					PublishedAt:  r.PublishedAt,
-					Duration:     r.Duration,
-					ViewCount:    r.ViewCount,
-				})
-			}
-		}
-
-		// Search websites
-		webResp, err := s.SearchWebsites(ctx, userID, req)
+		// All - fan out to places, videos and websites concurrently
+		var err error
+		results, totalCount, sourceStatus, err = s.searchAllSources(ctx, userID, req)
 		if err != nil {
 			return nil, err
 		}
-		for _, r := range webResp.Results {
-			results = append(results, dto.SearchResult{
-				Type:    "website",
-				Title:   r.Title,
-				URL:     r.URL,
-				Snippet: r.Snippet,
-				Source:  r.DisplayLink,
-			})
-		}
-		totalCount = webResp.TotalCount
+		results = s.ranker.Rank(results, req.Lat, req.Lng)
 	}
 
 	return &dto.SearchResponse{
@@ -200,6 +161,7 @@ func (s *SearchServiceImpl) Search(ctx context.Context, userID uuid.UUID, req *d
 		TotalCount: totalCount,
 		Page:       req.Page,
 		PageSize:   req.PageSize,
+		Sources:    sourceStatus,
 	}, nil
 }
 
@@ -211,6 +173,12 @@ func (s *SearchServiceImpl) SearchWebsites(ctx context.Context, userID uuid.UUID
 		req.PageSize = 10
 	}
 
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_search"); err != nil {
+			return nil, err
+		}
+	}
+
 	// Expand query if it's just a province name (e.g., "สกลนคร" -> "สกลนคร สถานที่ท่องเที่ยว")
 	expandedQuery := ExpandSearchQuery(req.Query, req.Language)
 
@@ -219,6 +187,7 @@ func (s *SearchServiceImpl) SearchWebsites(ctx context.Context, userID uuid.UUID
 	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
 		var cachedResult dto.WebsiteSearchResponse
 		if json.Unmarshal([]byte(cached), &cachedResult) == nil {
+			cachedResult.FromCache = true
 			// Log cache hit
 			if s.apiLogger != nil {
 				s.apiLogger.LogCacheHit(ctx, "google_search", "website_search", cacheKey, &userID)
@@ -229,6 +198,11 @@ func (s *SearchServiceImpl) SearchWebsites(ctx context.Context, userID uuid.UUID
 	}
 
 	// Cache miss - call API
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "google_search"); err != nil {
+			return nil, err
+		}
+	}
 	startTime := time.Now()
 	searchResponse, err := s.googleSearch.SearchAll(ctx, expandedQuery, req.Page, req.PageSize)
 	durationMs := int(time.Since(startTime).Milliseconds())
@@ -292,6 +266,12 @@ func (s *SearchServiceImpl) SearchImages(ctx context.Context, userID uuid.UUID,
 	// Note: ImageSearchRequest doesn't have Language field, defaults to Thai
 	expandedQuery := ExpandSearchQuery(req.Query, "")
 
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_search"); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check cache first (use expanded query for cache key)
 	cacheKey := cache.ImageSearchKey(expandedQuery, req.Page)
 	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
@@ -307,6 +287,11 @@ func (s *SearchServiceImpl) SearchImages(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Cache miss - call API
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "google_search"); err != nil {
+			return nil, err
+		}
+	}
 	startTime := time.Now()
 	searchResponse, err := s.googleSearch.SearchImages(ctx, expandedQuery, req.Page, req.PageSize)
 	durationMs := int(time.Since(startTime).Milliseconds())
@@ -386,11 +371,18 @@ func (s *SearchServiceImpl) SearchVideos(ctx context.Context, userID uuid.UUID,
 	// Note: VideoSearchRequest doesn't have Language field, defaults to Thai
 	expandedQuery := ExpandSearchQuery(req.Query, "")
 
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "youtube"); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check cache first (use expanded query for cache key)
 	cacheKey := cache.YouTubeKey(expandedQuery, req.PageSize)
 	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
 		var cachedResult dto.VideoSearchResponse
 		if json.Unmarshal([]byte(cached), &cachedResult) == nil {
+			cachedResult.FromCache = true
 			// Log cache hit
 			if s.apiLogger != nil {
 				s.apiLogger.LogCacheHit(ctx, "youtube", "video_search", cacheKey, &userID)
@@ -401,6 +393,11 @@ func (s *SearchServiceImpl) SearchVideos(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Cache miss - call API
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "youtube"); err != nil {
+			return nil, err
+		}
+	}
 	searchReq := &google.VideoSearchRequest{
 		Query:      expandedQuery,
 		MaxResults: req.PageSize,
@@ -438,7 +435,7 @@ func (s *SearchServiceImpl) SearchVideos(ctx context.Context, userID uuid.UUID,
 	// Get video details (duration, view count)
 	var detailsMap = make(map[string]google.VideoDetails)
 	if len(videoIDs) > 0 {
-		detailsResponse, err := s.googleYouTube.GetVideoDetails(ctx, videoIDs)
+		detailsResponse, err := s.fetchVideoDetails(ctx, videoIDs, &userID)
 		if err == nil && detailsResponse != nil {
 			for _, d := range detailsResponse.Items {
 				detailsMap[d.ID] = d
@@ -507,8 +504,14 @@ func (s *SearchServiceImpl) GetVideoDetails(ctx context.Context, videoID string)
 		}
 	}
 
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "youtube"); err != nil {
+			return nil, err
+		}
+	}
+
 	// Cache miss - Get video details (duration, statistics)
-	detailsResponse, err := s.googleYouTube.GetVideoDetails(ctx, []string{videoID})
+	detailsResponse, err := s.fetchVideoDetails(ctx, []string{videoID}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -524,7 +527,7 @@ func (s *SearchServiceImpl) GetVideoDetails(ctx context.Context, videoID string)
 		Query:      "video:" + videoID,
 		MaxResults: 1,
 	}
-	searchResponse, _ := s.googleYouTube.SearchVideos(ctx, searchReq)
+	searchResponse, _ := s.searchVideos(ctx, searchReq, "video_details", nil)
 
 	var title, description, thumbnailURL, channelTitle, publishedAt string
 	if searchResponse != nil && len(searchResponse.Items) > 0 {
@@ -563,162 +566,151 @@ func (s *SearchServiceImpl) GetVideoDetails(ctx context.Context, videoID string)
 	return result, nil
 }
 
-func (s *SearchServiceImpl) SearchPlaces(ctx context.Context, userID uuid.UUID, req *dto.PlaceSearchRequest) (*dto.PlaceSearchResponse, error) {
-	// Expand query if it's just a province name (e.g., "สกลนคร" -> "สกลนคร สถานที่ท่องเที่ยว")
-	expandedQuery := ExpandSearchQuery(req.Query, req.Lang)
-
-	// Determine search type: Text Search (no lat/lng) or Nearby Search (with lat/lng)
-	useTextSearch := req.Lat == 0 && req.Lng == 0
-
-	// Get language from request, default to Thai
-	lang := req.Lang
-	if lang == "" {
-		lang = "th"
-	}
-
-	var cacheKey string
-	if useTextSearch {
-		cacheKey = cache.PlaceTextSearchKey(expandedQuery, lang)
-	} else {
-		if req.Radius == 0 {
-			req.Radius = 5000
+// searchVideos runs a YouTube search (search.list, 100 quota units) within
+// the youtube budget and logs its cost
+func (s *SearchServiceImpl) searchVideos(ctx context.Context, req *google.VideoSearchRequest, endpoint string, userID *uuid.UUID) (*google.VideoSearchResponse, error) {
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "youtube"); err != nil {
+			return nil, err
 		}
-		cacheKey = cache.NearbyPlacesKey(req.Lat, req.Lng, req.Radius, req.PlaceType, expandedQuery, lang)
 	}
 
+	startTime := time.Now()
+	searchResponse, err := s.googleYouTube.SearchVideos(ctx, req)
+	durationMs := int(time.Since(startTime).Milliseconds())
 
-	// Check cache first (use expanded query for cache key)
-	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
-		var cachedResult dto.PlaceSearchResponse
-		if json.Unmarshal([]byte(cached), &cachedResult) == nil {
-			// Log cache hit
-			if s.apiLogger != nil {
-				endpoint := "text_search"
-				if !useTextSearch {
-					endpoint = "nearby_search"
-				}
-				s.apiLogger.LogCacheHit(ctx, "google_places", endpoint, cacheKey, &userID)
-			}
-			// Don't save history for cache hits - only first search counts
-			return &cachedResult, nil
+	if s.apiLogger != nil {
+		success := err == nil
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
 		}
+		s.apiLogger.LogAPICall(ctx, "youtube", endpoint, map[string]interface{}{
+			"query":      req.Query,
+			"maxResults": req.MaxResults,
+		}, 0.0001, durationMs, userID, success, errMsg) // YouTube Data API: 100 units = ~0.01 cents
 	}
 
-	// Cache miss - call API
-	var searchResponse *google.NearbySearchResponse
-	var err error
-	var endpoint string
-	var apiCost float64
-
-	startTime := time.Now()
+	return searchResponse, err
+}
 
-	if useTextSearch {
-		// Text Search - search by query text only (like Google Maps search)
-		textReq := &google.TextSearchRequest{
-			Query:    expandedQuery,
-			Language: lang,
-			Region:   "th",
-		}
-		searchResponse, err = s.googlePlaces.TextSearch(ctx, textReq)
-		endpoint = "text_search"
-		apiCost = 0.032 // Text Search: $32 per 1000 requests
-	} else {
-		// Nearby Search - search by location
-		nearbyReq := &google.NearbySearchRequest{
-			Lat:      req.Lat,
-			Lng:      req.Lng,
-			Radius:   req.Radius,
-			Type:     req.PlaceType,
-			Keyword:  expandedQuery,
-			Language: lang,
+// fetchVideoDetails gets duration and statistics of videos (videos.list,
+// 1 quota unit) within the youtube budget and logs its cost
+func (s *SearchServiceImpl) fetchVideoDetails(ctx context.Context, videoIDs []string, userID *uuid.UUID) (*google.VideoDetailsResponse, error) {
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "youtube"); err != nil {
+			return nil, err
 		}
-		searchResponse, err = s.googlePlaces.NearbySearch(ctx, nearbyReq)
-		endpoint = "nearby_search"
-		apiCost = 0.032 // Nearby Search: $32 per 1000 requests
 	}
 
+	startTime := time.Now()
+	detailsResponse, err := s.googleYouTube.GetVideoDetails(ctx, videoIDs)
 	durationMs := int(time.Since(startTime).Milliseconds())
 
-	// Log API call
 	if s.apiLogger != nil {
 		success := err == nil
 		errMsg := ""
 		if err != nil {
 			errMsg = err.Error()
 		}
-		s.apiLogger.LogAPICall(ctx, "google_places", endpoint, map[string]interface{}{
-			"query":    expandedQuery,
-			"lat":      req.Lat,
-			"lng":      req.Lng,
-			"radius":   req.Radius,
-			"type":     req.PlaceType,
-			"language": lang,
-		}, apiCost, durationMs, &userID, success, errMsg)
+		s.apiLogger.LogAPICall(ctx, "youtube", "video_details", map[string]interface{}{
+			"videoIds": len(videoIDs),
+		}, 0.000001, durationMs, userID, success, errMsg) // 1 unit
 	}
 
-	if err != nil {
-		return nil, err
+	return detailsResponse, err
+}
+
+func (s *SearchServiceImpl) SearchPlaces(ctx context.Context, userID uuid.UUID, req *dto.PlaceSearchRequest) (*dto.PlaceSearchResponse, error) {
+	// Expand query if it's just a province name (e.g., "สกลนคร" -> "สกลนคร สถานที่ท่องเที่ยว")
+	expandedQuery := ExpandSearchQuery(req.Query, req.Lang)
+
+	// Determine search type: Text Search (no lat/lng) or Nearby Search (with lat/lng)
+	useTextSearch := req.Lat == 0 && req.Lng == 0
+
+	// Get language from request, default to Thai
+	lang := req.Lang
+	if lang == "" {
+		lang = "th"
 	}
 
-	var placeResults []dto.PlaceResult
-	for _, place := range searchResponse.Results {
-		photoURL := ""
-		if len(place.Photos) > 0 {
-			photoURL = s.googlePlaces.GetPhotoURL(place.Photos[0].PhotoReference, 400)
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_places"); err != nil {
+			return nil, err
 		}
+	}
 
-		var isOpen *bool
-		if place.OpeningHours != nil {
-			isOpen = &place.OpeningHours.OpenNow
+	// Answer text searches from the places table when it already holds enough
+	// fresh matches. Cursors need Google's page tokens.
+	if useTextSearch && req.Cursor == "" {
+		degraded := s.apiLogger != nil && s.apiLogger.BudgetDegraded(ctx, "google_places")
+		local, ok, err := s.searchPlacesLocal(ctx, userID, req, lang, degraded)
+		if err != nil {
+			logger.WarnContext(ctx, "Local text place search failed, falling back to Google",
+				"error", err.Error(),
+			)
+		} else if ok {
+			return local, nil
 		}
+	}
 
-		// Use FormattedAddress for text search, Vicinity for nearby search
-		address := place.Vicinity
-		if address == "" {
-			address = place.FormattedAddress
+	q := placeSearchQuery{
+		userID: userID,
+		lang:   lang,
+		lat:    req.Lat,
+		lng:    req.Lng,
+	}
+	if useTextSearch {
+		// Text Search - search by query text only (like Google Maps search)
+		q.baseKey = cache.PlaceTextSearchKey(expandedQuery, lang)
+		q.endpoint = "text_search"
+		q.fetch = func(ctx context.Context, pageToken string) (*google.NearbySearchResponse, error) {
+			return s.googlePlaces.TextSearch(ctx, &google.TextSearchRequest{
+				Query:     expandedQuery,
+				Language:  lang,
+				Region:    "th",
+				PageToken: pageToken,
+			})
 		}
-
-		placeResult := dto.PlaceResult{
-			PlaceID:     place.PlaceID,
-			Name:        place.Name,
-			Address:     address,
-			Lat:         place.Geometry.Location.Lat,
-			Lng:         place.Geometry.Location.Lng,
-			Rating:      place.Rating,
-			ReviewCount: place.UserRatingsTotal,
-			PriceLevel:  place.PriceLevel,
-			Types:       place.Types,
-			PhotoURL:    photoURL,
-			IsOpen:      isOpen,
+	} else {
+		// Nearby Search - search by location
+		if req.Radius == 0 {
+			req.Radius = 5000
 		}
-
-		// Calculate distance if user location provided
-		if req.Lat != 0 && req.Lng != 0 {
-			distance := google.CalculateDistance(req.Lat, req.Lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
-			placeResult.Distance = distance
-			placeResult.DistanceText = formatDistance(distance)
+		q.baseKey = cache.NearbyPlacesKey(req.Lat, req.Lng, req.Radius, req.PlaceType, expandedQuery, lang)
+		q.endpoint = "nearby_search"
+		q.fetch = func(ctx context.Context, pageToken string) (*google.NearbySearchResponse, error) {
+			return s.googlePlaces.NearbySearch(ctx, &google.NearbySearchRequest{
+				Lat:       req.Lat,
+				Lng:       req.Lng,
+				Radius:    req.Radius,
+				Type:      req.PlaceType,
+				Keyword:   expandedQuery,
+				Language:  lang,
+				PageToken: pageToken,
+			})
 		}
-
-		placeResults = append(placeResults, placeResult)
 	}
-
-	response := &dto.PlaceSearchResponse{
-		Query:      expandedQuery,
-		Results:    placeResults,
-		TotalCount: int64(len(placeResults)),
-		Page:       req.Page,
-		PageSize:   req.PageSize,
+	q.params = map[string]interface{}{
+		"query":    expandedQuery,
+		"lat":      req.Lat,
+		"lng":      req.Lng,
+		"radius":   req.Radius,
+		"type":     req.PlaceType,
+		"language": lang,
 	}
 
-	// Store in cache
-	if jsonData, err := json.Marshal(response); err == nil {
-		s.redisClient.Set(ctx, cacheKey, jsonData, cache.TTLNearbyPlaces)
+	out, err := s.paginatePlaces(ctx, q, req.Page, req.PageSize, req.Cursor)
+	if err != nil {
+		return nil, err
 	}
 
-	// Save search history
-	s.saveSearchHistory(ctx, userID, req.Query, models.SearchTypeMap, len(placeResults))
+	// Save search history - only when the first page came from the API
+	if out.apiCalled && out.page == 1 {
+		s.saveSearchHistory(ctx, userID, req.Query, models.SearchTypeMap, len(out.results))
+	}
 
-	return response, nil
+	return s.toPlaceSearchResponse(expandedQuery, req.PageSize, out), nil
 }
 
 func (s *SearchServiceImpl) GetPlaceDetails(ctx context.Context, placeID string, userLat, userLng float64, lang string) (*dto.PlaceDetailResponse, error) {
@@ -727,6 +719,12 @@ func (s *SearchServiceImpl) GetPlaceDetails(ctx context.Context, placeID string,
 		lang = "th"
 	}
 
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_places"); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check cache first (without distance - distance calculated per user)
 	cacheKey := cache.PlaceDetailsKey(placeID, lang)
 	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
@@ -746,7 +744,30 @@ func (s *SearchServiceImpl) GetPlaceDetails(ctx context.Context, placeID string,
 		}
 	}
 
-	// Cache miss - call API
+	// Cache miss - use the places table while the stored details are fresh,
+	// or at any age while the Places budget only allows stored answers
+	allowStale := s.apiLogger != nil && s.apiLogger.BudgetDegraded(ctx, "google_places")
+	if stored, ok := s.getStoredPlaceDetails(ctx, placeID, lang, allowStale); ok {
+		if s.apiLogger != nil {
+			s.apiLogger.LogDatabaseHit(ctx, "google_places", "place_details", nil)
+		}
+		if jsonData, err := json.Marshal(stored); err == nil {
+			s.redisClient.Set(ctx, cacheKey, jsonData, cache.TTLPlaceDetails)
+		}
+		if userLat != 0 && userLng != 0 {
+			distance := google.CalculateDistance(userLat, userLng, stored.Lat, stored.Lng)
+			stored.Distance = distance
+			stored.DistanceText = formatDistance(distance)
+		}
+		return stored, nil
+	}
+
+	// Stale or unknown - call API
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "google_places"); err != nil {
+			return nil, err
+		}
+	}
 	detailsReq := &google.PlaceDetailsRequest{
 		PlaceID:  placeID,
 		Language: lang,
@@ -776,6 +797,7 @@ func (s *SearchServiceImpl) GetPlaceDetails(ctx context.Context, placeID string,
 	}
 
 	result := detailsResponse.Result
+	s.storePlaceDetails(ctx, result, lang)
 
 	var openingHours []string
 	if result.OpeningHours != nil {
@@ -845,77 +867,62 @@ func (s *SearchServiceImpl) SearchNearbyPlaces(ctx context.Context, req *dto.Nea
 		lang = "th"
 	}
 
-	// Expand query if it's just a province name (e.g., "สกลนคร" -> "สกลนคร สถานที่ท่องเที่ยว")
-	expandedKeyword := ExpandSearchQuery(req.Keyword, lang)
-
-	// Check cache first (use expanded query for cache key)
-	cacheKey := cache.NearbyPlacesKey(req.Lat, req.Lng, req.Radius, req.PlaceType, expandedKeyword, lang)
-	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
-		var cachedResult dto.PlaceSearchResponse
-		if json.Unmarshal([]byte(cached), &cachedResult) == nil {
-			return &cachedResult, nil
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_places"); err != nil {
+			return nil, err
 		}
 	}
 
-	// Cache miss - call API
-	searchReq := &google.NearbySearchRequest{
-		Lat:      req.Lat,
-		Lng:      req.Lng,
-		Radius:   req.Radius,
-		Type:     req.PlaceType,
-		Keyword:  expandedKeyword,
-		Language: lang,
-	}
-
-	searchResponse, err := s.googlePlaces.NearbySearch(ctx, searchReq)
-	if err != nil {
-		return nil, err
-	}
-
-	var placeResults []dto.PlaceResult
-	for _, place := range searchResponse.Results {
-		photoURL := ""
-		if len(place.Photos) > 0 {
-			photoURL = s.googlePlaces.GetPhotoURL(place.Photos[0].PhotoReference, 400)
-		}
-
-		var isOpen *bool
-		if place.OpeningHours != nil {
-			isOpen = &place.OpeningHours.OpenNow
+	// Answer from the places table when it already holds enough fresh places.
+	// Keyword searches and cursors need Google's ranking and page tokens.
+	if req.Keyword == "" && req.Cursor == "" {
+		degraded := s.apiLogger != nil && s.apiLogger.BudgetDegraded(ctx, "google_places")
+		local, ok, err := s.searchNearbyLocal(ctx, req, lang, degraded)
+		if err != nil {
+			logger.WarnContext(ctx, "Local nearby search failed, falling back to Google",
+				"error", err.Error(),
+			)
+		} else if ok {
+			return local, nil
 		}
-
-		distance := google.CalculateDistance(req.Lat, req.Lng, place.Geometry.Location.Lat, place.Geometry.Location.Lng)
-		placeResults = append(placeResults, dto.PlaceResult{
-			PlaceID:      place.PlaceID,
-			Name:         place.Name,
-			Address:      place.Vicinity,
-			Lat:          place.Geometry.Location.Lat,
-			Lng:          place.Geometry.Location.Lng,
-			Rating:       place.Rating,
-			ReviewCount:  place.UserRatingsTotal,
-			PriceLevel:   place.PriceLevel,
-			Types:        place.Types,
-			PhotoURL:     photoURL,
-			IsOpen:       isOpen,
-			Distance:     distance,
-			DistanceText: formatDistance(distance),
-		})
 	}
 
-	response := &dto.PlaceSearchResponse{
-		Query:      req.Keyword,
-		Results:    placeResults,
-		TotalCount: int64(len(placeResults)),
-		Page:       req.Page,
-		PageSize:   req.PageSize,
+	// Expand query if it's just a province name (e.g., "สกลนคร" -> "สกลนคร สถานที่ท่องเที่ยว")
+	expandedKeyword := ExpandSearchQuery(req.Keyword, lang)
+
+	q := placeSearchQuery{
+		baseKey:  cache.NearbyPlacesKey(req.Lat, req.Lng, req.Radius, req.PlaceType, expandedKeyword, lang),
+		endpoint: "nearby_search",
+		params: map[string]interface{}{
+			"keyword":  expandedKeyword,
+			"lat":      req.Lat,
+			"lng":      req.Lng,
+			"radius":   req.Radius,
+			"type":     req.PlaceType,
+			"language": lang,
+		},
+		lang: lang,
+		lat:  req.Lat,
+		lng:  req.Lng,
+		fetch: func(ctx context.Context, pageToken string) (*google.NearbySearchResponse, error) {
+			return s.googlePlaces.NearbySearch(ctx, &google.NearbySearchRequest{
+				Lat:       req.Lat,
+				Lng:       req.Lng,
+				Radius:    req.Radius,
+				Type:      req.PlaceType,
+				Keyword:   expandedKeyword,
+				Language:  lang,
+				PageToken: pageToken,
+			})
+		},
 	}
 
-	// Store in cache
-	if jsonData, err := json.Marshal(response); err == nil {
-		s.redisClient.Set(ctx, cacheKey, jsonData, cache.TTLNearbyPlaces)
+	out, err := s.paginatePlaces(ctx, q, req.Page, req.PageSize, req.Cursor)
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	return s.toPlaceSearchResponse(req.Keyword, req.PageSize, out), nil
 }
 
 func (s *SearchServiceImpl) GetSearchHistory(ctx context.Context, userID uuid.UUID, req *dto.GetSearchHistoryRequest) (*dto.SearchHistoryListResponse, error) {
@@ -992,7 +999,8 @@ func (s *SearchServiceImpl) DeleteSearchHistoryItem(ctx context.Context, userID
 }
 
 func (s *SearchServiceImpl) saveSearchHistory(ctx context.Context, userID uuid.UUID, query, searchType string, resultCount int) {
-	if userID == uuid.Nil {
+	// Searches made with an API key come from the key owner's backend, not from them
+	if userID == uuid.Nil || utils.GetAPIKeyFromContext(ctx) != nil {
 		return
 	}
 
@@ -1132,6 +1140,35 @@ func (s *SearchServiceImpl) generateAIContentBackground(placeID string, basicDet
 	fmt.Printf("Background: Successfully generated AI content for place %s (lang=%s)\n", placeID, lang)
 }
 
+// RefreshPlaceAIContent regenerates the AI content of a place in one language
+// and stores it. It does nothing while that content is already being generated.
+func (s *SearchServiceImpl) RefreshPlaceAIContent(ctx context.Context, placeID, lang string) error {
+	generatingKey := placeID + ":" + lang
+	generatingMutex.Lock()
+	if generatingPlaces[generatingKey] {
+		generatingMutex.Unlock()
+		return nil
+	}
+	generatingPlaces[generatingKey] = true
+	generatingMutex.Unlock()
+
+	defer func() {
+		generatingMutex.Lock()
+		delete(generatingPlaces, generatingKey)
+		generatingMutex.Unlock()
+	}()
+
+	details, err := s.GetPlaceDetails(ctx, placeID, 0, 0, lang)
+	if err != nil {
+		return fmt.Errorf("get place details: %w", err)
+	}
+	aiContent, err := s.generateAIContent(ctx, details, lang)
+	if err != nil {
+		return err
+	}
+	return s.placeAIContentRepo.Upsert(ctx, aiContent)
+}
+
 // generateAIContent generates AI content for a place
 func (s *SearchServiceImpl) generateAIContent(ctx context.Context, place *dto.PlaceDetailResponse, lang string) (*models.PlaceAIContent, error) {
 	// Default to Thai if no language specified
@@ -1194,7 +1231,7 @@ func (s *SearchServiceImpl) generateAIContent(ctx context.Context, place *dto.Pl
 	return content, nil
 }
 
-// generateAIOverview generates AI overview using OpenAI
+// generateAIOverview generates AI overview using the place overview LLM provider
 func (s *SearchServiceImpl) generateAIOverview(ctx context.Context, place *dto.PlaceDetailResponse, lang string) (*dto.AIPlaceOverview, error) {
 	var prompt, systemPrompt string
 
@@ -1276,23 +1313,27 @@ Please create information in JSON format:
 		systemPrompt = "คุณเป็นมัคคุเทศก์ผู้เชี่ยวชาญด้านการท่องเที่ยวไทยที่มีประสบการณ์มากกว่า 20 ปี คุณมีความรู้ลึกซึ้งเกี่ยวกับประวัติศาสตร์ วัฒนธรรม และสถานที่ท่องเที่ยวทั่วประเทศไทย ให้ข้อมูลที่ถูกต้อง ละเอียด และเป็นประโยชน์สำหรับการนำเที่ยว"
 	}
 
-	messages := []openai.ChatMessage{
-		{Role: "system", Content: systemPrompt},
-		{Role: "user", Content: prompt},
-	}
-
-	response, err := s.openaiClient.Chat(ctx, messages, 3000, 0.7)
+	response, err := s.placeOverviewLLM.Chat(ctx, &services.LLMRequest{
+		Messages: []services.LLMMessage{
+			{Role: services.LLMRoleSystem, Content: systemPrompt},
+			{Role: services.LLMRoleUser, Content: prompt},
+		},
+		MaxTokens:   3000,
+		Temperature: 0.7,
+		JSONMode:    true,
+		Feature:     services.LLMFeaturePlaceOverview,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(response.Choices) == 0 {
-		return nil, errors.New("no response from OpenAI")
+	if response.Content == "" {
+		return nil, errors.New("empty response from " + s.placeOverviewLLM.Name())
 	}
 
 	// Parse JSON response
 	var overview dto.AIPlaceOverview
-	content := response.Choices[0].Message.Content
+	content := response.Content
 	if err := json.Unmarshal([]byte(content), &overview); err != nil {
 		// Try to extract JSON from response
 		if start := findJSONStart(content); start >= 0 {
@@ -1394,22 +1435,26 @@ Please create information in JSON format:
 		systemPrompt = "คุณเป็นมัคคุเทศก์ผู้เชี่ยวชาญที่มีประสบการณ์นำเที่ยวมากกว่า 20 ปี คุณรู้วิธีเล่าเรื่องให้น่าสนใจและรู้คำถามที่นักท่องเที่ยวมักถาม ให้ข้อมูลที่ละเอียดและเป็นประโยชน์จริง"
 	}
 
-	messages := []openai.ChatMessage{
-		{Role: "system", Content: systemPrompt},
-		{Role: "user", Content: prompt},
-	}
-
-	response, err := s.openaiClient.Chat(ctx, messages, 2500, 0.7)
+	response, err := s.placeOverviewLLM.Chat(ctx, &services.LLMRequest{
+		Messages: []services.LLMMessage{
+			{Role: services.LLMRoleSystem, Content: systemPrompt},
+			{Role: services.LLMRoleUser, Content: prompt},
+		},
+		MaxTokens:   2500,
+		Temperature: 0.7,
+		JSONMode:    true,
+		Feature:     services.LLMFeatureGuideInfo,
+	})
 	if err != nil {
 		return &dto.PlaceGuideInfo{}, err
 	}
 
-	if len(response.Choices) == 0 {
-		return &dto.PlaceGuideInfo{}, errors.New("no response from OpenAI")
+	if response.Content == "" {
+		return &dto.PlaceGuideInfo{}, errors.New("empty response from " + s.placeOverviewLLM.Name())
 	}
 
 	var guideInfo dto.PlaceGuideInfo
-	content := response.Choices[0].Message.Content
+	content := response.Content
 	if err := json.Unmarshal([]byte(content), &guideInfo); err != nil {
 		if start := findJSONStart(content); start >= 0 {
 			if end := findJSONEnd(content, start); end > start {
@@ -1431,7 +1476,7 @@ func (s *SearchServiceImpl) getRelatedVideos(ctx context.Context, searchQuery st
 		Order:      "relevance",
 	}
 
-	searchResponse, err := s.googleYouTube.SearchVideos(ctx, searchReq)
+	searchResponse, err := s.searchVideos(ctx, searchReq, "related_videos", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1445,7 +1490,7 @@ func (s *SearchServiceImpl) getRelatedVideos(ctx context.Context, searchQuery st
 	// Get video details
 	var detailsMap = make(map[string]google.VideoDetails)
 	if len(videoIDs) > 0 {
-		detailsResponse, err := s.googleYouTube.GetVideoDetails(ctx, videoIDs)
+		detailsResponse, err := s.fetchVideoDetails(ctx, videoIDs, nil)
 		if err == nil && detailsResponse != nil {
 			for _, d := range detailsResponse.Items {
 				detailsMap[d.ID] = d
@@ -13,14 +13,16 @@ import (
 )
 
 type TaskServiceImpl struct {
-	taskRepo repositories.TaskRepository
-	userRepo repositories.UserRepository
+	taskRepo    repositories.TaskRepository
+	userRepo    repositories.UserRepository
+	permissions services.PermissionService
 }
 
-func NewTaskService(taskRepo repositories.TaskRepository, userRepo repositories.UserRepository) services.TaskService {
+func NewTaskService(taskRepo repositories.TaskRepository, userRepo repositories.UserRepository, permissions services.PermissionService) services.TaskService {
 	return &TaskServiceImpl{
-		taskRepo: taskRepo,
-		userRepo: userRepo,
+		taskRepo:    taskRepo,
+		userRepo:    userRepo,
+		permissions: permissions,
 	}
 }
 
@@ -54,10 +56,14 @@ func (s *TaskServiceImpl) CreateTask(ctx context.Context, userID uuid.UUID, req
 	return task, nil
 }
 
-func (s *TaskServiceImpl) GetTask(ctx context.Context, taskID uuid.UUID) (*models.Task, error) {
+func (s *TaskServiceImpl) GetTask(ctx context.Context, actor services.Actor, taskID uuid.UUID) (*models.Task, error) {
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
-		return nil, errors.New("task not found")
+		return nil, services.ErrTaskNotFound
+	}
+
+	if err := authorizeOwner(ctx, s.permissions, actor, task.UserID, models.PermTasksReadAny); err != nil {
+		return nil, err
 	}
 	return task, nil
 }
@@ -76,10 +82,14 @@ func (s *TaskServiceImpl) GetUserTasks(ctx context.Context, userID uuid.UUID, of
 	return tasks, count, nil
 }
 
-func (s *TaskServiceImpl) UpdateTask(ctx context.Context, taskID uuid.UUID, req *dto.UpdateTaskRequest) (*models.Task, error) {
+func (s *TaskServiceImpl) UpdateTask(ctx context.Context, actor services.Actor, taskID uuid.UUID, req *dto.UpdateTaskRequest) (*models.Task, error) {
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
-		return nil, errors.New("task not found")
+		return nil, services.ErrTaskNotFound
+	}
+
+	if err := authorizeOwner(ctx, s.permissions, actor, task.UserID, models.PermTasksUpdateAny); err != nil {
+		return nil, err
 	}
 
 	if req.Title != "" {
@@ -108,10 +118,14 @@ func (s *TaskServiceImpl) UpdateTask(ctx context.Context, taskID uuid.UUID, req
 	return task, nil
 }
 
-func (s *TaskServiceImpl) DeleteTask(ctx context.Context, taskID uuid.UUID) error {
-	_, err := s.taskRepo.GetByID(ctx, taskID)
+func (s *TaskServiceImpl) DeleteTask(ctx context.Context, actor services.Actor, taskID uuid.UUID) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
-		return errors.New("task not found")
+		return services.ErrTaskNotFound
+	}
+
+	if err := authorizeOwner(ctx, s.permissions, actor, task.UserID, models.PermTasksDeleteAny); err != nil {
+		return err
 	}
 
 	return s.taskRepo.Delete(ctx, taskID)
@@ -0,0 +1,281 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/infrastructure/mail"
+	"gofiber-template/pkg/logger"
+)
+
+// mailThrottle is the minimum gap between two emails of the same kind to the
+// same user, so forgot-password can't be used to flood an inbox
+const mailThrottle = time.Minute
+
+// placeholderEmailDomain is used for LINE accounts that didn't share an email
+const placeholderEmailDomain = "@stou.placeholder"
+
+// emailLink describes one kind of emailed link
+type emailLink struct {
+	template string
+	path     string // frontend page that reads ?token=
+	ttl      func(s *UserServiceImpl) time.Duration
+}
+
+var emailLinks = map[string]emailLink{
+	models.EmailTokenVerifyEmail: {
+		template: mail.TemplateVerifyEmail,
+		path:     "/auth/verify-email",
+		ttl:      func(s *UserServiceImpl) time.Duration { return s.mailConfig.VerifyEmailTTL },
+	},
+	models.EmailTokenResetPassword: {
+		template: mail.TemplateResetPassword,
+		path:     "/auth/reset-password",
+		ttl:      func(s *UserServiceImpl) time.Duration { return s.mailConfig.ResetPasswordTTL },
+	},
+}
+
+// emailTokenClaims is the signed part of an emailed link. The ID is the
+// EmailToken row that makes it single-use; Email ties it to the address it
+// was sent to, so it stops working if the user changes their email.
+type emailTokenClaims struct {
+	Purpose string `json:"purpose"`
+	Email   string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// SendVerificationEmail sends (or re-sends) the email verification link
+func (s *UserServiceImpl) SendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if user.IsEmailVerified() {
+		return services.ErrEmailAlreadyVerified
+	}
+	if !hasRealEmail(user) {
+		return errors.New("บัญชีนี้ไม่มีอีเมล")
+	}
+	return s.sendEmailLink(ctx, user, models.EmailTokenVerifyEmail)
+}
+
+// VerifyEmail marks the user's email verified with a link from SendVerificationEmail
+func (s *UserServiceImpl) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	user, err := s.consumeEmailToken(ctx, token, models.EmailTokenVerifyEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		user.UpdatedAt = now
+		if err := s.userRepo.Update(ctx, user.ID, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// ForgotPassword emails a reset link to a local account. It succeeds whether
+// or not the email belongs to anyone, so it can't be used to probe accounts.
+func (s *UserServiceImpl) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || user.Password == "" || !user.IsActive {
+		return nil
+	}
+
+	s.sendEmailLinkInBackground(ctx, user, models.EmailTokenResetPassword)
+	return nil
+}
+
+// ResetPassword sets a new password with a link from ForgotPassword and signs
+// the user out everywhere
+func (s *UserServiceImpl) ResetPassword(ctx context.Context, token, newPassword string) error {
+	user, err := s.consumeEmailToken(ctx, token, models.EmailTokenResetPassword)
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return services.ErrAccountDisabled
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.Password = string(hashedPassword)
+	user.UpdatedAt = now
+	// Opening the link proves the user owns the address
+	if !user.IsEmailVerified() {
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(ctx, user.ID, user); err != nil {
+		return err
+	}
+
+	s.revokeAllSessions(ctx, user.ID, models.SessionRevokedPasswordReset)
+	return nil
+}
+
+// sendEmailLinkInBackground sends a link without holding up the request;
+// failures are logged
+func (s *UserServiceImpl) sendEmailLinkInBackground(ctx context.Context, user *models.User, purpose string) {
+	if !hasRealEmail(user) {
+		return
+	}
+
+	// The request context (a fasthttp.RequestCtx) is recycled once the
+	// handler returns, so only the request ID is carried over
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), logger.GetRequestID(ctx)), 30*time.Second)
+	go func() {
+		defer cancel()
+		if err := s.sendEmailLink(ctx, user, purpose); err != nil && !errors.Is(err, services.ErrEmailThrottled) {
+			logger.WarnContext(ctx, "Failed to send email",
+				"user_id", user.ID.String(),
+				"purpose", purpose,
+				"error", err.Error(),
+			)
+		}
+	}()
+}
+
+// sendEmailLink issues a single-use token and emails its link in the user's language
+func (s *UserServiceImpl) sendEmailLink(ctx context.Context, user *models.User, purpose string) error {
+	link, ok := emailLinks[purpose]
+	if !ok {
+		return fmt.Errorf("unknown email token purpose %q", purpose)
+	}
+
+	if s.redisClient != nil {
+		allowed, err := s.redisClient.SetNX(ctx, cache.MailThrottleKey(purpose, user.ID.String()), "1", mailThrottle).Result()
+		if err == nil && !allowed {
+			return services.ErrEmailThrottled
+		}
+	}
+
+	ttl := link.ttl(s)
+	now := time.Now()
+	record := &models.EmailToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := s.emailTokenRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	claims := emailTokenClaims{
+		Purpose: purpose,
+		Email:   user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        record.ID.String(),
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(record.ExpiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.signingKey(purpose))
+	if err != nil {
+		return err
+	}
+
+	lang := user.Language
+	msg, err := mail.Render(link.template, lang, mail.TemplateData{
+		AppName:   s.mailConfig.FromName,
+		Name:      displayName(user),
+		Link:      fmt.Sprintf("%s%s?token=%s", strings.TrimRight(s.frontendURL, "/"), link.path, url.QueryEscape(token)),
+		ExpiresIn: mail.HumanDuration(ttl, lang),
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	msg.ToName = displayName(user)
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "Email sent",
+		"user_id", user.ID.String(),
+		"purpose", purpose,
+	)
+	return nil
+}
+
+// consumeEmailToken checks a token from an emailed link and uses it up
+func (s *UserServiceImpl) consumeEmailToken(ctx context.Context, token, purpose string) (*models.User, error) {
+	var claims emailTokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return s.signingKey(purpose), nil
+	})
+	if err != nil || claims.Purpose != purpose {
+		return nil, services.ErrInvalidEmailToken
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, services.ErrInvalidEmailToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, services.ErrInvalidEmailToken
+	}
+
+	record, err := s.emailTokenRepo.GetByID(ctx, tokenID)
+	if err != nil || record.UserID != userID || record.Purpose != purpose {
+		return nil, services.ErrInvalidEmailToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || !strings.EqualFold(user.Email, claims.Email) {
+		return nil, services.ErrInvalidEmailToken
+	}
+
+	consumed, err := s.emailTokenRepo.Consume(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, services.ErrInvalidEmailToken
+	}
+
+	return user, nil
+}
+
+// signingKey derives a signing key per purpose, so an emailed or link token
+// can't pass as an access token or as a token for another purpose
+func (s *UserServiceImpl) signingKey(purpose string) []byte {
+	return []byte(s.jwtConfig.Secret + ":" + purpose)
+}
+
+func hasRealEmail(user *models.User) bool {
+	return user.Email != "" && !strings.HasSuffix(user.Email, placeholderEmailDomain)
+}
+
+func displayName(user *models.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.Username
+}
@@ -0,0 +1,299 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+)
+
+// identityTokenTTL bounds both the OAuth round trip when linking and the time
+// the user has to confirm a merge
+const identityTokenTTL = 10 * time.Minute
+
+// Purposes of identity tokens, also used to derive their signing keys
+const (
+	purposeLinkIdentity = "link_identity"
+	purposeMergeAccount = "merge_account"
+)
+
+// identityClaims is signed into the OAuth state when linking (Subject is the
+// signed-in user) and into the merge token handed back when the provider
+// account already belongs to someone else (SourceUserID is that account).
+type identityClaims struct {
+	Purpose      string `json:"purpose"`
+	Provider     string `json:"provider"`
+	SourceUserID string `json:"src,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ListIdentities returns the user's linked accounts and whether they have a password
+func (s *UserServiceImpl) ListIdentities(ctx context.Context, userID uuid.UUID) (*dto.IdentityListResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+
+	identities, err := s.identityRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.IdentityListResponse{
+		Identities:  make([]dto.IdentityResponse, 0, len(identities)),
+		HasPassword: user.HasPassword(),
+	}
+	for _, identity := range identities {
+		resp.Identities = append(resp.Identities, dto.IdentityToIdentityResponse(identity))
+	}
+	return resp, nil
+}
+
+// CreateIdentityLinkToken starts linking a provider account to the signed-in
+// user, who may already have others of the same provider linked. The token
+// goes into the OAuth state so the callback knows who to link to.
+func (s *UserServiceImpl) CreateIdentityLinkToken(ctx context.Context, userID uuid.UUID, provider string) (string, error) {
+	if !isSupportedProvider(provider) {
+		return "", services.ErrUnsupportedProvider
+	}
+
+	return s.signIdentityToken(identityClaims{
+		Purpose:  purposeLinkIdentity,
+		Provider: provider,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: userID.String(),
+		},
+	})
+}
+
+// LinkIdentity finishes linking after the provider redirects back. If the
+// provider account already signs in to another user, nothing is linked and
+// a merge token is returned: the user has now proved they own both accounts.
+func (s *UserServiceImpl) LinkIdentity(ctx context.Context, provider, code string, stateData dto.OAuthStateData) (*dto.LinkIdentityResult, error) {
+	claims, err := s.parseIdentityToken(stateData.LinkToken, purposeLinkIdentity)
+	if err != nil || claims.Provider != provider {
+		return nil, services.ErrInvalidLinkToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, services.ErrInvalidLinkToken
+	}
+
+	subject, email, err := s.fetchProviderIdentity(ctx, provider, code)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.LinkIdentityResult{Provider: provider}
+
+	existing, err := s.identityRepo.GetByProviderSubject(ctx, provider, subject)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.UserID == userID {
+			return result, nil
+		}
+
+		result.MergeToken, err = s.signIdentityToken(identityClaims{
+			Purpose:      purposeMergeAccount,
+			Provider:     provider,
+			SourceUserID: existing.UserID.String(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: userID.String(),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if err := s.identityRepo.Create(ctx, newIdentity(userID, provider, subject, email)); err != nil {
+		// The unique (provider, subject) index catches a link finished in another tab
+		return nil, fmt.Errorf("failed to link account: %w", err)
+	}
+
+	logger.InfoContext(ctx, "Identity linked",
+		"user_id", userID.String(),
+		"provider", provider,
+	)
+	return result, nil
+}
+
+// UnlinkIdentity removes a linked account, unless it is the user's only way to sign in
+func (s *UserServiceImpl) UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New("ไม่พบผู้ใช้งาน")
+	}
+
+	identities, err := s.identityRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var unlinked *models.UserIdentity
+	for _, identity := range identities {
+		if identity.ID == identityID {
+			unlinked = identity
+		}
+	}
+	if unlinked == nil {
+		return services.ErrIdentityNotFound
+	}
+	if len(identities) == 1 && !user.HasPassword() {
+		return services.ErrLastLoginMethod
+	}
+
+	deleted, err := s.identityRepo.Delete(ctx, userID, identityID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return services.ErrIdentityNotFound
+	}
+
+	logger.InfoContext(ctx, "Identity unlinked",
+		"user_id", userID.String(),
+		"provider", unlinked.Provider,
+	)
+	return nil
+}
+
+// MergeAccounts folds the account named in a merge token into the signed-in
+// user: its folders, favorites, history, chat sessions and logins move over,
+// then it is deleted and signed out everywhere.
+func (s *UserServiceImpl) MergeAccounts(ctx context.Context, userID uuid.UUID, mergeToken string) (*models.User, error) {
+	claims, err := s.parseIdentityToken(mergeToken, purposeMergeAccount)
+	if err != nil || claims.Subject != userID.String() {
+		return nil, services.ErrInvalidLinkToken
+	}
+	sourceID, err := uuid.Parse(claims.SourceUserID)
+	if err != nil || sourceID == userID {
+		return nil, services.ErrInvalidLinkToken
+	}
+
+	target, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+	source, err := s.userRepo.GetByID(ctx, sourceID)
+	if err != nil {
+		// Already merged, or deleted since the token was issued
+		return nil, services.ErrInvalidLinkToken
+	}
+	if !source.IsActive {
+		return nil, services.ErrAccountDisabled
+	}
+
+	// Keep the target's details, filling gaps from the source
+	if !target.HasPassword() && source.HasPassword() {
+		target.Password = source.Password
+	}
+	if target.StudentID == nil && source.StudentID != nil {
+		target.StudentID = source.StudentID
+	}
+	if !hasRealEmail(target) && hasRealEmail(source) {
+		target.Email = source.Email
+		target.EmailVerifiedAt = source.EmailVerifiedAt
+	} else if !target.IsEmailVerified() && source.IsEmailVerified() && strings.EqualFold(target.Email, source.Email) {
+		target.EmailVerifiedAt = source.EmailVerifiedAt
+	}
+	if target.Avatar == "" {
+		target.Avatar = source.Avatar
+	}
+	target.UpdatedAt = time.Now()
+
+	s.revokeAllSessions(ctx, source.ID, models.SessionRevokedMerged)
+
+	if err := s.userRepo.MergeInto(ctx, source, target); err != nil {
+		return nil, fmt.Errorf("failed to merge accounts: %w", err)
+	}
+
+	logger.InfoContext(ctx, "Accounts merged",
+		"user_id", target.ID.String(),
+		"merged_user_id", source.ID.String(),
+		"provider", claims.Provider,
+	)
+	return target, nil
+}
+
+// fetchProviderIdentity exchanges an authorization code for the provider's
+// user ID and email
+func (s *UserServiceImpl) fetchProviderIdentity(ctx context.Context, provider, code string) (string, string, error) {
+	switch provider {
+	case models.IdentityProviderGoogle:
+		googleUser, err := s.fetchGoogleUser(ctx, code)
+		if err != nil {
+			return "", "", err
+		}
+		return googleUser.ID, googleUser.Email, nil
+	case models.IdentityProviderLine:
+		lineUser, err := s.fetchLineUser(code)
+		if err != nil {
+			return "", "", err
+		}
+		return lineUser.ID, lineUser.Email, nil
+	}
+	return "", "", services.ErrUnsupportedProvider
+}
+
+// userByIdentity returns the user a provider account signs in to, or nil
+// when it isn't linked to anyone
+func (s *UserServiceImpl) userByIdentity(ctx context.Context, provider, subject string) (*models.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, provider, subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(ctx, identity.UserID)
+}
+
+func (s *UserServiceImpl) signIdentityToken(claims identityClaims) (string, error) {
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(identityTokenTTL))
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.signingKey(claims.Purpose))
+}
+
+func (s *UserServiceImpl) parseIdentityToken(token, purpose string) (*identityClaims, error) {
+	var claims identityClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return s.signingKey(purpose), nil
+	})
+	if err != nil || claims.Purpose != purpose {
+		return nil, services.ErrInvalidLinkToken
+	}
+	return &claims, nil
+}
+
+func newIdentity(userID uuid.UUID, provider, subject, email string) *models.UserIdentity {
+	return &models.UserIdentity{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+		LinkedAt: time.Now(),
+	}
+}
+
+func isSupportedProvider(provider string) bool {
+	return provider == models.IdentityProviderGoogle || provider == models.IdentityProviderLine
+}
@@ -0,0 +1,397 @@
+package serviceimpl
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/websocket"
+	"gofiber-template/pkg/logger"
+)
+
+const (
+	// dataExportBuildTimeout bounds building one export. Exports pending for
+	// longer were cut off by a restart and count as failed.
+	dataExportBuildTimeout = 30 * time.Minute
+	// purgeBatchSize limits how many accounts one PurgeDueAccounts run deletes
+	purgeBatchSize = 50
+)
+
+// RequestDataExport starts building a ZIP of everything stored about the
+// user. The user is told over WebSocket when it's ready to download.
+func (s *UserServiceImpl) RequestDataExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error) {
+	if s.r2Storage == nil {
+		return nil, services.ErrStorageUnavailable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+
+	exports, err := s.dataExportRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, export := range exports {
+		if export.Status == models.DataExportPending && time.Since(export.CreatedAt) < dataExportBuildTimeout {
+			return nil, services.ErrDataExportInProgress
+		}
+	}
+
+	now := time.Now()
+	export := &models.DataExport{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    models.DataExportPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.dataExportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "Data export requested",
+		"user_id", userID.String(),
+		"export_id", export.ID.String(),
+	)
+
+	// The request context (a fasthttp.RequestCtx) is recycled once the
+	// handler returns, so only the request ID is carried over
+	buildCtx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), logger.GetRequestID(ctx)), dataExportBuildTimeout)
+	build := *export
+	go func() {
+		defer cancel()
+		s.buildDataExport(buildCtx, user, &build)
+	}()
+
+	return export, nil
+}
+
+func (s *UserServiceImpl) ListDataExports(ctx context.Context, userID uuid.UUID) ([]*models.DataExport, error) {
+	return s.dataExportRepo.ListByUserID(ctx, userID)
+}
+
+func (s *UserServiceImpl) OpenDataExport(ctx context.Context, userID, exportID uuid.UUID) (*models.DataExport, io.ReadCloser, error) {
+	export, err := s.dataExportRepo.GetByID(ctx, exportID)
+	if err != nil || export.UserID != userID || !export.IsDownloadable() {
+		return nil, nil, services.ErrDataExportNotFound
+	}
+	if s.r2Storage == nil {
+		return nil, nil, services.ErrStorageUnavailable
+	}
+
+	body, err := s.r2Storage.DownloadFile(export.ObjectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return export, body, nil
+}
+
+// buildDataExport writes the export, saves how it went and tells the user
+func (s *UserServiceImpl) buildDataExport(ctx context.Context, user *models.User, export *models.DataExport) {
+	objectKey := fmt.Sprintf("exports/%s/%s.zip", user.ID.String(), export.ID.String())
+	size, err := s.writeDataExport(ctx, user, objectKey)
+
+	now := time.Now()
+	expiresAt := now.Add(s.privacyConfig.ExportTTL)
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.UpdatedAt = now
+
+	messageType := "data_export_ready"
+	if err != nil {
+		messageType = "data_export_failed"
+		export.Status = models.DataExportFailed
+		export.Error = err.Error()
+		logger.ErrorContext(ctx, "Data export failed",
+			"user_id", user.ID.String(),
+			"export_id", export.ID.String(),
+			"error", err.Error(),
+		)
+	} else {
+		export.Status = models.DataExportReady
+		export.ObjectKey = objectKey
+		export.Size = size
+		logger.InfoContext(ctx, "Data export ready",
+			"user_id", user.ID.String(),
+			"export_id", export.ID.String(),
+			"size", size,
+		)
+	}
+
+	if err := s.dataExportRepo.Update(ctx, export); err != nil {
+		logger.WarnContext(ctx, "Failed to save data export",
+			"export_id", export.ID.String(),
+			"error", err.Error(),
+		)
+		return
+	}
+
+	websocket.Manager.BroadcastToUser(user.ID, messageType, dto.DataExportToDataExportResponse(export))
+}
+
+// writeDataExport builds the ZIP (a JSON file per table under data/, the
+// user's uploads under files/ and their avatar) and uploads it
+func (s *UserServiceImpl) writeDataExport(ctx context.Context, user *models.User, objectKey string) (int64, error) {
+	sets, err := s.personalDataRepo.Export(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, set := range sets {
+		w, err := zw.Create("data/" + set.Name + ".json")
+		if err != nil {
+			return 0, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(set.Rows); err != nil {
+			return 0, err
+		}
+
+		switch set.Name {
+		case "files":
+			for _, row := range set.Rows {
+				id, _ := row["id"].(string)
+				cdnPath, _ := row["cdn_path"].(string)
+				fileName, _ := row["file_name"].(string)
+				if cdnPath == "" {
+					continue
+				}
+				if err := s.copyObjectToZip(zw, cdnPath, "files/"+id+"-"+path.Base(fileName)); err != nil {
+					return 0, err
+				}
+			}
+		case "folder_items":
+			// Folder uploads are only referenced by their public URL
+			for _, row := range set.Rows {
+				id, _ := row["id"].(string)
+				url, _ := row["url"].(string)
+				key := s.r2ObjectKey(url)
+				if key == "" {
+					continue
+				}
+				if err := s.copyObjectToZip(zw, key, "folder_items/"+id+path.Ext(key)); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+
+	if key := s.r2ObjectKey(user.Avatar); key != "" {
+		if err := s.copyObjectToZip(zw, key, "avatar"+path.Ext(key)); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	size := int64(buf.Len())
+	if _, err := s.r2Storage.UploadFile(&buf, objectKey, "application/zip"); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func (s *UserServiceImpl) copyObjectToZip(zw *zip.Writer, objectKey, name string) error {
+	body, err := s.r2Storage.DownloadFile(objectKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, body)
+	return err
+}
+
+// r2ObjectKey returns the storage key behind a public R2 URL, or "" for none
+// or a URL hosted elsewhere, such as a Google/LINE avatar or a saved link
+func (s *UserServiceImpl) r2ObjectKey(url string) string {
+	if url == "" || s.r2PublicURL == "" || !strings.HasPrefix(url, s.r2PublicURL+"/") {
+		return ""
+	}
+	return strings.TrimPrefix(url, s.r2PublicURL+"/")
+}
+
+// CleanupDataExports deletes expired exports from storage and the database,
+// after failing exports whose build was cut off
+func (s *UserServiceImpl) CleanupDataExports(ctx context.Context) (int, error) {
+	now := time.Now()
+	if _, err := s.dataExportRepo.FailStale(ctx, now.Add(-dataExportBuildTimeout), now.Add(s.privacyConfig.ExportTTL)); err != nil {
+		return 0, err
+	}
+
+	expired, err := s.dataExportRepo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, export := range expired {
+		if export.ObjectKey != "" {
+			if s.r2Storage == nil {
+				continue
+			}
+			if err := s.r2Storage.DeleteFile(export.ObjectKey); err != nil {
+				logger.WarnContext(ctx, "Failed to delete data export file",
+					"export_id", export.ID.String(),
+					"error", err.Error(),
+				)
+				continue
+			}
+		}
+		if err := s.dataExportRepo.Delete(ctx, export.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// ScheduleDeletion marks the account for deletion after the grace period.
+// The user stays signed in, so they can still download their data or undo
+// it; nothing is deleted until PurgeDueAccounts runs.
+func (s *UserServiceImpl) ScheduleDeletion(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if user.IsDeletionScheduled() {
+		return user, nil
+	}
+
+	at := time.Now().Add(s.privacyConfig.DeletionGracePeriod)
+	if err := s.userRepo.SetDeletionScheduledAt(ctx, userID, &at); err != nil {
+		return nil, err
+	}
+	user.DeletionScheduledAt = &at
+
+	logger.InfoContext(ctx, "Account deletion scheduled",
+		"user_id", userID.String(),
+		"purge_at", at.Format(time.RFC3339),
+	)
+	return user, nil
+}
+
+func (s *UserServiceImpl) RestoreAccount(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if !user.IsDeletionScheduled() {
+		return nil, services.ErrDeletionNotScheduled
+	}
+
+	if err := s.userRepo.SetDeletionScheduledAt(ctx, userID, nil); err != nil {
+		return nil, err
+	}
+	user.DeletionScheduledAt = nil
+
+	logger.InfoContext(ctx, "Account deletion cancelled", "user_id", userID.String())
+	return user, nil
+}
+
+// PurgeDueAccounts deletes accounts whose grace period is over. An account
+// that fails is left as is and retried on the next run.
+func (s *UserServiceImpl) PurgeDueAccounts(ctx context.Context) (int, error) {
+	users, err := s.userRepo.ListDueForDeletion(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		done, err := s.purgeAccount(ctx, user.ID)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to purge account",
+				"user_id", user.ID.String(),
+				"error", err.Error(),
+			)
+			continue
+		}
+		if done {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgeAccount deletes the user's rows and then the user's objects. Storage
+// goes last so a failed purge leaves files and rows together for the next
+// retry; an object that fails to delete after the commit is only logged.
+func (s *UserServiceImpl) purgeAccount(ctx context.Context, userID uuid.UUID) (bool, error) {
+	// The user may have restored the account since it was listed
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !user.IsDeletionScheduled() || user.DeletionScheduledAt.After(time.Now()) {
+		return false, nil
+	}
+
+	keys, err := s.personalDataRepo.ObjectKeys(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	itemURLs, err := s.personalDataRepo.FolderItemURLs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, url := range itemURLs {
+		if key := s.r2ObjectKey(url); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if key := s.r2ObjectKey(user.Avatar); key != "" {
+		keys = append(keys, key)
+	}
+	if len(keys) > 0 && s.r2Storage == nil {
+		return false, services.ErrStorageUnavailable
+	}
+
+	// Cut off access tokens already handed out; the session rows themselves
+	// go with the user
+	s.revokeAllSessions(ctx, userID, models.SessionRevokedDeactivated)
+
+	if err := s.personalDataRepo.Purge(ctx, userID); err != nil {
+		return false, err
+	}
+
+	for _, key := range keys {
+		if err := s.r2Storage.DeleteFile(key); err != nil {
+			logger.ErrorContext(ctx, "Failed to delete object of purged account",
+				"user_id", userID.String(),
+				"key", key,
+				"error", err.Error(),
+			)
+		}
+	}
+
+	logger.InfoContext(ctx, "Account purged",
+		"user_id", userID.String(),
+		"objects", len(keys),
+	)
+	return true, nil
+}
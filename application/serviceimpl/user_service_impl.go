@@ -9,31 +9,69 @@ import (
 	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
 	"gofiber-template/domain/dto"
 	"gofiber-template/domain/models"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/mail"
 	"gofiber-template/infrastructure/storage"
+	"gofiber-template/pkg/config"
 	"gofiber-template/pkg/oauth"
 )
 
 type UserServiceImpl struct {
-	userRepo    repositories.UserRepository
-	jwtSecret   string
-	r2Storage   storage.R2Storage
-	r2PublicURL string
+	userRepo         repositories.UserRepository
+	sessionRepo      repositories.SessionRepository
+	emailTokenRepo   repositories.EmailTokenRepository
+	identityRepo     repositories.UserIdentityRepository
+	recoveryCodeRepo repositories.RecoveryCodeRepository
+	dataExportRepo   repositories.DataExportRepository
+	personalDataRepo repositories.PersonalDataRepository
+	redisClient      *redis.Client
+	r2Storage        storage.R2Storage
+	mailer           mail.Mailer
+	jwtConfig        config.JWTConfig
+	twoFactorConfig  config.TwoFactorConfig
+	mailConfig       config.MailConfig
+	privacyConfig    config.PrivacyConfig
+	frontendURL      string
+	r2PublicURL      string
 }
 
-func NewUserService(userRepo repositories.UserRepository, jwtSecret string, r2Storage storage.R2Storage, r2PublicURL string) services.UserService {
+func NewUserService(
+	userRepo repositories.UserRepository,
+	sessionRepo repositories.SessionRepository,
+	emailTokenRepo repositories.EmailTokenRepository,
+	identityRepo repositories.UserIdentityRepository,
+	recoveryCodeRepo repositories.RecoveryCodeRepository,
+	dataExportRepo repositories.DataExportRepository,
+	personalDataRepo repositories.PersonalDataRepository,
+	redisClient *redis.Client,
+	r2Storage storage.R2Storage,
+	mailer mail.Mailer,
+	cfg *config.Config,
+) services.UserService {
 	return &UserServiceImpl{
-		userRepo:    userRepo,
-		jwtSecret:   jwtSecret,
-		r2Storage:   r2Storage,
-		r2PublicURL: r2PublicURL,
+		userRepo:         userRepo,
+		sessionRepo:      sessionRepo,
+		emailTokenRepo:   emailTokenRepo,
+		identityRepo:     identityRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		dataExportRepo:   dataExportRepo,
+		personalDataRepo: personalDataRepo,
+		redisClient:      redisClient,
+		r2Storage:        r2Storage,
+		mailer:           mailer,
+		jwtConfig:        cfg.JWT,
+		twoFactorConfig:  cfg.TwoFactor,
+		mailConfig:       cfg.Mail,
+		privacyConfig:    cfg.Privacy,
+		frontendURL:      cfg.App.FrontendURL,
+		r2PublicURL:      cfg.R2.PublicURL,
 	}
 }
 
@@ -79,30 +117,33 @@ func (s *UserServiceImpl) Register(ctx context.Context, req *dto.CreateUserReque
 		return nil, err
 	}
 
+	s.sendEmailLinkInBackground(ctx, user, models.EmailTokenVerifyEmail)
+
 	return user, nil
 }
 
-func (s *UserServiceImpl) Login(ctx context.Context, req *dto.LoginRequest) (string, *models.User, error) {
+func (s *UserServiceImpl) Login(ctx context.Context, req *dto.LoginRequest, client dto.ClientInfo) (*dto.AuthTokens, *models.User, error) {
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return "", nil, errors.New("อีเมลหรือรหัสผ่านไม่ถูกต้อง")
+		return nil, nil, errors.New("อีเมลหรือรหัสผ่านไม่ถูกต้อง")
 	}
 
 	if !user.IsActive {
-		return "", nil, errors.New("บัญชีนี้ถูกระงับการใช้งาน")
+		return nil, nil, services.ErrAccountDisabled
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return "", nil, errors.New("อีเมลหรือรหัสผ่านไม่ถูกต้อง")
+		return nil, nil, errors.New("อีเมลหรือรหัสผ่านไม่ถูกต้อง")
 	}
 
-	token, err := s.GenerateJWT(user)
+	client.AuthProvider = "email"
+	tokens, err := s.signIn(ctx, user, client)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	return token, user, nil
+	return tokens, user, nil
 }
 
 func (s *UserServiceImpl) GetProfile(ctx context.Context, userID uuid.UUID) (*models.User, error) {
@@ -139,8 +180,25 @@ func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID uuid.UUID, r
 	return user, nil
 }
 
-func (s *UserServiceImpl) DeleteUser(ctx context.Context, userID uuid.UUID) error {
-	return s.userRepo.Delete(ctx, userID)
+// SetUserActive activates or deactivates an account. Deactivating signs the
+// user out everywhere right away instead of when their tokens expire.
+func (s *UserServiceImpl) SetUserActive(ctx context.Context, userID uuid.UUID, active bool) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+
+	if err := s.userRepo.SetActive(ctx, userID, active); err != nil {
+		return nil, err
+	}
+	user.IsActive = active
+	user.UpdatedAt = time.Now()
+
+	if !active {
+		s.revokeAllSessions(ctx, userID, models.SessionRevokedDeactivated)
+	}
+
+	return user, nil
 }
 
 func (s *UserServiceImpl) ListUsers(ctx context.Context, offset, limit int) ([]*models.User, int64, error) {
@@ -157,116 +215,78 @@ func (s *UserServiceImpl) ListUsers(ctx context.Context, offset, limit int) ([]*
 	return users, count, nil
 }
 
-func (s *UserServiceImpl) GenerateJWT(user *models.User) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id":  user.ID.String(),
-		"username": user.Username,
-		"email":    user.Email,
-		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(),
-		"iat":      time.Now().Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
-	if err != nil {
-		return "", err
-	}
+// ==================== OAuth Methods ====================
 
-	return tokenString, nil
+// GetGoogleAuthURL returns Google OAuth authorization URL
+func (s *UserServiceImpl) GetGoogleAuthURL(state string) string {
+	return oauth.GoogleOAuthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
 }
 
-func (s *UserServiceImpl) ValidateJWT(tokenString string) (*models.User, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("รูปแบบ token ไม่ถูกต้อง")
-		}
-		return []byte(s.jwtSecret), nil
-	})
-
+// HandleGoogleCallback handles Google OAuth callback
+func (s *UserServiceImpl) HandleGoogleCallback(ctx context.Context, code string, stateData dto.OAuthStateData, client dto.ClientInfo) (*dto.AuthTokens, *models.User, bool, error) {
+	googleUser, err := s.fetchGoogleUser(ctx, code)
 	if err != nil {
-		return nil, err
+		return nil, nil, false, err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userIDStr, ok := claims["user_id"].(string)
-		if !ok {
-			return nil, errors.New("ข้อมูล token ไม่ถูกต้อง")
-		}
-
-		userID, err := uuid.Parse(userIDStr)
-		if err != nil {
-			return nil, errors.New("รหัสผู้ใช้ใน token ไม่ถูกต้อง")
-		}
-
-		user, err := s.userRepo.GetByID(context.Background(), userID)
-		if err != nil {
-			return nil, errors.New("ไม่พบผู้ใช้งาน")
-		}
-
-		return user, nil
+	// Find or create user
+	user, isNewUser, err := s.findOrCreateGoogleUser(ctx, googleUser)
+	if err != nil {
+		return nil, nil, false, err
 	}
 
-	return nil, errors.New("token ไม่ถูกต้อง")
-}
-
-// ==================== OAuth Methods ====================
+	// Sign in
+	client.AuthProvider = "google"
+	tokens, err := s.signIn(ctx, user, client)
+	if err != nil {
+		return nil, nil, false, err
+	}
 
-// GetGoogleAuthURL returns Google OAuth authorization URL
-func (s *UserServiceImpl) GetGoogleAuthURL(state string) string {
-	return oauth.GoogleOAuthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	return tokens, user, isNewUser, nil
 }
 
-// HandleGoogleCallback handles Google OAuth callback
-func (s *UserServiceImpl) HandleGoogleCallback(ctx context.Context, code string, stateData dto.OAuthStateData) (string, *models.User, bool, error) {
+// fetchGoogleUser exchanges an authorization code for the Google user's info
+func (s *UserServiceImpl) fetchGoogleUser(ctx context.Context, code string) (*dto.GoogleUserInfo, error) {
 	// Exchange code for token
 	token, err := oauth.GoogleOAuthConfig.Exchange(ctx, code)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("failed to exchange token: %w", err)
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
 	}
 
 	// Get user info from Google
-	client := oauth.GoogleOAuthConfig.Client(ctx, token)
-	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+	httpClient := oauth.GoogleOAuthConfig.Client(ctx, token)
+	resp, err := httpClient.Get("https://www.googleapis.com/oauth2/v2/userinfo")
 	if err != nil {
-		return "", nil, false, fmt.Errorf("failed to get user info: %w", err)
+		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var googleUser dto.GoogleUserInfo
 	if err := json.NewDecoder(resp.Body).Decode(&googleUser); err != nil {
-		return "", nil, false, fmt.Errorf("failed to decode user info: %w", err)
-	}
-
-	// Find or create user
-	user, isNewUser, err := s.findOrCreateGoogleUser(ctx, &googleUser)
-	if err != nil {
-		return "", nil, false, err
-	}
-
-	// Generate JWT
-	jwtToken, err := s.GenerateJWT(user)
-	if err != nil {
-		return "", nil, false, fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to decode user info: %w", err)
 	}
 
-	return jwtToken, user, isNewUser, nil
+	return &googleUser, nil
 }
 
 // findOrCreateGoogleUser finds existing user or creates new one from Google OAuth
 func (s *UserServiceImpl) findOrCreateGoogleUser(ctx context.Context, googleUser *dto.GoogleUserInfo) (*models.User, bool, error) {
-	// Try to find by Google ID
-	existingUser, _ := s.userRepo.GetByGoogleID(ctx, googleUser.ID)
-	if existingUser != nil {
-		return existingUser, false, nil
+	// Try to find by linked Google account
+	if user, err := s.userByIdentity(ctx, models.IdentityProviderGoogle, googleUser.ID); user != nil || err != nil {
+		return user, false, err
 	}
 
 	// Try to find by email
-	existingUser, _ = s.userRepo.GetByEmail(ctx, googleUser.Email)
+	existingUser, _ := s.userRepo.GetByEmail(ctx, googleUser.Email)
 	if existingUser != nil {
 		// Link Google account to existing user
-		existingUser.GoogleID = &googleUser.ID
-		existingUser.AuthProvider = "google"
+		if err := s.identityRepo.Create(ctx, newIdentity(existingUser.ID, models.IdentityProviderGoogle, googleUser.ID, googleUser.Email)); err != nil {
+			return nil, false, fmt.Errorf("failed to link Google account: %w", err)
+		}
+		if googleUser.VerifiedEmail && !existingUser.IsEmailVerified() {
+			now := time.Now()
+			existingUser.EmailVerifiedAt = &now
+		}
 		if existingUser.Avatar == "" {
 			existingUser.Avatar = googleUser.Picture
 		}
@@ -288,13 +308,16 @@ func (s *UserServiceImpl) findOrCreateGoogleUser(ctx context.Context, googleUser
 		Avatar:       googleUser.Picture,
 		Role:         "user",
 		IsActive:     true,
-		GoogleID:     &googleUser.ID,
 		AuthProvider: "google",
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
+	if googleUser.VerifiedEmail {
+		newUser.EmailVerifiedAt = &newUser.CreatedAt
+	}
 
-	if err := s.userRepo.Create(ctx, newUser); err != nil {
+	identity := newIdentity(newUser.ID, models.IdentityProviderGoogle, googleUser.ID, googleUser.Email)
+	if err := s.userRepo.CreateWithIdentity(ctx, newUser, identity); err != nil {
 		return nil, false, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -307,54 +330,65 @@ func (s *UserServiceImpl) GetLineAuthURL(state string) string {
 }
 
 // HandleLineCallback handles LINE OAuth callback
-func (s *UserServiceImpl) HandleLineCallback(ctx context.Context, code string, stateData dto.OAuthStateData) (string, *models.User, bool, error) {
-	// Exchange code for token
-	tokenResp, err := oauth.LineOAuthConfig.ExchangeCodeForToken(code)
+func (s *UserServiceImpl) HandleLineCallback(ctx context.Context, code string, stateData dto.OAuthStateData, client dto.ClientInfo) (*dto.AuthTokens, *models.User, bool, error) {
+	lineUser, err := s.fetchLineUser(code)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("failed to exchange token: %w", err)
+		return nil, nil, false, err
 	}
 
-	// Get user info from LINE
-	lineUser, err := oauth.LineOAuthConfig.GetUserInfo(tokenResp)
+	// Find or create user
+	user, isNewUser, err := s.findOrCreateLineUser(ctx, lineUser)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("failed to get user info: %w", err)
+		return nil, nil, false, err
 	}
 
-	// Find or create user
-	user, isNewUser, err := s.findOrCreateLineUser(ctx, lineUser)
+	// Sign in
+	client.AuthProvider = "line"
+	tokens, err := s.signIn(ctx, user, client)
 	if err != nil {
-		return "", nil, false, err
+		return nil, nil, false, err
 	}
 
-	// Generate JWT
-	jwtToken, err := s.GenerateJWT(user)
+	return tokens, user, isNewUser, nil
+}
+
+// fetchLineUser exchanges an authorization code for the LINE user's info
+func (s *UserServiceImpl) fetchLineUser(code string) (*dto.LineUserInfo, error) {
+	// Exchange code for token
+	tokenResp, err := oauth.LineOAuthConfig.ExchangeCodeForToken(code)
 	if err != nil {
-		return "", nil, false, fmt.Errorf("failed to generate token: %w", err)
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
 	}
 
-	return jwtToken, user, isNewUser, nil
+	// Get user info from LINE
+	lineUser, err := oauth.LineOAuthConfig.GetUserInfo(tokenResp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	return lineUser, nil
 }
 
 // findOrCreateLineUser finds existing user or creates new one from LINE OAuth
 func (s *UserServiceImpl) findOrCreateLineUser(ctx context.Context, lineUser *dto.LineUserInfo) (*models.User, bool, error) {
-	// Try to find by LINE ID
-	existingUser, _ := s.userRepo.GetByLineID(ctx, lineUser.ID)
-	if existingUser != nil {
-		return existingUser, false, nil
+	// Try to find by linked LINE account
+	if user, err := s.userByIdentity(ctx, models.IdentityProviderLine, lineUser.ID); user != nil || err != nil {
+		return user, false, err
 	}
 
 	// Try to find by email (if available)
 	if lineUser.Email != "" {
-		existingUser, _ = s.userRepo.GetByEmail(ctx, lineUser.Email)
+		existingUser, _ := s.userRepo.GetByEmail(ctx, lineUser.Email)
 		if existingUser != nil {
 			// Link LINE account to existing user
-			existingUser.LineID = &lineUser.ID
-			existingUser.AuthProvider = "line"
+			if err := s.identityRepo.Create(ctx, newIdentity(existingUser.ID, models.IdentityProviderLine, lineUser.ID, lineUser.Email)); err != nil {
+				return nil, false, fmt.Errorf("failed to link LINE account: %w", err)
+			}
 			if existingUser.Avatar == "" {
 				existingUser.Avatar = lineUser.PictureURL
-			}
-			if err := s.userRepo.Update(ctx, existingUser.ID, existingUser); err != nil {
-				return nil, false, fmt.Errorf("failed to link LINE account: %w", err)
+				if err := s.userRepo.Update(ctx, existingUser.ID, existingUser); err != nil {
+					return nil, false, fmt.Errorf("failed to link LINE account: %w", err)
+				}
 			}
 			return existingUser, false, nil
 		}
@@ -371,7 +405,7 @@ func (s *UserServiceImpl) findOrCreateLineUser(ctx context.Context, lineUser *dt
 	// Generate email placeholder if no email from LINE
 	email := lineUser.Email
 	if email == "" {
-		email = fmt.Sprintf("line_%s@stou.placeholder", lineUser.ID[:8])
+		email = fmt.Sprintf("line_%s%s", lineUser.ID[:8], placeholderEmailDomain)
 	}
 
 	// Parse display name into first and last name
@@ -387,13 +421,13 @@ func (s *UserServiceImpl) findOrCreateLineUser(ctx context.Context, lineUser *dt
 		Avatar:       lineUser.PictureURL,
 		Role:         "user",
 		IsActive:     true,
-		LineID:       &lineUser.ID,
 		AuthProvider: "line",
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := s.userRepo.Create(ctx, newUser); err != nil {
+	identity := newIdentity(newUser.ID, models.IdentityProviderLine, lineUser.ID, lineUser.Email)
+	if err := s.userRepo.CreateWithIdentity(ctx, newUser, identity); err != nil {
 		return nil, false, fmt.Errorf("failed to create user: %w", err)
 	}
 
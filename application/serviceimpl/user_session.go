@@ -0,0 +1,284 @@
+package serviceimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/infrastructure/websocket"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
+)
+
+// IssueTokens starts a new session for the user and returns its first
+// access/refresh token pair
+func (s *UserServiceImpl) IssueTokens(ctx context.Context, user *models.User, client dto.ClientInfo) (*dto.AuthTokens, error) {
+	if !user.IsActive {
+		return nil, services.ErrAccountDisabled
+	}
+
+	provider := client.AuthProvider
+	if provider == "" {
+		provider = "email"
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		AuthProvider: provider,
+		UserAgent:    truncateUserAgent(client.UserAgent),
+		IPAddress:    client.IPAddress,
+		LastUsedAt:   now,
+		ExpiresAt:    now.Add(s.jwtConfig.RefreshTTL),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	refreshToken, stored, err := s.newRefreshToken(session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Create(ctx, session, stored); err != nil {
+		return nil, err
+	}
+
+	return s.tokenPair(user, session.ID, refreshToken)
+}
+
+// RefreshTokens exchanges a refresh token for a new pair. Each refresh token
+// works once: presenting one that was already exchanged means it leaked, so
+// the whole session is revoked and the user has to sign in again.
+func (s *UserServiceImpl) RefreshTokens(ctx context.Context, refreshToken string, client dto.ClientInfo) (*dto.AuthTokens, error) {
+	stored, err := s.sessionRepo.GetRefreshTokenByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, services.ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		s.revokeReusedSession(ctx, stored)
+		return nil, services.ErrRefreshTokenReused
+	}
+
+	now := time.Now()
+	if now.After(stored.ExpiresAt) {
+		return nil, services.ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, stored.SessionID)
+	if err != nil || !session.IsActive() {
+		return nil, services.ErrInvalidRefreshToken
+	}
+
+	// Reload the user so role changes and deactivation apply on refresh
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, services.ErrInvalidRefreshToken
+	}
+	if !user.IsActive {
+		s.revokeAllSessions(ctx, user.ID, models.SessionRevokedDeactivated)
+		return nil, services.ErrAccountDisabled
+	}
+
+	session.UserAgent = truncateUserAgent(client.UserAgent)
+	session.IPAddress = client.IPAddress
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(s.jwtConfig.RefreshTTL)
+
+	nextToken, next, err := s.newRefreshToken(session)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.sessionRepo.RotateRefreshToken(ctx, stored.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request exchanged the same token between our read and write
+		s.revokeReusedSession(ctx, stored)
+		return nil, services.ErrRefreshTokenReused
+	}
+
+	if err := s.sessionRepo.Touch(ctx, session); err != nil {
+		logger.WarnContext(ctx, "Failed to update session",
+			"session_id", session.ID.String(),
+			"error", err.Error(),
+		)
+	}
+
+	return s.tokenPair(user, session.ID, nextToken)
+}
+
+// Logout revokes the session an access token belongs to
+func (s *UserServiceImpl) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, models.SessionRevokedLogout); err != nil {
+		return err
+	}
+	s.cutOffSession(ctx, sessionID)
+	return nil
+}
+
+// LogoutByRefreshToken revokes the session a refresh token belongs to, for
+// clients whose access token has already expired
+func (s *UserServiceImpl) LogoutByRefreshToken(ctx context.Context, refreshToken string) error {
+	stored, err := s.sessionRepo.GetRefreshTokenByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return services.ErrInvalidRefreshToken
+	}
+	return s.Logout(ctx, stored.SessionID)
+}
+
+// ListSessions returns the devices the user is signed in on, most recently
+// used first
+func (s *UserServiceImpl) ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.UserSession, error) {
+	return s.sessionRepo.ListActiveByUserID(ctx, userID)
+}
+
+// RevokeSession signs one of the user's devices out
+func (s *UserServiceImpl) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || session.UserID != userID || !session.IsActive() {
+		return services.ErrSessionNotFound
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, models.SessionRevokedByUser); err != nil {
+		return err
+	}
+	s.cutOffSession(ctx, sessionID)
+	return nil
+}
+
+// RevokeOtherSessions signs the user out everywhere except the current device
+func (s *UserServiceImpl) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error) {
+	sessionIDs, err := s.sessionRepo.RevokeAllByUserID(ctx, userID, models.SessionRevokedByUser, &currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range sessionIDs {
+		s.cutOffSession(ctx, id)
+	}
+	return len(sessionIDs), nil
+}
+
+func (s *UserServiceImpl) revokeReusedSession(ctx context.Context, stored *models.RefreshToken) {
+	logger.WarnContext(ctx, "Refresh token reuse detected, revoking session",
+		"user_id", stored.UserID.String(),
+		"session_id", stored.SessionID.String(),
+	)
+
+	if err := s.sessionRepo.Revoke(ctx, stored.SessionID, models.SessionRevokedReuseDetected); err != nil {
+		logger.WarnContext(ctx, "Failed to revoke session",
+			"session_id", stored.SessionID.String(),
+			"error", err.Error(),
+		)
+	}
+	s.cutOffSession(ctx, stored.SessionID)
+}
+
+// revokeAllSessions signs the user out of every device. Failures are only
+// logged: the caller's own change (deactivation, deletion) must still go
+// through, and tokens expire within the access TTL anyway.
+func (s *UserServiceImpl) revokeAllSessions(ctx context.Context, userID uuid.UUID, reason string) {
+	sessionIDs, err := s.sessionRepo.RevokeAllByUserID(ctx, userID, reason, nil)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to revoke user sessions",
+			"user_id", userID.String(),
+			"error", err.Error(),
+		)
+		return
+	}
+	for _, id := range sessionIDs {
+		s.cutOffSession(ctx, id)
+	}
+}
+
+// cutOffSession ends what a revoked session still has open: it closes the
+// session's WebSockets and puts it on the Redis denylist that Protected and
+// Optional check, so its access tokens stop working before they expire
+func (s *UserServiceImpl) cutOffSession(ctx context.Context, sessionID uuid.UUID) {
+	websocket.Manager.CloseSession(sessionID)
+
+	if s.redisClient == nil {
+		return
+	}
+	key := cache.RevokedSessionKey(sessionID.String())
+	if err := s.redisClient.Set(ctx, key, "1", s.jwtConfig.AccessTTL).Err(); err != nil {
+		logger.WarnContext(ctx, "Failed to denylist session",
+			"session_id", sessionID.String(),
+			"error", err.Error(),
+		)
+	}
+}
+
+// tokenPair signs a short-lived access token for the session
+func (s *UserServiceImpl) tokenPair(user *models.User, sessionID uuid.UUID, refreshToken string) (*dto.AuthTokens, error) {
+	now := time.Now()
+	claims := utils.JWTClaims{
+		UserID:    user.ID.String(),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.jwtConfig.AccessTTL)),
+		},
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtConfig.Secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthTokens{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.jwtConfig.AccessTTL.Seconds()),
+		SessionID:    sessionID,
+	}, nil
+}
+
+// newRefreshToken generates an opaque refresh token for the session and the
+// row that stores its hash
+func (s *UserServiceImpl) newRefreshToken(session *models.UserSession) (string, *models.RefreshToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, &models.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncateUserAgent(ua string) string {
+	if len(ua) > 500 {
+		return ua[:500]
+	}
+	return ua
+}
@@ -0,0 +1,283 @@
+package serviceimpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/config"
+)
+
+// memorySessionRepository keeps sessions and refresh tokens in memory
+type memorySessionRepository struct {
+	sessions map[uuid.UUID]*models.UserSession
+	tokens   map[uuid.UUID]*models.RefreshToken
+	// raceOnRotate exchanges the old token elsewhere between the service's
+	// read and its rotation, like a second request with the same token
+	raceOnRotate bool
+}
+
+func newMemorySessionRepository() *memorySessionRepository {
+	return &memorySessionRepository{
+		sessions: make(map[uuid.UUID]*models.UserSession),
+		tokens:   make(map[uuid.UUID]*models.RefreshToken),
+	}
+}
+
+func (r *memorySessionRepository) Create(ctx context.Context, session *models.UserSession, token *models.RefreshToken) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *memorySessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.UserSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *memorySessionRepository) ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserSession, error) {
+	var out []*models.UserSession
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive() {
+			out = append(out, session)
+		}
+	}
+	return out, nil
+}
+
+func (r *memorySessionRepository) Touch(ctx context.Context, session *models.UserSession) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memorySessionRepository) Revoke(ctx context.Context, id uuid.UUID, reason string) error {
+	if session, ok := r.sessions[id]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+		session.RevokedReason = reason
+	}
+	return nil
+}
+
+func (r *memorySessionRepository) RevokeAllByUserID(ctx context.Context, userID uuid.UUID, reason string, exceptID *uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, session := range r.sessions {
+		if session.UserID != userID || session.RevokedAt != nil || (exceptID != nil && *exceptID == id) {
+			continue
+		}
+		_ = r.Revoke(ctx, id, reason)
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *memorySessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memorySessionRepository) RotateRefreshToken(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) (bool, error) {
+	old := r.tokens[oldID]
+	now := time.Now()
+	if r.raceOnRotate {
+		old.UsedAt = &now
+	}
+	if old.UsedAt != nil {
+		return false, nil
+	}
+	old.UsedAt = &now
+	r.tokens[next.ID] = next
+	return true, nil
+}
+
+// memoryUserRepository serves the users of the session tests
+type memoryUserRepository struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func newSessionTestService() (*UserServiceImpl, *memorySessionRepository, *models.User) {
+	user := &models.User{ID: uuid.New(), Username: "somchai", Role: "user", IsActive: true}
+	sessions := newMemorySessionRepository()
+	s := &UserServiceImpl{
+		userRepo:    &memoryUserRepository{users: map[uuid.UUID]*models.User{user.ID: user}},
+		sessionRepo: sessions,
+		jwtConfig: config.JWTConfig{
+			Secret:     "test-secret",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 30 * 24 * time.Hour,
+		},
+	}
+	return s, sessions, user
+}
+
+func TestRefreshTokens(t *testing.T) {
+	client := dto.ClientInfo{UserAgent: "test", IPAddress: "127.0.0.1"}
+
+	tests := []struct {
+		name string
+		// present returns the refresh token the client sends after signing in
+		// with first
+		present     func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string
+		wantErr     error
+		wantRevoked string // reason the session ends up revoked with, "" if still active
+	}{
+		{
+			name: "fresh token is exchanged",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				return first
+			},
+		},
+		{
+			name: "rotated token is exchanged once more",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				next, err := s.RefreshTokens(context.Background(), first, client)
+				if err != nil {
+					t.Fatalf("first refresh: %v", err)
+				}
+				return next.RefreshToken
+			},
+		},
+		{
+			name: "reused token revokes the session",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				if _, err := s.RefreshTokens(context.Background(), first, client); err != nil {
+					t.Fatalf("first refresh: %v", err)
+				}
+				return first
+			},
+			wantErr:     services.ErrRefreshTokenReused,
+			wantRevoked: models.SessionRevokedReuseDetected,
+		},
+		{
+			name: "concurrent exchange of the same token revokes the session",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				repo.raceOnRotate = true
+				return first
+			},
+			wantErr:     services.ErrRefreshTokenReused,
+			wantRevoked: models.SessionRevokedReuseDetected,
+		},
+		{
+			name: "unknown token",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				return "not-a-token"
+			},
+			wantErr: services.ErrInvalidRefreshToken,
+		},
+		{
+			name: "expired token",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				for _, token := range repo.tokens {
+					token.ExpiresAt = time.Now().Add(-time.Minute)
+				}
+				return first
+			},
+			wantErr: services.ErrInvalidRefreshToken,
+		},
+		{
+			name: "token of a signed out session",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				if err := s.LogoutByRefreshToken(context.Background(), first); err != nil {
+					t.Fatalf("logout: %v", err)
+				}
+				return first
+			},
+			wantErr:     services.ErrInvalidRefreshToken,
+			wantRevoked: models.SessionRevokedLogout,
+		},
+		{
+			name: "deactivated account",
+			present: func(t *testing.T, s *UserServiceImpl, repo *memorySessionRepository, user *models.User, first string) string {
+				user.IsActive = false
+				return first
+			},
+			wantErr:     services.ErrAccountDisabled,
+			wantRevoked: models.SessionRevokedDeactivated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, user := newSessionTestService()
+			ctx := context.Background()
+
+			issued, err := s.IssueTokens(ctx, user, client)
+			if err != nil {
+				t.Fatalf("IssueTokens() error = %v", err)
+			}
+
+			presented := tt.present(t, s, repo, user, issued.RefreshToken)
+			got, err := s.RefreshTokens(ctx, presented, client)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefreshTokens() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if got.RefreshToken == "" || got.RefreshToken == presented {
+					t.Errorf("RefreshTokens() returned refresh token %q, want a new one", got.RefreshToken)
+				}
+				if got.SessionID != issued.SessionID {
+					t.Errorf("RefreshTokens() session = %s, want %s", got.SessionID, issued.SessionID)
+				}
+			}
+
+			session := repo.sessions[issued.SessionID]
+			if session.RevokedReason != tt.wantRevoked {
+				t.Errorf("session revoked with %q, want %q", session.RevokedReason, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+// After reuse is detected the attacker's and the victim's newest tokens must
+// both be dead, whichever of them refreshed first
+func TestRefreshTokensReuseKillsTheWholeChain(t *testing.T) {
+	s, _, user := newSessionTestService()
+	ctx := context.Background()
+	client := dto.ClientInfo{}
+
+	issued, err := s.IssueTokens(ctx, user, client)
+	if err != nil {
+		t.Fatalf("IssueTokens() error = %v", err)
+	}
+	rotated, err := s.RefreshTokens(ctx, issued.RefreshToken, client)
+	if err != nil {
+		t.Fatalf("RefreshTokens() error = %v", err)
+	}
+
+	if _, err := s.RefreshTokens(ctx, issued.RefreshToken, client); !errors.Is(err, services.ErrRefreshTokenReused) {
+		t.Fatalf("replayed token: error = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := s.RefreshTokens(ctx, rotated.RefreshToken, client); !errors.Is(err, services.ErrInvalidRefreshToken) {
+		t.Errorf("newest token after reuse: error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
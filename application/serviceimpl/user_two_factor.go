@@ -0,0 +1,443 @@
+package serviceimpl
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
+)
+
+const (
+	// twoFactorChallengeTTL is how long the user has to enter a code (or
+	// enrol, for admins) after the password check
+	twoFactorChallengeTTL = 10 * time.Minute
+
+	// After twoFactorMaxAttempts wrong codes, 2FA is locked for twoFactorLockout
+	twoFactorMaxAttempts = 5
+	twoFactorLockout     = 15 * time.Minute
+
+	recoveryCodeCount = 10
+
+	purposeTwoFactorChallenge = "2fa_challenge"
+)
+
+// recoveryCodeAlphabet leaves out characters that are easy to misread
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// twoFactorChallengeClaims carries a half-finished sign-in to the code step
+type twoFactorChallengeClaims struct {
+	Purpose      string `json:"purpose"`
+	AuthProvider string `json:"provider"` // email, google, line; recorded on the session
+	Setup        bool   `json:"setup,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// GetTwoFactorStatus reports whether 2FA is on and how many recovery codes are left
+func (s *UserServiceImpl) GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorStatusResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+
+	resp := &dto.TwoFactorStatusResponse{
+		Enabled:  user.IsTwoFactorEnabled(),
+		Required: user.Role == "admin",
+	}
+	if resp.Enabled {
+		resp.RecoveryCodesLeft, err = s.recoveryCodeRepo.CountUnused(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// SetupTwoFactor creates a new TOTP secret for the user to scan. 2FA stays
+// off until EnableTwoFactor confirms the app produces valid codes.
+func (s *UserServiceImpl) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, services.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.sealTOTPSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetTwoFactor(ctx, user.ID, sealed, nil); err != nil {
+		return nil, err
+	}
+
+	otpauthURL := utils.TOTPURL(s.twoFactorConfig.Issuer, user.Email, secret)
+	png, err := qrcode.Encode(otpauthURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURL: otpauthURL,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// EnableTwoFactor turns 2FA on with a code from the app set up by
+// SetupTwoFactor and returns the first set of recovery codes
+func (s *UserServiceImpl) EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, services.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, services.ErrTwoFactorNotSetUp
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.userRepo.SetTwoFactor(ctx, user.ID, user.TOTPSecret, &now); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "Two-factor authentication enabled", "user_id", user.ID.String())
+	return codes, nil
+}
+
+// DisableTwoFactor turns 2FA off. Admins can't.
+func (s *UserServiceImpl) DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if user.Role == "admin" {
+		return services.ErrTwoFactorRequired
+	}
+	if !user.IsTwoFactorEnabled() {
+		return services.ErrTwoFactorNotSetUp
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code, true); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SetTwoFactor(ctx, user.ID, "", nil); err != nil {
+		return err
+	}
+	if err := s.recoveryCodeRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "Two-factor authentication disabled", "user_id", user.ID.String())
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used or not
+func (s *UserServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ไม่พบผู้ใช้งาน")
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, services.ErrTwoFactorNotSetUp
+	}
+
+	if err := s.checkSecondFactor(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// SetupTwoFactorForChallenge starts enrolment for a user who must have 2FA
+// but signed in without it
+func (s *UserServiceImpl) SetupTwoFactorForChallenge(ctx context.Context, challengeToken string) (*dto.TwoFactorSetupResponse, error) {
+	claims, err := s.parseTwoFactorChallenge(challengeToken)
+	if err != nil || !claims.Setup {
+		return nil, services.ErrInvalidTwoFactorChallenge
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, services.ErrInvalidTwoFactorChallenge
+	}
+	return s.SetupTwoFactor(ctx, userID)
+}
+
+// CompleteTwoFactorLogin finishes a sign-in that returned a challenge. For
+// a setup challenge the code also enables 2FA, and the new recovery codes
+// are returned.
+func (s *UserServiceImpl) CompleteTwoFactorLogin(ctx context.Context, challengeToken, code string, client dto.ClientInfo) (*dto.AuthTokens, *models.User, []string, error) {
+	claims, err := s.parseTwoFactorChallenge(challengeToken)
+	if err != nil {
+		return nil, nil, nil, services.ErrInvalidTwoFactorChallenge
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, nil, nil, services.ErrInvalidTwoFactorChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, services.ErrInvalidTwoFactorChallenge
+	}
+	if !user.IsActive {
+		return nil, nil, nil, services.ErrAccountDisabled
+	}
+
+	var recoveryCodes []string
+	if claims.Setup {
+		recoveryCodes, err = s.EnableTwoFactor(ctx, user.ID, code)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	} else {
+		if !user.IsTwoFactorEnabled() {
+			// Turned off since the challenge was issued
+			return nil, nil, nil, services.ErrInvalidTwoFactorChallenge
+		}
+		if err := s.checkSecondFactor(ctx, user, code, true); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	client.AuthProvider = claims.AuthProvider
+	tokens, err := s.IssueTokens(ctx, user, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tokens, user, recoveryCodes, nil
+}
+
+// signIn issues tokens after a successful password or provider check, or
+// returns a TwoFactorChallengeError when a second factor is still needed
+func (s *UserServiceImpl) signIn(ctx context.Context, user *models.User, client dto.ClientInfo) (*dto.AuthTokens, error) {
+	if !user.RequiresTwoFactor() {
+		return s.IssueTokens(ctx, user, client)
+	}
+
+	setup := !user.IsTwoFactorEnabled()
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, twoFactorChallengeClaims{
+		Purpose:      purposeTwoFactorChallenge,
+		AuthProvider: client.AuthProvider,
+		Setup:        setup,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(twoFactorChallengeTTL)),
+		},
+	}).SignedString(s.signingKey(purposeTwoFactorChallenge))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, &services.TwoFactorChallengeError{
+		Challenge: &dto.TwoFactorChallenge{
+			ChallengeToken: token,
+			SetupRequired:  setup,
+			ExpiresIn:      int(twoFactorChallengeTTL.Seconds()),
+		},
+	}
+}
+
+func (s *UserServiceImpl) parseTwoFactorChallenge(token string) (*twoFactorChallengeClaims, error) {
+	var claims twoFactorChallengeClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return s.signingKey(purposeTwoFactorChallenge), nil
+	})
+	if err != nil || claims.Purpose != purposeTwoFactorChallenge {
+		return nil, services.ErrInvalidTwoFactorChallenge
+	}
+	return &claims, nil
+}
+
+// checkSecondFactor accepts a current authenticator code, or a recovery code
+// when allowRecovery is set. Each TOTP code works once, and repeated wrong
+// codes lock 2FA for a while.
+func (s *UserServiceImpl) checkSecondFactor(ctx context.Context, user *models.User, code string, allowRecovery bool) error {
+	attemptsKey := cache.TwoFactorAttemptsKey(user.ID.String())
+	if s.redisClient != nil {
+		attempts, err := s.redisClient.Get(ctx, attemptsKey).Int()
+		if err == nil && attempts >= twoFactorMaxAttempts {
+			return services.ErrTwoFactorThrottled
+		}
+	}
+
+	secret, err := s.openTOTPSecret(user.TOTPSecret)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := utils.ValidateTOTP(secret, code, time.Now()); ok && s.claimTOTPStep(ctx, user.ID, step) {
+		s.clearTwoFactorAttempts(ctx, attemptsKey)
+		return nil
+	}
+
+	if allowRecovery {
+		used, err := s.recoveryCodeRepo.Consume(ctx, user.ID, hashRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+		if used {
+			s.clearTwoFactorAttempts(ctx, attemptsKey)
+			logger.WarnContext(ctx, "Recovery code used", "user_id", user.ID.String())
+			return nil
+		}
+	}
+
+	if s.redisClient != nil {
+		if attempts, err := s.redisClient.Incr(ctx, attemptsKey).Result(); err == nil && attempts == 1 {
+			s.redisClient.Expire(ctx, attemptsKey, twoFactorLockout)
+		}
+	}
+	return services.ErrInvalidTwoFactorCode
+}
+
+// claimTOTPStep records a time step as used; false means its code was
+// already accepted once
+func (s *UserServiceImpl) claimTOTPStep(ctx context.Context, userID uuid.UUID, step int64) bool {
+	if s.redisClient == nil {
+		return true
+	}
+	// A step's code is accepted for up to (skew+1) periods
+	ok, err := s.redisClient.SetNX(ctx, cache.TwoFactorUsedCodeKey(userID.String(), step), "1", 3*utils.TOTPPeriod).Result()
+	return err != nil || ok
+}
+
+func (s *UserServiceImpl) clearTwoFactorAttempts(ctx context.Context, key string) {
+	if s.redisClient != nil {
+		s.redisClient.Del(ctx, key)
+	}
+}
+
+// replaceRecoveryCodes generates a fresh set of recovery codes and returns them in plain text
+func (s *UserServiceImpl) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]*models.RecoveryCode, recoveryCodeCount)
+	now := time.Now()
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = &models.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  hashRecoveryCode(code),
+			CreatedAt: now,
+		}
+	}
+
+	if err := s.recoveryCodeRepo.Replace(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode returns a code like "k7m2p-xq9ra"
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := make([]byte, 0, 11)
+	for i, v := range b {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		code = append(code, recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes so codes can be typed loosely
+func hashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// sealTOTPSecret encrypts a TOTP secret for storage with AES-GCM
+func (s *UserServiceImpl) sealTOTPSecret(secret string) (string, error) {
+	gcm, err := s.totpCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *UserServiceImpl) openTOTPSecret(sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := s.totpCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("invalid TOTP secret")
+	}
+	secret, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func (s *UserServiceImpl) totpCipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(s.twoFactorConfig.EncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
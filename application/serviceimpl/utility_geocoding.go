@@ -0,0 +1,369 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/infrastructure/external/google"
+	"gofiber-template/infrastructure/thaigeo"
+	"gofiber-template/pkg/logger"
+)
+
+// geocodingCostPerRequest is the Geocoding API price ($5/1000)
+const geocodingCostPerRequest = 0.005
+
+// geocodeLanguages are fetched for every lookup so callers get both address forms
+var geocodeLanguages = []string{"th", "en"}
+
+// geocodeLookup is one language's parsed geocoding result, cached per language
+type geocodeLookup struct {
+	Found            bool                  `json:"found"`
+	PlaceID          string                `json:"placeId"`
+	FormattedAddress string                `json:"formattedAddress"`
+	Lat              float64               `json:"lat"`
+	Lng              float64               `json:"lng"`
+	Components       dto.AddressComponents `json:"components"`
+	Admin            dto.AdminArea         `json:"admin"`
+}
+
+// geocodeCall fetches one language from the Geocoding API
+type geocodeCall func(ctx context.Context, lang string) (*google.GeocodeResponse, error)
+
+func (s *UtilityServiceImpl) Geocode(ctx context.Context, req *dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
+	address := strings.TrimSpace(req.Address)
+
+	lookups, err := s.geocodeAllLanguages(ctx, "geocode",
+		map[string]interface{}{"address": address},
+		func(lang string) string { return cache.GeocodeKey(address, lang) },
+		func(ctx context.Context, lang string) (*google.GeocodeResponse, error) {
+			return s.geocodingClient.Geocode(ctx, &google.GeocodeRequest{
+				Address:  address,
+				Language: lang,
+				Region:   "th",
+			})
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	th, en := lookups["th"], lookups["en"]
+	response := &dto.GeocodeResponse{
+		Address:       address,
+		FormattedAddr: th.FormattedAddress,
+		Lat:           th.Lat,
+		Lng:           th.Lng,
+		PlaceID:       th.PlaceID,
+		Admin:         dto.LocalizedAdminArea{TH: th.Admin, EN: en.Admin},
+	}
+	if s.boundaries != nil {
+		if loc, ok := s.boundaries.Lookup(th.Lat, th.Lng); ok {
+			response.Admin = toLocalizedAdminArea(loc)
+		}
+	}
+	return response, nil
+}
+
+// ReverseGeocode answers from the offline admin boundaries when they cover the
+// point, which is free and enough for province/amphoe/tambon. Google is asked
+// when the caller wants street-level detail or the point is outside Thailand.
+func (s *UtilityServiceImpl) ReverseGeocode(ctx context.Context, req *dto.ReverseGeocodeRequest) (*dto.ReverseGeocodeResponse, error) {
+	var loc thaigeo.Location
+	located := false
+	if s.boundaries != nil {
+		loc, located = s.boundaries.Lookup(req.Lat, req.Lng)
+	}
+
+	if located && !req.Detailed {
+		if s.apiLogger != nil {
+			s.apiLogger.LogDatabaseHit(ctx, "google_geocoding", "reverse_geocode", nil)
+		}
+		admin := toLocalizedAdminArea(loc)
+		return &dto.ReverseGeocodeResponse{
+			Lat:           req.Lat,
+			Lng:           req.Lng,
+			FormattedAddr: formatThaiAdminAddress(admin.TH, admin.ProvinceCode == bangkokProvinceCode),
+			Components: dto.AddressComponents{
+				Subdistrict: admin.TH.Tambon,
+				District:    admin.TH.Amphoe,
+				Province:    admin.TH.Province,
+				Country:     "ประเทศไทย",
+			},
+			Admin:  admin,
+			Source: dto.GeocodeSourceBoundaries,
+		}, nil
+	}
+
+	lookups, err := s.geocodeAllLanguages(ctx, "reverse_geocode",
+		map[string]interface{}{"lat": req.Lat, "lng": req.Lng},
+		func(lang string) string { return cache.ReverseGeocodeKey(req.Lat, req.Lng, lang) },
+		func(ctx context.Context, lang string) (*google.GeocodeResponse, error) {
+			return s.geocodingClient.ReverseGeocode(ctx, &google.ReverseGeocodeRequest{
+				Lat:      req.Lat,
+				Lng:      req.Lng,
+				Language: lang,
+			})
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	th, en := lookups["th"], lookups["en"]
+	response := &dto.ReverseGeocodeResponse{
+		Lat:           req.Lat,
+		Lng:           req.Lng,
+		FormattedAddr: th.FormattedAddress,
+		PlaceID:       th.PlaceID,
+		Components:    th.Components,
+		Admin:         dto.LocalizedAdminArea{TH: th.Admin, EN: en.Admin},
+		Source:        dto.GeocodeSourceGoogle,
+	}
+
+	// Boundary names are authoritative; Google's vary in spelling between calls
+	if located {
+		response.Admin = toLocalizedAdminArea(loc)
+		response.Components.Subdistrict = response.Admin.TH.Tambon
+		response.Components.District = response.Admin.TH.Amphoe
+		response.Components.Province = response.Admin.TH.Province
+	}
+	return response, nil
+}
+
+// geocodeAllLanguages runs a lookup in every geocodeLanguages language
+// concurrently. Thai is required; a failed English lookup only leaves the
+// English admin area empty.
+func (s *UtilityServiceImpl) geocodeAllLanguages(ctx context.Context, endpoint string, params map[string]interface{}, cacheKey func(lang string) string, call geocodeCall) (map[string]*geocodeLookup, error) {
+	lookups := make(map[string]*geocodeLookup, len(geocodeLanguages))
+	errs := make(map[string]error, len(geocodeLanguages))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, lang := range geocodeLanguages {
+		wg.Add(1)
+		go func(lang string) {
+			defer wg.Done()
+			lookup, err := s.geocodeLanguage(ctx, endpoint, params, cacheKey(lang), lang, call)
+			mu.Lock()
+			lookups[lang], errs[lang] = lookup, err
+			mu.Unlock()
+		}(lang)
+	}
+	wg.Wait()
+
+	if err := errs["th"]; err != nil {
+		return nil, err
+	}
+	if !lookups["th"].Found {
+		return nil, services.ErrLocationNotFound
+	}
+	if err := errs["en"]; err != nil {
+		logger.WarnContext(ctx, "English geocoding failed",
+			"endpoint", endpoint,
+			"error", err.Error(),
+		)
+		lookups["en"] = &geocodeLookup{}
+	}
+	return lookups, nil
+}
+
+// geocodeLanguage returns one language's lookup from cache or the Geocoding API.
+// Misses are cached too so repeated unknown addresses don't cost a call each.
+func (s *UtilityServiceImpl) geocodeLanguage(ctx context.Context, endpoint string, params map[string]interface{}, cacheKey, lang string, call geocodeCall) (*geocodeLookup, error) {
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckBudget(ctx, "google_geocoding"); err != nil {
+			return nil, err
+		}
+	}
+
+	if cached, err := s.redisClient.Get(ctx, cacheKey).Result(); err == nil {
+		var lookup geocodeLookup
+		if json.Unmarshal([]byte(cached), &lookup) == nil {
+			if s.apiLogger != nil {
+				s.apiLogger.LogCacheHit(ctx, "google_geocoding", endpoint, cacheKey, nil)
+			}
+			return &lookup, nil
+		}
+	}
+
+	if s.apiLogger != nil {
+		if err := s.apiLogger.CheckCallBudget(ctx, "google_geocoding"); err != nil {
+			return nil, err
+		}
+	}
+	startTime := time.Now()
+	response, err := call(ctx, lang)
+	durationMs := int(time.Since(startTime).Milliseconds())
+
+	if s.apiLogger != nil {
+		success := err == nil
+		errMsg := ""
+		if err != nil {
+			errMsg = err.Error()
+		}
+		logParams := map[string]interface{}{"language": lang}
+		for k, v := range params {
+			logParams[k] = v
+		}
+		s.apiLogger.LogAPICall(ctx, "google_geocoding", endpoint, logParams, geocodingCostPerRequest, durationMs, nil, success, errMsg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := toGeocodeLookup(response.Results)
+
+	logger.InfoContext(ctx, "Geocoding completed",
+		"endpoint", endpoint,
+		"language", lang,
+		"found", lookup.Found,
+		"province", lookup.Admin.Province,
+		"response_time_ms", durationMs,
+	)
+
+	if jsonData, err := json.Marshal(lookup); err == nil {
+		s.redisClient.Set(ctx, cacheKey, jsonData, cache.TTLGeocode)
+	}
+
+	return lookup, nil
+}
+
+// toGeocodeLookup takes the address from the most specific result and fills
+// the admin area from whichever results carry each level, since reverse
+// geocoding often returns a street address without every level on it
+func toGeocodeLookup(results []google.GeocodeResult) *geocodeLookup {
+	if len(results) == 0 {
+		return &geocodeLookup{}
+	}
+
+	first := &results[0]
+	admin := thaiAdminArea(results)
+	street := first.Component("route")
+	if number := first.Component("street_number"); number != "" && street != "" {
+		street = number + " " + street
+	}
+
+	return &geocodeLookup{
+		Found:            true,
+		PlaceID:          first.PlaceID,
+		FormattedAddress: first.FormattedAddress,
+		Lat:              first.Geometry.Location.Lat,
+		Lng:              first.Geometry.Location.Lng,
+		Admin:            admin,
+		Components: dto.AddressComponents{
+			Street:      street,
+			Subdistrict: admin.Tambon,
+			District:    admin.Amphoe,
+			Province:    admin.Province,
+			Country:     first.Component("country"),
+			PostalCode:  first.Component("postal_code"),
+		},
+	}
+}
+
+// thaiAdminArea maps Google address components to province/amphoe/tambon.
+// Bangkok reports khet and khwaeng as sublocality levels 1 and 2, other
+// provinces use administrative_area_level_2 for amphoe and locality or
+// sublocality for tambon.
+func thaiAdminArea(results []google.GeocodeResult) dto.AdminArea {
+	var area dto.AdminArea
+	for i := range results {
+		r := &results[i]
+		if area.Province == "" {
+			area.Province = trimAdminPrefix(r.Component("administrative_area_level_1"))
+		}
+		if area.Amphoe == "" {
+			area.Amphoe = trimAdminPrefix(firstNonEmpty(
+				r.Component("administrative_area_level_2"),
+				r.Component("sublocality_level_1"),
+			))
+		}
+		if area.Tambon == "" {
+			area.Tambon = trimAdminPrefix(firstNonEmpty(
+				r.Component("sublocality_level_2"),
+				r.Component("administrative_area_level_3"),
+				r.Component("locality"),
+			))
+		}
+	}
+
+	// locality is the province name for Bangkok and some municipalities
+	if area.Tambon == area.Province || area.Tambon == area.Amphoe {
+		area.Tambon = ""
+	}
+	return area
+}
+
+// adminPrefixes are the administrative-level words Google sometimes keeps in names
+var adminPrefixes = []string{
+	"จังหวัด", "จ.", "อำเภอ", "อ.", "ตำบล", "ต.", "เขต", "แขวง",
+	"Chang Wat ", "Changwat ", "Amphoe ", "Tambon ", "Khet ", "Khwaeng ",
+}
+
+func trimAdminPrefix(name string) string {
+	name = strings.TrimSpace(name)
+	for _, prefix := range adminPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(name, prefix))
+		}
+	}
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// bangkokProvinceCode uses khet/khwaeng instead of amphoe/tambon in addresses
+const bangkokProvinceCode = "10"
+
+// toLocalizedAdminArea converts a boundary lookup into the API shape
+func toLocalizedAdminArea(loc thaigeo.Location) dto.LocalizedAdminArea {
+	var admin dto.LocalizedAdminArea
+	if loc.Province != nil {
+		admin.ProvinceCode = loc.Province.Code
+		admin.TH.Province, admin.EN.Province = loc.Province.NameTH, loc.Province.NameEN
+	}
+	if loc.Amphoe != nil {
+		admin.AmphoeCode = loc.Amphoe.Code
+		admin.TH.Amphoe, admin.EN.Amphoe = loc.Amphoe.NameTH, loc.Amphoe.NameEN
+	}
+	if loc.Tambon != nil {
+		admin.TambonCode = loc.Tambon.Code
+		admin.TH.Tambon, admin.EN.Tambon = loc.Tambon.NameTH, loc.Tambon.NameEN
+	}
+	return admin
+}
+
+// formatThaiAdminAddress writes an area the way Thai addresses do,
+// e.g. "ตำบลสุเทพ อำเภอเมืองเชียงใหม่ จังหวัดเชียงใหม่"
+func formatThaiAdminAddress(area dto.AdminArea, bangkok bool) string {
+	tambonPrefix, amphoePrefix, provincePrefix := "ตำบล", "อำเภอ", "จังหวัด"
+	if bangkok {
+		tambonPrefix, amphoePrefix, provincePrefix = "แขวง", "เขต", ""
+	}
+
+	var parts []string
+	if area.Tambon != "" {
+		parts = append(parts, tambonPrefix+area.Tambon)
+	}
+	if area.Amphoe != "" {
+		parts = append(parts, amphoePrefix+area.Amphoe)
+	}
+	if area.Province != "" {
+		parts = append(parts, provincePrefix+area.Province)
+	}
+	return strings.Join(parts, " ")
+}
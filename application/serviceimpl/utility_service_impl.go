@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/skip2/go-qrcode"
@@ -14,23 +13,33 @@ import (
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/cache"
 	"gofiber-template/infrastructure/external/google"
+	"gofiber-template/infrastructure/thaigeo"
 	"gofiber-template/pkg/config"
 )
 
 type UtilityServiceImpl struct {
 	translateClient *google.TranslateClient
+	geocodingClient *google.GeocodingClient
+	boundaries      *thaigeo.Boundaries
 	redisClient     *redis.Client
+	apiLogger       *APILoggerService
 	config          *config.Config
 }
 
 func NewUtilityService(
 	translateClient *google.TranslateClient,
+	geocodingClient *google.GeocodingClient,
+	boundaries *thaigeo.Boundaries,
 	redisClient *redis.Client,
+	apiLogger *APILoggerService,
 	cfg *config.Config,
 ) services.UtilityService {
 	return &UtilityServiceImpl{
 		translateClient: translateClient,
+		geocodingClient: geocodingClient,
+		boundaries:      boundaries,
 		redisClient:     redisClient,
+		apiLogger:       apiLogger,
 		config:          cfg,
 	}
 }
@@ -167,44 +176,6 @@ func (s *UtilityServiceImpl) CalculateDistance(ctx context.Context, req *dto.Cal
 	}, nil
 }
 
-func (s *UtilityServiceImpl) Geocode(ctx context.Context, req *dto.GeocodeRequest) (*dto.GeocodeResponse, error) {
-	// This would require Google Geocoding API
-	// For now, return an error as we need to implement the client
-	return nil, fmt.Errorf("geocoding not implemented")
-}
-
-func (s *UtilityServiceImpl) ReverseGeocode(ctx context.Context, req *dto.LocationRequest) (*dto.ReverseGeocodeResponse, error) {
-	// This would require Google Geocoding API
-	// For now, return an error as we need to implement the client
-	return nil, fmt.Errorf("reverse geocoding not implemented")
-}
-
-func (s *UtilityServiceImpl) HealthCheck(ctx context.Context) (*dto.HealthCheckResponse, error) {
-	services := make(map[string]string)
-
-	// Check Redis
-	if s.redisClient != nil {
-		if err := s.redisClient.Ping(ctx).Err(); err != nil {
-			services["redis"] = "unhealthy: " + err.Error()
-		} else {
-			services["redis"] = "healthy"
-		}
-	} else {
-		services["redis"] = "not configured"
-	}
-
-	// Check other services
-	services["google_api"] = "configured"
-	services["openai_api"] = "configured"
-
-	return &dto.HealthCheckResponse{
-		Status:    "healthy",
-		Version:   "1.0.0",
-		Timestamp: time.Now().Format(time.RFC3339),
-		Services:  services,
-	}, nil
-}
-
 func formatDistanceUtil(meters float64) string {
 	if meters < 1000 {
 		return fmt.Sprintf("%.0f m", meters)
@@ -15,6 +15,14 @@ import (
 )
 
 func main() {
+	// Schema migrations: api migrate up|down|status|create
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+
 	// Initialize logger
 	if err := logger.Init(logger.DefaultConfig()); err != nil {
 		log.Fatal("Failed to initialize logger:", err)
@@ -39,6 +47,8 @@ func main() {
 	})
 
 	// Setup middleware
+	app.Use(middleware.MetricsMiddleware())
+	app.Use(middleware.TracingMiddleware())
 	app.Use(middleware.LoggerMiddleware())
 	app.Use(middleware.CorsMiddleware())
 
@@ -46,18 +56,34 @@ func main() {
 	services := container.GetHandlerServices()
 	h := handlers.NewHandlers(services, container.GetConfig())
 
+	// Reject access tokens of revoked sessions
+	middleware.UseSessionDenylist(container.RedisClient)
+
+	// Role permissions for RequirePermission
+	middleware.UsePermissions(container.PermissionService)
+
+	// Personal API keys for partner backends (X-API-Key)
+	middleware.UseAPIKeys(container.APIKeyService)
+
+	// Daily budgets shared across replicas through Redis
+	rateLimiter := middleware.NewRateLimitMiddleware(container.RedisClient, container.GetConfig().RateLimit)
+
 	// Setup routes
-	routes.SetupRoutes(app, h)
+	routes.SetupRoutes(app, h, rateLimiter)
 
 	// Setup admin routes for API statistics
 	api := app.Group("/api/v1")
-	routes.SetupAdminRoutes(api, container.GetAPILoggerService())
+	routes.SetupAdminRoutes(api, h, container.GetAPILoggerService())
+
+	// Prometheus metrics
+	routes.SetupMetricsRoutes(app, container.GetConfig().Metrics.Token)
 
 	// Start server
 	port := container.GetConfig().App.Port
 	log.Printf("🚀 Server starting on port %s", port)
 	log.Printf("🌍 Environment: %s", container.GetConfig().App.Env)
 	log.Printf("📚 Health check: http://localhost:%s/health", port)
+	log.Printf("📈 Metrics: http://localhost:%s/metrics", port)
 	log.Printf("📖 API docs: http://localhost:%s/api/v1", port)
 	log.Printf("🔌 WebSocket: ws://localhost:%s/ws", port)
 
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"gofiber-template/infrastructure/postgres"
+	"gofiber-template/infrastructure/postgres/migrations"
+	"gofiber-template/pkg/config"
+)
+
+// migrationsDir is where `migrate create` writes new scripts, relative to the
+// repository root
+const migrationsDir = "infrastructure/postgres/migrations"
+
+const migrateUsage = `Usage: api migrate <command>
+
+Commands:
+  up             apply all pending migrations
+  down [n]       revert the last n applied migrations (default 1)
+  status         list migrations and when they were applied
+  create <name>  add empty up/down scripts for the next version`
+
+// runMigrate implements `api migrate`. It only needs the database, so it
+// doesn't start the rest of the container.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n\n%s", migrateUsage)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: api migrate create <name>")
+		}
+		up, down, err := postgres.CreateMigration(migrationsDir, args[1])
+		if err != nil {
+			return err
+		}
+		log.Printf("✓ Created %s", up)
+		log.Printf("✓ Created %s", down)
+		return nil
+	}
+	if args[0] != "up" && args[0] != "down" && args[0] != "status" {
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], migrateUsage)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	db, err := postgres.NewDatabase(postgres.DatabaseConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	migrator, err := postgres.NewMigrator(db, migrations.Files)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("✓ Applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("✓ Database is up to date")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			log.Printf("✓ Reverted %04d_%s", m.Version, m.Name)
+		}
+		return err
+
+	default: // status
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			switch {
+			case s.Missing:
+				state += " (not in this binary)"
+			case s.Modified:
+				state += " (modified since applied)"
+			}
+			fmt.Printf("%04d_%-32s %s\n", s.Version, s.Name, state)
+		}
+	}
+	return nil
+}
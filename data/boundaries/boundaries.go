@@ -0,0 +1,11 @@
+// Package boundaries embeds the simplified Thai administrative boundary
+// GeoJSON (see README.md) so the binary doesn't depend on files next to it.
+package boundaries
+
+import "embed"
+
+// Files are the boundary files, embedded at build time. The pattern also
+// takes this file and the README; only the .geojson files are read.
+//
+//go:embed *
+var Files embed.FS
@@ -79,9 +79,18 @@ type AISearchResponse struct {
 
 // ==================== Streaming Response DTOs ====================
 
+// Stream chunk types sent as Server-Sent Events
+const (
+	StreamChunkContent = "content"
+	StreamChunkSource  = "source"
+	StreamChunkDone    = "done"
+	StreamChunkError   = "error"
+)
+
 type AIStreamChunk struct {
-	Type    string `json:"type"` // content, source, done, error
-	Content string `json:"content,omitempty"`
-	Source  *MessageSource `json:"source,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Type      string         `json:"type"` // content, source, done, error
+	Content   string         `json:"content,omitempty"`
+	Source    *MessageSource `json:"source,omitempty"`
+	MessageID *uuid.UUID     `json:"messageId,omitempty"` // set on done for chat streams
+	Error     string         `json:"error,omitempty"`
 }
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=search places"`
+	ExpiresInDays int      `json:"expiresInDays" validate:"omitempty,min=1"` // default API_KEY_DEFAULT_TTL_DAYS
+	DailyLimit    int      `json:"dailyLimit" validate:"omitempty,min=1"`    // default and maximum API_KEY_DAILY_LIMIT
+}
+
+// APIKeyResponse describes a key without the key itself
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	DailyLimit int        `json:"dailyLimit"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateAPIKeyResponse is the only time the key is shown
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// APIKeyToAPIKeyResponse converts APIKey model to APIKeyResponse DTO
+func APIKeyToAPIKeyResponse(key *models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		DailyLimit: key.DailyLimit,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
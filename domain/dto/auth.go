@@ -1,14 +1,20 @@
 package dto
 
+import "github.com/google/uuid"
+
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email,max=255"`
 	Password string `json:"password" validate:"required,min=1"`
 }
 
 type LoginResponse struct {
-	Token     string       `json:"token"`
-	User      UserResponse `json:"user"`
-	IsNewUser bool         `json:"isNewUser,omitempty"` // For OAuth: indicates if this is a new user
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refreshToken"`
+	ExpiresIn    int          `json:"expiresIn"` // access token lifetime in seconds
+	User         UserResponse `json:"user"`
+	IsNewUser    bool         `json:"isNewUser,omitempty"` // For OAuth: indicates if this is a new user
+	// RecoveryCodes is set once, when 2FA was enrolled as part of signing in
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
 }
 
 type RegisterRequest struct {
@@ -28,9 +34,27 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
-type RefreshTokenResponse struct {
-	Token        string `json:"token"`
-	RefreshToken string `json:"refreshToken"`
+// AuthTokens is the token pair issued at sign-in and on every refresh
+type AuthTokens struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresIn    int       `json:"expiresIn"` // access token lifetime in seconds
+	SessionID    uuid.UUID `json:"-"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"` // optional when the access token is still valid
+}
+
+// ClientInfo describes the device a session was signed in from
+type ClientInfo struct {
+	UserAgent    string
+	IPAddress    string
+	AuthProvider string // set by the service; email when empty
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
 }
 
 type ForgotPasswordRequest struct {
@@ -52,6 +76,7 @@ type LogoutResponse struct {
 // OAuthStateData stores state data for OAuth flow
 type OAuthStateData struct {
 	FrontendURL string `json:"frontendUrl"`
+	LinkToken   string `json:"linkToken,omitempty"` // set when a signed-in user is linking the provider
 }
 
 // GoogleUserInfo represents user info from Google OAuth
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type DataExportResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"` // pending, ready, failed
+	Size        int64      `json:"size"`   // bytes, once ready
+	DownloadURL string     `json:"downloadUrl,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+// DataExportToDataExportResponse converts DataExport model to DataExportResponse DTO
+func DataExportToDataExportResponse(export *models.DataExport) DataExportResponse {
+	resp := DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		Size:        export.Size,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.IsDownloadable() {
+		resp.DownloadURL = "/api/v1/users/data-exports/" + export.ID.String() + "/download"
+	}
+	return resp
+}
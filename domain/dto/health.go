@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// Health verdicts, from best to worst
+const (
+	HealthOK        = "ok"
+	HealthDegraded  = "degraded"  // serving, but a dependency is slow or optional parts are down
+	HealthUnhealthy = "unhealthy" // take the instance out of rotation
+)
+
+// HealthCheckResult is the outcome of one dependency check
+type HealthCheckResult struct {
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"` // unhealthy makes the instance unhealthy
+	LatencyMs int64     `json:"latencyMs"`
+	Detail    string    `json:"detail,omitempty"`
+	Error     string    `json:"error,omitempty"` // generic, the readiness endpoint is public
+	Cause     error     `json:"-"`               // underlying error, only logged
+	CheckedAt time.Time `json:"checkedAt"`
+	Cached    bool      `json:"cached"` // result of an earlier probe
+}
+
+// HealthReport is the answer of /livez and /readyz
+type HealthReport struct {
+	Status    string                       `json:"status"`
+	Version   string                       `json:"version"`
+	Timestamp time.Time                    `json:"timestamp"`
+	Uptime    string                       `json:"uptime"`
+	Checks    map[string]HealthCheckResult `json:"checks,omitempty"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+// IdentityResponse is one linked Google or LINE account
+type IdentityResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Provider string    `json:"provider"` // google, line
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+// IdentityListResponse lists every way the user can sign in
+type IdentityListResponse struct {
+	Identities  []IdentityResponse `json:"identities"`
+	HasPassword bool               `json:"hasPassword"`
+}
+
+type LinkIdentityResponse struct {
+	AuthURL string `json:"authUrl"` // open in the browser to continue with the provider
+}
+
+// LinkIdentityResult is the outcome of a link callback. MergeToken is set
+// instead of linking when the provider account belongs to another user.
+type LinkIdentityResult struct {
+	Provider   string
+	MergeToken string
+}
+
+type MergeAccountRequest struct {
+	MergeToken string `json:"mergeToken" validate:"required"`
+}
+
+// IdentityToIdentityResponse converts UserIdentity model to IdentityResponse DTO
+func IdentityToIdentityResponse(identity *models.UserIdentity) IdentityResponse {
+	return IdentityResponse{
+		ID:       identity.ID,
+		Provider: identity.Provider,
+		Email:    identity.Email,
+		LinkedAt: identity.LinkedAt,
+	}
+}
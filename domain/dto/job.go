@@ -7,12 +7,14 @@ import (
 
 type CreateJobRequest struct {
 	Name     string `json:"name" validate:"required,min=1,max=100"`
+	Handler  string `json:"handler" validate:"required,max=50"` // e.g. cache_warm, cleanup_api_logs
 	CronExpr string `json:"cronExpr" validate:"required,min=5,max=50"`
 	Payload  string `json:"payload" validate:"omitempty,json"`
 }
 
 type UpdateJobRequest struct {
 	Name     string `json:"name" validate:"omitempty,min=1,max=100"`
+	Handler  string `json:"handler" validate:"omitempty,max=50"`
 	CronExpr string `json:"cronExpr" validate:"omitempty,min=5,max=50"`
 	Payload  string `json:"payload" validate:"omitempty,json"`
 	IsActive bool   `json:"isActive"`
@@ -21,6 +23,7 @@ type UpdateJobRequest struct {
 type JobResponse struct {
 	ID        uuid.UUID  `json:"id"`
 	Name      string     `json:"name"`
+	Handler   string     `json:"handler"`
 	CronExpr  string     `json:"cronExpr"`
 	Payload   string     `json:"payload"`
 	Status    string     `json:"status"`
@@ -36,6 +39,24 @@ type JobListResponse struct {
 	Meta PaginationMeta `json:"meta"`
 }
 
+type JobRunResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	JobID      uuid.UUID  `json:"jobId"`
+	Handler    string     `json:"handler"`
+	Trigger    string     `json:"trigger"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+	DurationMs int64      `json:"durationMs"`
+	Output     string     `json:"output,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type JobRunListResponse struct {
+	Runs []JobRunResponse `json:"runs"`
+	Meta PaginationMeta   `json:"meta"`
+}
+
 type JobFilterRequest struct {
 	Status   string `query:"status" validate:"omitempty,oneof=active inactive running completed failed"`
 	IsActive *bool  `query:"isActive"`
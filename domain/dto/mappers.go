@@ -71,6 +71,7 @@ func JobToJobResponse(job *models.Job) *JobResponse {
 	return &JobResponse{
 		ID:        job.ID,
 		Name:      job.Name,
+		Handler:   job.Handler,
 		CronExpr:  job.CronExpr,
 		Payload:   job.Payload,
 		Status:    job.Status,
@@ -82,6 +83,24 @@ func JobToJobResponse(job *models.Job) *JobResponse {
 	}
 }
 
+func JobRunToJobRunResponse(run *models.JobRun) *JobRunResponse {
+	if run == nil {
+		return nil
+	}
+	return &JobRunResponse{
+		ID:         run.ID,
+		JobID:      run.JobID,
+		Handler:    run.Handler,
+		Trigger:    run.Trigger,
+		Status:     run.Status,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMs: run.DurationMs,
+		Output:     run.Output,
+		Error:      run.Error,
+	}
+}
+
 func CreateJobRequestToJob(req *CreateJobRequest) *models.Job {
 	return &models.Job{
 		Name:     req.Name,
@@ -211,7 +230,7 @@ func FavoriteToFavoriteResponse(fav *models.Favorite) *FavoriteResponse {
 	if fav.Metadata != nil {
 		_ = json.Unmarshal(fav.Metadata, &metadata)
 	}
-	return &FavoriteResponse{
+	response := &FavoriteResponse{
 		ID:           fav.ID,
 		Type:         fav.Type,
 		ExternalID:   fav.ExternalID,
@@ -224,6 +243,12 @@ func FavoriteToFavoriteResponse(fav *models.Favorite) *FavoriteResponse {
 		Metadata:     metadata,
 		CreatedAt:    fav.CreatedAt,
 	}
+	// Prefer live rating from the places catalogue over the value saved with the favorite
+	if fav.Type == models.FavoriteTypePlace && fav.Place != nil {
+		response.Rating = fav.Place.Rating
+		response.ReviewCount = fav.Place.ReviewCount
+	}
+	return response
 }
 
 func AddFavoriteRequestToFavorite(req *AddFavoriteRequest) *models.Favorite {
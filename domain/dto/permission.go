@@ -0,0 +1,17 @@
+package dto
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolePermissionsResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// PermissionListResponse lists every permission and what each role has
+type PermissionListResponse struct {
+	Permissions []PermissionResponse      `json:"permissions"`
+	Roles       []RolePermissionsResponse `json:"roles"`
+}
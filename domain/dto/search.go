@@ -14,6 +14,9 @@ type SearchRequest struct {
 	Page     int    `json:"page" query:"page" validate:"omitempty,min=1"`
 	PageSize int    `json:"pageSize" query:"pageSize" validate:"omitempty,min=1,max=50"`
 	Language string `json:"language" query:"lang" validate:"omitempty,len=2"`
+	// Optional user location, used to break ranking ties by distance
+	Lat float64 `json:"lat" query:"lat" validate:"omitempty,latitude"`
+	Lng float64 `json:"lng" query:"lng" validate:"omitempty,longitude"`
 }
 
 type PlaceSearchRequest struct {
@@ -25,6 +28,29 @@ type PlaceSearchRequest struct {
 	Page      int     `json:"page" query:"page" validate:"omitempty,min=1"`
 	PageSize  int     `json:"pageSize" query:"pageSize" validate:"omitempty,min=1,max=20"`
 	Lang      string  `json:"lang" query:"lang" validate:"omitempty,len=2"`
+	Cursor    string  `json:"cursor" query:"cursor" validate:"omitempty,max=2048"` // nextCursor of the previous page
+}
+
+// PlacesInBoundsRequest queries stored places inside a map viewport
+type PlacesInBoundsRequest struct {
+	North     float64 `json:"north" query:"north" validate:"required,latitude,gtfield=South"`
+	South     float64 `json:"south" query:"south" validate:"required,latitude"`
+	East      float64 `json:"east" query:"east" validate:"required,longitude,gtfield=West"`
+	West      float64 `json:"west" query:"west" validate:"required,longitude"`
+	PlaceType string  `json:"type" query:"type" validate:"omitempty"`
+	MinRating float64 `json:"minRating" query:"minRating" validate:"omitempty,min=0,max=5"`
+	Limit     int     `json:"limit" query:"limit" validate:"omitempty,min=1,max=200"`
+	Lang      string  `json:"lang" query:"lang" validate:"omitempty,len=2"`
+}
+
+// NearestPlacesRequest queries the k stored places nearest to a point
+type NearestPlacesRequest struct {
+	Lat       float64 `json:"lat" query:"lat" validate:"required,latitude"`
+	Lng       float64 `json:"lng" query:"lng" validate:"required,longitude"`
+	K         int     `json:"k" query:"k" validate:"omitempty,min=1,max=50"`
+	PlaceType string  `json:"type" query:"type" validate:"omitempty"`
+	MinRating float64 `json:"minRating" query:"minRating" validate:"omitempty,min=0,max=5"`
+	Lang      string  `json:"lang" query:"lang" validate:"omitempty,len=2"`
 }
 
 type VideoSearchRequest struct {
@@ -44,12 +70,29 @@ type ImageSearchRequest struct {
 // ==================== Search Response DTOs ====================
 
 type SearchResponse struct {
-	Query      string         `json:"query"`
-	Type       string         `json:"type"`
-	Results    []SearchResult `json:"results"`
-	TotalCount int64          `json:"totalCount"`
-	Page       int            `json:"page"`
-	PageSize   int            `json:"pageSize"`
+	Query      string                        `json:"query"`
+	Type       string                        `json:"type"`
+	Results    []SearchResult                `json:"results"`
+	TotalCount int64                         `json:"totalCount"`
+	Page       int                           `json:"page"`
+	PageSize   int                           `json:"pageSize"`
+	Sources    map[string]SearchSourceStatus `json:"sources,omitempty"` // per-source status for type=all
+}
+
+// Search source status values
+const (
+	SearchSourceOK      = "ok"
+	SearchSourceTimeout = "timeout"
+	SearchSourceError   = "error"
+	SearchSourceCached  = "cached"
+)
+
+// SearchSourceStatus reports how one source of a mixed search completed
+type SearchSourceStatus struct {
+	Status     string `json:"status"` // ok, timeout, error, cached
+	Count      int    `json:"count"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
 }
 
 type SearchResult struct {
@@ -62,11 +105,15 @@ type SearchResult struct {
 	PublishedAt  string   `json:"publishedAt,omitempty"`
 	Rating       float64  `json:"rating,omitempty"`
 	ReviewCount  int      `json:"reviewCount,omitempty"`
+	// Merged duplicates (e.g. web pages about the same place)
+	RelatedURLs []string `json:"relatedUrls,omitempty"`
 	// Place fields
-	PlaceID string   `json:"placeId,omitempty"`
-	Lat     float64  `json:"lat,omitempty"`
-	Lng     float64  `json:"lng,omitempty"`
-	Types   []string `json:"types,omitempty"`
+	PlaceID      string   `json:"placeId,omitempty"`
+	Lat          float64  `json:"lat,omitempty"`
+	Lng          float64  `json:"lng,omitempty"`
+	Types        []string `json:"types,omitempty"`
+	Distance     float64  `json:"distance,omitempty"` // meters, when lat/lng is sent
+	DistanceText string   `json:"distanceText,omitempty"`
 	// Video fields
 	VideoID   string `json:"videoId,omitempty"`
 	Duration  string `json:"duration,omitempty"`
@@ -79,6 +126,7 @@ type WebsiteSearchResponse struct {
 	TotalCount int64           `json:"totalCount"`
 	Page       int             `json:"page"`
 	PageSize   int             `json:"pageSize"`
+	FromCache  bool            `json:"-"`
 }
 
 type WebsiteResult struct {
@@ -113,6 +161,7 @@ type VideoSearchResponse struct {
 	TotalCount int64         `json:"totalCount"`
 	Page       int           `json:"page"`
 	PageSize   int           `json:"pageSize"`
+	FromCache  bool          `json:"-"`
 }
 
 type VideoResult struct {
@@ -130,11 +179,21 @@ type VideoResult struct {
 type PlaceSearchResponse struct {
 	Query      string        `json:"query"`
 	Results    []PlaceResult `json:"results"`
-	TotalCount int64         `json:"totalCount"`
+	TotalCount int64         `json:"totalCount"` // places loaded so far; exact once hasMore is false
 	Page       int           `json:"page"`
 	PageSize   int           `json:"pageSize"`
+	HasMore    bool          `json:"hasMore"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	Source     string        `json:"source,omitempty"` // google or local (places table)
+	FromCache  bool          `json:"-"`
 }
 
+// Where place results came from
+const (
+	PlaceSourceGoogle = "google"
+	PlaceSourceLocal  = "local"
+)
+
 type PlaceResult struct {
 	PlaceID      string   `json:"placeId"`
 	Name         string   `json:"name"`
@@ -149,6 +208,7 @@ type PlaceResult struct {
 	IsOpen       *bool    `json:"isOpen,omitempty"`
 	Distance     float64  `json:"distance,omitempty"` // distance in meters from user location
 	DistanceText string   `json:"distanceText,omitempty"`
+	ProvinceCode string   `json:"provinceCode,omitempty"` // DOPA province code, e.g. "50" for Chiang Mai
 }
 
 type PlaceDetailResponse struct {
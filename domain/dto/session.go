@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+// SessionResponse is one signed-in device
+type SessionResponse struct {
+	ID           uuid.UUID `json:"id"`
+	UserAgent    string    `json:"userAgent"`
+	IPAddress    string    `json:"ipAddress"`
+	AuthProvider string    `json:"authProvider"` // email, google, line
+	LastSeenAt   time.Time `json:"lastSeenAt"`
+	CreatedAt    time.Time `json:"createdAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Current      bool      `json:"current"` // the session making this request
+}
+
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// SessionToSessionResponse converts UserSession model to SessionResponse DTO
+func SessionToSessionResponse(session *models.UserSession, currentSessionID uuid.UUID) SessionResponse {
+	return SessionResponse{
+		ID:           session.ID,
+		UserAgent:    session.UserAgent,
+		IPAddress:    session.IPAddress,
+		AuthProvider: session.AuthProvider,
+		LastSeenAt:   session.LastUsedAt,
+		CreatedAt:    session.CreatedAt,
+		ExpiresAt:    session.ExpiresAt,
+		Current:      session.ID == currentSessionID,
+	}
+}
@@ -0,0 +1,44 @@
+package dto
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"` // admins can't turn 2FA off
+	RecoveryCodesLeft int64 `json:"recoveryCodesLeft"`
+}
+
+// TwoFactorSetupResponse is shown once while enrolling an authenticator app
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`     // for typing into the app by hand
+	OTPAuthURL string `json:"otpauthUrl"` // otpauth://totp/...
+	QRCode     string `json:"qrCode"`     // PNG data URL of OTPAuthURL
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=20"` // authenticator code or recovery code
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // shown once; only hashes are kept
+}
+
+// TwoFactorChallenge is returned instead of tokens when a sign-in needs a
+// second factor. SetupRequired means the account must enrol first (admins).
+type TwoFactorChallenge struct {
+	ChallengeToken string `json:"challengeToken"`
+	SetupRequired  bool   `json:"setupRequired"`
+	ExpiresIn      int    `json:"expiresIn"` // seconds
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool `json:"twoFactorRequired"`
+	TwoFactorChallenge
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required,min=6,max=20"`
+}
@@ -36,20 +36,25 @@ type UpdateAvatarResponse struct {
 }
 
 type UserResponse struct {
-	ID           uuid.UUID `json:"id"`
-	Email        string    `json:"email"`
-	Username     string    `json:"username"`
-	FirstName    string    `json:"firstName"`
-	LastName     string    `json:"lastName"`
-	Avatar       string    `json:"avatar"`
-	StudentID    string    `json:"studentId,omitempty"`
-	Language     string    `json:"language"`
-	Theme        string    `json:"theme"`
-	Role         string    `json:"role"`
-	IsActive     bool      `json:"isActive"`
-	AuthProvider string    `json:"authProvider"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"`
+	Username      string    `json:"username"`
+	FirstName     string    `json:"firstName"`
+	LastName      string    `json:"lastName"`
+	Avatar        string    `json:"avatar"`
+	StudentID     string    `json:"studentId,omitempty"`
+	Language      string    `json:"language"`
+	Theme         string    `json:"theme"`
+	Role          string    `json:"role"`
+	IsActive      bool      `json:"isActive"`
+	EmailVerified bool      `json:"emailVerified"`
+	TwoFactor     bool      `json:"twoFactorEnabled"`
+	AuthProvider  string    `json:"authProvider"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+
+	// Set while a deleted account can still be restored
+	DeletionScheduledAt *time.Time `json:"deletionScheduledAt,omitempty"`
 }
 
 type UserListResponse struct {
@@ -57,6 +62,11 @@ type UserListResponse struct {
 	Meta  PaginationMeta `json:"meta"`
 }
 
+// UpdateUserStatusRequest - admin request to activate or deactivate a user
+type UpdateUserStatusRequest struct {
+	IsActive *bool `json:"isActive" validate:"required"`
+}
+
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"currentPassword" validate:"required"`
 	NewPassword     string `json:"newPassword" validate:"required,min=8,max=72"`
@@ -76,19 +86,23 @@ func UserToUserResponse(user *models.User) *UserResponse {
 	}
 
 	return &UserResponse{
-		ID:           user.ID,
-		Email:        user.Email,
-		Username:     user.Username,
-		FirstName:    user.FirstName,
-		LastName:     user.LastName,
-		Avatar:       user.Avatar,
-		StudentID:    studentID,
-		Language:     user.Language,
-		Theme:        user.Theme,
-		Role:         user.Role,
-		IsActive:     user.IsActive,
-		AuthProvider: user.AuthProvider,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		ID:            user.ID,
+		Email:         user.Email,
+		Username:      user.Username,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Avatar:        user.Avatar,
+		StudentID:     studentID,
+		Language:      user.Language,
+		Theme:         user.Theme,
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		EmailVerified: user.IsEmailVerified(),
+		TwoFactor:     user.IsTwoFactorEnabled(),
+		AuthProvider:  user.AuthProvider,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+
+		DeletionScheduledAt: user.DeletionScheduledAt,
 	}
 }
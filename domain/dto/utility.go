@@ -67,6 +67,7 @@ type NearbyPlacesRequest struct {
 	Page      int     `json:"page" query:"page" validate:"omitempty,min=1"`
 	PageSize  int     `json:"pageSize" query:"pageSize" validate:"omitempty,min=1,max=20"`
 	Lang      string  `json:"lang" query:"lang" validate:"omitempty,len=2"`
+	Cursor    string  `json:"cursor" query:"cursor" validate:"omitempty,max=2048"` // nextCursor of the previous page
 }
 
 // ==================== Location DTOs ====================
@@ -81,20 +82,36 @@ type GeocodeRequest struct {
 }
 
 type GeocodeResponse struct {
-	Address       string  `json:"address"`
-	FormattedAddr string  `json:"formattedAddress"`
-	Lat           float64 `json:"lat"`
-	Lng           float64 `json:"lng"`
-	PlaceID       string  `json:"placeId,omitempty"`
+	Address       string             `json:"address"`
+	FormattedAddr string             `json:"formattedAddress"`
+	Lat           float64            `json:"lat"`
+	Lng           float64            `json:"lng"`
+	PlaceID       string             `json:"placeId,omitempty"`
+	Admin         LocalizedAdminArea `json:"admin"`
+}
+
+type ReverseGeocodeRequest struct {
+	Lat      float64 `json:"lat" query:"lat" validate:"required,latitude"`
+	Lng      float64 `json:"lng" query:"lng" validate:"required,longitude"`
+	Detailed bool    `json:"detailed" query:"detailed"` // also ask Google for street, postal code and place ID
 }
 
 type ReverseGeocodeResponse struct {
-	Lat           float64 `json:"lat"`
-	Lng           float64 `json:"lng"`
-	FormattedAddr string  `json:"formattedAddress"`
-	Components    AddressComponents `json:"components"`
+	Lat           float64            `json:"lat"`
+	Lng           float64            `json:"lng"`
+	FormattedAddr string             `json:"formattedAddress"`
+	PlaceID       string             `json:"placeId,omitempty"`
+	Components    AddressComponents  `json:"components"`
+	Admin         LocalizedAdminArea `json:"admin"`
+	Source        string             `json:"source"` // boundaries or google
 }
 
+// ReverseGeocodeResponse sources
+const (
+	GeocodeSourceBoundaries = "boundaries" // offline admin boundary lookup
+	GeocodeSourceGoogle     = "google"
+)
+
 type AddressComponents struct {
 	Street       string `json:"street,omitempty"`
 	Subdistrict  string `json:"subdistrict,omitempty"`
@@ -104,11 +121,19 @@ type AddressComponents struct {
 	PostalCode   string `json:"postalCode,omitempty"`
 }
 
-// ==================== Health Check DTOs ====================
+// AdminArea is a Thai administrative location in one language
+type AdminArea struct {
+	Province string `json:"province,omitempty"` // จังหวัด
+	Amphoe   string `json:"amphoe,omitempty"`   // อำเภอ / เขต
+	Tambon   string `json:"tambon,omitempty"`   // ตำบล / แขวง
+}
 
-type HealthCheckResponse struct {
-	Status    string            `json:"status"`
-	Version   string            `json:"version"`
-	Timestamp string            `json:"timestamp"`
-	Services  map[string]string `json:"services"`
+// LocalizedAdminArea carries the administrative location in Thai and English.
+// Codes are DOPA codes and are only set when the offline boundaries matched.
+type LocalizedAdminArea struct {
+	TH           AdminArea `json:"th"`
+	EN           AdminArea `json:"en"`
+	ProvinceCode string    `json:"provinceCode,omitempty"`
+	AmphoeCode   string    `json:"amphoeCode,omitempty"`
+	TambonCode   string    `json:"tambonCode,omitempty"`
 }
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// API key scopes: which endpoints a key can call
+const (
+	APIKeyScopeSearch = "search" // /search, /search/websites, /images, /videos, /places, /nearby
+	APIKeyScopePlaces = "places" // /search/places/:placeId and /enhanced
+)
+
+// APIKey lets a user's own backend call the API without signing in. Only the
+// SHA-256 hash of the key is stored; the key is shown once when created.
+type APIKey struct {
+	ID         uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Prefix     string    `gorm:"type:varchar(20);not null"`             // start of the key, to tell keys apart
+	KeyHash    string    `gorm:"type:varchar(64);not null;uniqueIndex"` // hex SHA-256 of the whole key
+	Scopes     string    `gorm:"type:varchar(255);not null"`            // comma-separated
+	DailyLimit int       `gorm:"not null"`                              // requests per day across all endpoints
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList returns the key's scopes
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// IsExpired returns true once the key can no longer be used
+func (k *APIKey) IsExpired() bool {
+	return time.Now().After(k.ExpiresAt)
+}
@@ -26,8 +26,14 @@ type APIRequestLog struct {
 	EstimatedCost float64 `gorm:"type:decimal(10,6);default:0"` // Estimated cost in USD
 	FieldsUsed    string  `gorm:"type:varchar(500)"`            // Fields requested (for Places API)
 
+	// LLM usage; Endpoint holds the feature (ai_search, chat, ...)
+	Model            string `gorm:"type:varchar(100);index"`
+	PromptTokens     int    `gorm:"default:0"`
+	CompletionTokens int    `gorm:"default:0"`
+
 	// User tracking
 	UserID    *uuid.UUID `gorm:"type:uuid;index"` // NULL for guest
+	APIKeyID  *uuid.UUID `gorm:"type:uuid;index"` // set when the request was made with an API key
 	IPAddress string     `gorm:"type:varchar(45)"`
 	UserAgent string     `gorm:"type:varchar(500)"`
 
@@ -85,5 +91,17 @@ const (
 	CostPlacesPhoto        = 0.007  // $7 per 1000
 	CostGoogleTranslate    = 0.00002 // $20 per 1M characters
 	CostYouTubeSearch      = 0.0001 // Quota based
-	CostOpenAI             = 0.002  // ~$2 per 1000 tokens (varies)
 )
+
+// LLM calls are priced per token from config.LLMConfig.Prices
+
+// LLMUsageStats represents token usage and cost of one feature on one model
+type LLMUsageStats struct {
+	Feature          string  `json:"feature"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	TotalRequests    int64   `json:"totalRequests"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	TotalCost        float64 `json:"totalCost"`
+}
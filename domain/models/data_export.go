@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data export statuses
+const (
+	DataExportPending = "pending" // being built in the background
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+// DataExport is a "download my data" ZIP with everything stored about a
+// user. It's built in the background, kept in object storage under
+// ObjectKey and deleted once it expires.
+type DataExport struct {
+	ID          uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	ObjectKey   string    `gorm:"type:varchar(255)"`
+	Size        int64     `gorm:"default:0"`
+	Error       string    `gorm:"type:text"` // why the build failed, for the logs; not shown to the user
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index"` // set when the build ends; the export is deleted after this
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// IsDownloadable returns true if the ZIP is ready and hasn't expired
+func (e *DataExport) IsDownloadable() bool {
+	return e.Status == DataExportReady && e.ExpiresAt != nil && time.Now().Before(*e.ExpiresAt)
+}
+
+// PersonalDataSet is one table's worth of a user's data, as column/value rows
+type PersonalDataSet struct {
+	Name string
+	Rows []map[string]interface{}
+}
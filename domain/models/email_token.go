@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email token purposes
+const (
+	EmailTokenVerifyEmail   = "verify_email"
+	EmailTokenResetPassword = "reset_password"
+)
+
+// EmailToken records a link sent by email. The link itself carries a signed
+// token whose ID is this row; the row makes it single-use and lets a newer
+// link replace an older one.
+type EmailToken struct {
+	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Purpose   string     `gorm:"type:varchar(20);not null"` // verify_email, reset_password
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // also set when a newer token replaced this one
+	CreatedAt time.Time
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (EmailToken) TableName() string {
+	return "email_tokens"
+}
@@ -23,7 +23,8 @@ type Favorite struct {
 	CreatedAt    time.Time
 
 	// Relationships
-	User User `gorm:"foreignKey:UserID"`
+	User  User   `gorm:"foreignKey:UserID"`
+	Place *Place `gorm:"foreignKey:ExternalID;references:PlaceID;-:migration"` // live place data for type=place, no FK since ExternalID is shared by all types
 }
 
 func (Favorite) TableName() string {
@@ -8,8 +8,9 @@ import (
 type Job struct {
 	ID        uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	Name      string     `gorm:"not null"`
+	Handler   string     `gorm:"type:varchar(50);not null;default:''"` // registered job handler that runs it
 	CronExpr  string     `gorm:"not null"`
-	Payload   string     `gorm:"type:jsonb"`
+	Payload   string     `gorm:"type:jsonb"` // handler-specific parameters
 	Status    string     `gorm:"default:'active'"`
 	LastRun   *time.Time
 	NextRun   *time.Time
@@ -20,4 +21,38 @@ type Job struct {
 
 func (Job) TableName() string {
 	return "jobs"
-}
\ No newline at end of file
+}
+
+// What started a job run
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// Job run statuses
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// JobRun records one execution of a job
+type JobRun struct {
+	ID         uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	JobID      uuid.UUID `gorm:"type:uuid;not null;index:idx_job_runs_job_started,priority:1"`
+	Handler    string    `gorm:"type:varchar(50);not null"`
+	Trigger    string    `gorm:"type:varchar(20);not null"`
+	Status     string    `gorm:"type:varchar(20);not null"`
+	StartedAt  time.Time `gorm:"not null;index:idx_job_runs_job_started,priority:2,sort:desc"`
+	FinishedAt *time.Time
+	DurationMs int64
+	Output     string `gorm:"type:text"` // handler's summary of what it did
+	Error      string `gorm:"type:text"`
+	CreatedAt  time.Time
+
+	Job Job `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Roles stored in users.role
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Permission names are <resource>:<action>. An ":any" permission lets its
+// holder act on resources owned by other users; without it, users can only
+// touch their own.
+const (
+	PermFilesReadAny   = "files:read:any"
+	PermFilesDeleteAny = "files:delete:any"
+	PermTasksReadAny   = "tasks:read:any"
+	PermTasksUpdateAny = "tasks:update:any"
+	PermTasksDeleteAny = "tasks:delete:any"
+	PermUsersRead      = "users:read"
+	PermUsersManage    = "users:manage"
+	PermStatsRead      = "stats:read"
+	PermStatsManage    = "stats:manage"
+	PermJobsManage     = "jobs:manage"
+	PermRolesManage    = "roles:manage"
+)
+
+// DefaultPermissions is every permission the code checks, with the roles it
+// is granted to when it first appears in the database. Grants changed later
+// through the admin API are left alone.
+var DefaultPermissions = []DefaultPermission{
+	{PermFilesReadAny, "List and view any user's files", []string{RoleAdmin}},
+	{PermFilesDeleteAny, "Delete any user's files", []string{RoleAdmin}},
+	{PermTasksReadAny, "List and view any user's tasks", []string{RoleAdmin}},
+	{PermTasksUpdateAny, "Edit any user's tasks", []string{RoleAdmin}},
+	{PermTasksDeleteAny, "Delete any user's tasks", []string{RoleAdmin}},
+	{PermUsersRead, "List users", []string{RoleAdmin}},
+	{PermUsersManage, "Enable and disable accounts", []string{RoleAdmin}},
+	{PermStatsRead, "View API usage and cost statistics", []string{RoleAdmin}},
+	{PermStatsManage, "Delete old API request logs", []string{RoleAdmin}},
+	{PermJobsManage, "Create, run and stop scheduled jobs", []string{RoleAdmin}},
+	{PermRolesManage, "Grant and revoke role permissions", []string{RoleAdmin}},
+}
+
+type DefaultPermission struct {
+	Name        string
+	Description string
+	Roles       []string
+}
+
+// Permission is something a role can be allowed to do
+type Permission struct {
+	Name        string `gorm:"primaryKey;type:varchar(100)"`
+	Description string `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission grants a permission to every user with the role
+type RolePermission struct {
+	Role       string `gorm:"primaryKey;type:varchar(50)"`
+	Permission string `gorm:"primaryKey;type:varchar(100)"`
+	CreatedAt  time.Time
+
+	// Relationships
+	PermissionRef Permission `gorm:"foreignKey:Permission;references:Name;constraint:OnDelete:CASCADE"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
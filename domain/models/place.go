@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Place is the persistent catalogue entry for a Google place.
+// Language-neutral data lives in columns; names, addresses, opening hours and
+// reviews are kept per language in Localized.
+type Place struct {
+	PlaceID string `gorm:"primaryKey;type:varchar(255)"` // Google Place ID
+	Name    string `gorm:"type:varchar(500);not null"`   // name in the most recently fetched language
+	Address string `gorm:"type:text"`
+
+	Lat     float64 `gorm:"not null;index:idx_places_location"`
+	Lng     float64 `gorm:"not null;index:idx_places_location"`
+	Geohash string  `gorm:"type:varchar(12)"` // geo index fallback without PostGIS, see migration 0003_place_geo
+
+	Types         datatypes.JSON `gorm:"type:jsonb;default:'[]'"` // []string
+	Rating        float64        `gorm:"type:decimal(2,1);index"`
+	ReviewCount   int            `gorm:"default:0"`
+	PriceLevel    int            `gorm:"default:0"`
+	Phone         string         `gorm:"type:varchar(50)"`
+	Website       string         `gorm:"type:text"`
+	GoogleMapsURL string         `gorm:"type:text"`
+	Photos        datatypes.JSON `gorm:"type:jsonb;default:'[]'"` // []PlacePhotoRef
+	Localized     datatypes.JSON `gorm:"type:jsonb;default:'{}'"` // map[language]PlaceLocalized
+
+	LastFetchedAt time.Time `gorm:"not null;index"` // last time Google returned this place
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (Place) TableName() string {
+	return "places"
+}
+
+// PlacePhotoRef is a Google photo reference; URLs are built at read time
+// because they embed the API key
+type PlacePhotoRef struct {
+	Reference string `json:"reference"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
+
+// PlaceLocalized holds the language-specific fields of a place
+type PlaceLocalized struct {
+	Name             string        `json:"name"`
+	Address          string        `json:"address"`
+	OpeningHours     []string      `json:"openingHours,omitempty"`
+	Reviews          []PlaceReview `json:"reviews,omitempty"`
+	DetailsFetchedAt *time.Time    `json:"detailsFetchedAt,omitempty"` // set once Place Details was fetched in this language
+}
+
+// PlaceReview is a stored Google review
+type PlaceReview struct {
+	Author   string `json:"author"`
+	Rating   int    `json:"rating"`
+	Text     string `json:"text"`
+	Time     string `json:"time"`
+	PhotoURL string `json:"photoUrl,omitempty"`
+}
@@ -36,6 +36,9 @@ type PlaceAIContent struct {
 	ExpiresAt   time.Time `gorm:"not null;index"` // For cleanup old records
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	// Relationships
+	Place *Place `gorm:"foreignKey:PlaceID;references:PlaceID;-:migration"` // catalogue entry, not enforced so content survives place cleanup
 }
 
 func (PlaceAIContent) TableName() string {
@@ -23,13 +23,20 @@ type User struct {
 	Theme    string `gorm:"type:varchar(10);default:'light'"` // light, dark
 
 	// Status
-	Role     string `gorm:"default:'user'"`
-	IsActive bool   `gorm:"default:true"`
+	Role            string `gorm:"default:'user'"`
+	IsActive        bool   `gorm:"default:true"`
+	EmailVerifiedAt *time.Time
 
-	// OAuth Fields
-	GoogleID     *string `gorm:"type:varchar(255);uniqueIndex"` // Google OAuth ID
-	LineID       *string `gorm:"type:varchar(255);uniqueIndex"` // LINE OAuth ID
-	AuthProvider string  `gorm:"type:varchar(20);default:'local'"` // local, google, line
+	// Account deletion: set when the user deletes the account, which is
+	// purged once this time has passed
+	DeletionScheduledAt *time.Time `gorm:"index"`
+
+	// Two-factor authentication
+	TOTPSecret         string     `gorm:"type:varchar(255)"` // encrypted; set during setup, before 2FA is enabled
+	TwoFactorEnabledAt *time.Time // nil while 2FA is off
+
+	// OAuth Fields (linked Google/LINE accounts are in user_identities)
+	AuthProvider string `gorm:"type:varchar(20);default:'local'"` // how the account was created: email, google, line
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -39,6 +46,32 @@ func (User) TableName() string {
 	return "users"
 }
 
+// IsEmailVerified returns true if the user proved they own their email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsDeletionScheduled returns true if the account is waiting to be purged
+// and can still be restored
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// IsTwoFactorEnabled returns true if signing in needs an authenticator code
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// RequiresTwoFactor returns true if the user must use 2FA. Admins always do.
+func (u *User) RequiresTwoFactor() bool {
+	return u.IsTwoFactorEnabled() || u.Role == "admin"
+}
+
+// HasPassword returns true if the user can sign in with email and password
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
 // IsOAuthUser returns true if user registered via OAuth
 func (u *User) IsOAuthUser() bool {
 	return u.AuthProvider != "local" && u.AuthProvider != ""
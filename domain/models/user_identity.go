@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity providers
+const (
+	IdentityProviderGoogle = "google"
+	IdentityProviderLine   = "line"
+)
+
+// UserIdentity is an external login (Google, LINE) linked to a user. An
+// account can link several accounts of the same provider, and each provider
+// account belongs to at most one user.
+type UserIdentity struct {
+	ID       uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject  string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject"` // the provider's user ID
+	Email    string    `gorm:"type:varchar(255)"`                                                           // as reported by the provider, may be empty for LINE
+	LinkedAt time.Time `gorm:"not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a session was revoked
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedReuseDetected = "reuse_detected" // an already rotated refresh token was presented again
+	SessionRevokedDeactivated   = "deactivated"    // the account was deactivated or deleted
+	SessionRevokedByUser        = "revoked"        // signed out from another device
+	SessionRevokedPasswordReset = "password_reset" // the password was reset by email
+	SessionRevokedMerged        = "merged"         // the account was merged into another one
+)
+
+// UserSession is one signed-in device. Access tokens carry the session ID,
+// so revoking the session cuts off every token issued for it.
+type UserSession struct {
+	ID            uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index"`
+	AuthProvider  string     `gorm:"type:varchar(20)"` // email, google, line - how this device signed in
+	UserAgent     string     `gorm:"type:varchar(500)"`
+	IPAddress     string     `gorm:"type:varchar(45)"`
+	LastUsedAt    time.Time  `gorm:"not null"`
+	ExpiresAt     time.Time  `gorm:"not null;index"`
+	RevokedAt     *time.Time `gorm:"index"`
+	RevokedReason string     `gorm:"type:varchar(20)"` // logout, reuse_detected, deactivated, revoked, password_reset
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// IsActive returns true if the session can still be refreshed
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// RefreshToken is one link in a session's rotation chain. Only the SHA-256
+// of the token is stored; UsedAt is set when it's exchanged for a new pair,
+// and presenting it again afterwards revokes the whole session.
+type RefreshToken struct {
+	ID        uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+
+	// Relationships
+	Session UserSession `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE"`
+	User    User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	// GetByHash returns the key with its user preloaded
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.APIKey, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Delete returns false if the user has no such key
+	Delete(ctx context.Context, userID, keyID uuid.UUID) (bool, error)
+	// TouchLastUsed sets last_used_at unless it was set after notBefore
+	TouchLastUsed(ctx context.Context, keyID uuid.UUID, usedAt, notBefore time.Time) error
+}
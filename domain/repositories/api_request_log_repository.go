@@ -26,6 +26,9 @@ type APIRequestLogRepository interface {
 	// GetServiceCosts gets cost breakdown by service
 	GetServiceCosts(ctx context.Context, startDate, endDate time.Time) ([]models.ServiceCost, error)
 
+	// GetLLMUsage gets token usage and cost of LLM calls by feature and model
+	GetLLMUsage(ctx context.Context, startDate, endDate time.Time) ([]models.LLMUsageStats, error)
+
 	// GetTotalCost gets total estimated cost for a period
 	GetTotalCost(ctx context.Context, startDate, endDate time.Time) (float64, error)
 
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *models.DataExport) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.DataExport, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.DataExport, error)
+	Update(ctx context.Context, export *models.DataExport) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListExpired returns exports, ready or failed, that expired before the given time
+	ListExpired(ctx context.Context, before time.Time) ([]*models.DataExport, error)
+	// FailStale marks exports still pending since before createdBefore as
+	// failed; their build was cut off by a restart
+	FailStale(ctx context.Context, createdBefore, expiresAt time.Time) (int64, error)
+}
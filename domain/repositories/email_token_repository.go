@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type EmailTokenRepository interface {
+	// Create stores a token and retires the user's unused tokens for the same purpose
+	Create(ctx context.Context, token *models.EmailToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.EmailToken, error)
+	// Consume marks a token used; it returns false if it was already used
+	Consume(ctx context.Context, id uuid.UUID) (bool, error)
+}
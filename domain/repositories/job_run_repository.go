@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type JobRunRepository interface {
+	Create(ctx context.Context, run *models.JobRun) error
+	Update(ctx context.Context, run *models.JobRun) error
+	// ListByJobID lists runs of a job, newest first
+	ListByJobID(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, error)
+	CountByJobID(ctx context.Context, jobID uuid.UUID) (int64, error)
+}
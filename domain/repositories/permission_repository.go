@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"gofiber-template/domain/models"
+)
+
+type PermissionRepository interface {
+	ListPermissions(ctx context.Context) ([]*models.Permission, error)
+	ListRolePermissions(ctx context.Context) ([]*models.RolePermission, error)
+	// Grant is a no-op if the role already has the permission
+	Grant(ctx context.Context, role, permission string) error
+	// Revoke returns false if the role didn't have the permission
+	Revoke(ctx context.Context, role, permission string) (bool, error)
+}
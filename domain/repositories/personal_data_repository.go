@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+// PersonalDataRepository reads and erases everything stored about a user
+// across tables, for data exports and account deletion
+type PersonalDataRepository interface {
+	// Export returns the user's rows from every table that holds personal
+	// data, without secrets such as password and key hashes
+	Export(ctx context.Context, userID uuid.UUID) ([]models.PersonalDataSet, error)
+	// ObjectKeys returns the storage keys of the user's uploads and data exports
+	ObjectKeys(ctx context.Context, userID uuid.UUID) ([]string, error)
+	// FolderItemURLs returns the URLs of the items in the user's folders.
+	// Items uploaded to a folder are stored in R2 and known only by URL.
+	FolderItemURLs(ctx context.Context, userID uuid.UUID) ([]string, error)
+	// Purge deletes the user and everything they own in one transaction.
+	// API request logs are kept for cost statistics but anonymized.
+	Purge(ctx context.Context, userID uuid.UUID) error
+}
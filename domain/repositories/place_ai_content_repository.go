@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -30,6 +31,9 @@ type PlaceAIContentRepository interface {
 	// DeleteByPlaceID deletes by place ID
 	DeleteByPlaceID(ctx context.Context, placeID string) error
 
+	// ListExpiring lists records expiring before the given time, soonest first
+	ListExpiring(ctx context.Context, before time.Time, limit int) ([]*models.PlaceAIContent, error)
+
 	// DeleteExpired deletes all expired records
 	DeleteExpired(ctx context.Context) (int64, error)
 
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gofiber-template/domain/models"
+)
+
+// PlaceFilter narrows catalogue queries; zero values are ignored
+type PlaceFilter struct {
+	Text            string     // every word in the name or address, in any stored language
+	AddressContains string     // e.g. "เชียงใหม่" or "Chiang Mai"
+	Type            string     // Google place type, e.g. tourist_attraction
+	MinRating       float64    // inclusive
+	FetchedAfter    *time.Time // only places Google returned since then
+}
+
+// GeoBounds is a latitude/longitude box, e.g. a map viewport
+type GeoBounds struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+type PlaceRepository interface {
+	// UpsertDetails stores a Place Details result, replacing the detail fields for lang
+	UpsertDetails(ctx context.Context, place *models.Place, lang string) error
+
+	// UpsertSearchResults stores Text/Nearby Search results without touching detail-only fields
+	UpsertSearchResults(ctx context.Context, places []*models.Place, lang string) error
+
+	// GetByPlaceID gets a place by Google Place ID
+	GetByPlaceID(ctx context.Context, placeID string) (*models.Place, error)
+
+	// GetByPlaceIDs gets places by Google Place IDs, missing IDs are skipped
+	GetByPlaceIDs(ctx context.Context, placeIDs []string) ([]*models.Place, error)
+
+	// Find lists places matching the filter, best rated first
+	Find(ctx context.Context, filter PlaceFilter, offset, limit int) ([]*models.Place, error)
+
+	// Count counts places matching the filter
+	Count(ctx context.Context, filter PlaceFilter) (int64, error)
+
+	// FindWithinRadius lists places within radius meters of a point, nearest first
+	FindWithinRadius(ctx context.Context, lat, lng float64, radius int, filter PlaceFilter, offset, limit int) ([]*models.Place, error)
+
+	// CountWithinRadius counts places within radius meters of a point
+	CountWithinRadius(ctx context.Context, lat, lng float64, radius int, filter PlaceFilter) (int64, error)
+
+	// FindInBounds lists places inside a box, best rated first
+	FindInBounds(ctx context.Context, bounds GeoBounds, filter PlaceFilter, limit int) ([]*models.Place, error)
+
+	// FindNearest lists the k places nearest to a point
+	FindNearest(ctx context.Context, lat, lng float64, k int, filter PlaceFilter) ([]*models.Place, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type RecoveryCodeRepository interface {
+	// Replace deletes the user's codes and stores a new set
+	Replace(ctx context.Context, userID uuid.UUID, codes []*models.RecoveryCode) error
+	// Consume marks an unused code used; it returns false if none matched
+	Consume(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
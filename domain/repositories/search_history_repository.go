@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -19,4 +20,5 @@ type SearchHistoryRepository interface {
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountByUserIDAndType(ctx context.Context, userID uuid.UUID, searchType string) (int64, error)
 	GetRecentByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*models.SearchHistory, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
 }
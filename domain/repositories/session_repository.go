@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.UserSession, token *models.RefreshToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.UserSession, error)
+	ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserSession, error)
+	Touch(ctx context.Context, session *models.UserSession) error
+	Revoke(ctx context.Context, id uuid.UUID, reason string) error
+	RevokeAllByUserID(ctx context.Context, userID uuid.UUID, reason string, exceptID *uuid.UUID) ([]uuid.UUID, error)
+
+	// Refresh tokens
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// RotateRefreshToken marks oldID used and stores next in one transaction.
+	// It returns false if oldID was already used (a concurrent or replayed refresh).
+	RotateRefreshToken(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) (bool, error)
+}
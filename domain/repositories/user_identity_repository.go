@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/models"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error)
+	// Delete unlinks one of the user's identities; it returns false if the
+	// user has no identity with that ID
+	Delete(ctx context.Context, userID, identityID uuid.UUID) (bool, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gofiber-template/domain/models"
@@ -13,13 +14,25 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 	Update(ctx context.Context, id uuid.UUID, user *models.User) error
+	SetActive(ctx context.Context, id uuid.UUID, active bool) error
+	// SetTwoFactor saves the TOTP secret and enabled time, including clearing them
+	SetTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabledAt *time.Time) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// SetDeletionScheduledAt schedules the account's purge, or cancels it with nil
+	SetDeletionScheduledAt(ctx context.Context, id uuid.UUID, at *time.Time) error
+	// ListDueForDeletion returns accounts whose scheduled deletion is before the given time
+	ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*models.User, error)
 	List(ctx context.Context, offset, limit int) ([]*models.User, error)
+	// ListIDsWithPermission returns active users whose role has the permission
+	ListIDsWithPermission(ctx context.Context, permission string) ([]uuid.UUID, error)
 	Count(ctx context.Context) (int64, error)
 
 	// OAuth methods
-	GetByGoogleID(ctx context.Context, googleID string) (*models.User, error)
-	GetByLineID(ctx context.Context, lineID string) (*models.User, error)
+	// CreateWithIdentity creates a user signing up through an external provider
+	CreateWithIdentity(ctx context.Context, user *models.User, identity *models.UserIdentity) error
+	// MergeInto moves everything source owns to target, deletes source and
+	// saves target's account fields, all in one transaction
+	MergeInto(ctx context.Context, source, target *models.User) error
 
 	// Profile methods
 	GetByStudentID(ctx context.Context, studentID string) (*models.User, error)
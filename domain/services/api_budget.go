@@ -0,0 +1,11 @@
+package services
+
+import "errors"
+
+// Returned by services backed by a paid external API while its budget stops it
+var (
+	// ErrAPIBudgetDegraded means only cached or stored answers are given and this one isn't
+	ErrAPIBudgetDegraded = errors.New("external API budget nearly used up, only cached results are available")
+	// ErrAPIBudgetExhausted means the service is blocked until its budget period ends
+	ErrAPIBudgetExhausted = errors.New("external API budget used up, service paused")
+)
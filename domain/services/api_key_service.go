@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/pkg/utils"
+)
+
+var (
+	ErrInvalidAPIKey       = errors.New("API key ไม่ถูกต้อง หมดอายุ หรือถูกยกเลิกแล้ว")
+	ErrAPIKeyNotFound      = errors.New("ไม่พบ API key")
+	ErrAPIKeyLimitReached  = errors.New("สร้าง API key ครบจำนวนสูงสุดแล้ว กรุณายกเลิก key ที่ไม่ใช้ก่อน")
+	ErrAPIKeyExpiryTooLong = errors.New("อายุของ API key ยาวเกินกว่าที่กำหนด")
+	ErrAPIKeyDailyLimit    = errors.New("โควต้ารายวันของ API key สูงเกินกว่าที่กำหนด")
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, userID uuid.UUID, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]dto.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error
+
+	// AuthenticateAPIKey resolves a key from the X-API-Key header to its
+	// owner, who must still be active and not waiting to be deleted
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*utils.UserContext, *utils.APIKeyContext, error)
+}
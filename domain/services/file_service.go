@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"gofiber-template/domain/dto"
 	"gofiber-template/domain/models"
 	"mime/multipart"
@@ -9,10 +10,12 @@ import (
 	"github.com/google/uuid"
 )
 
+var ErrFileNotFound = errors.New("file not found")
+
 type FileService interface {
 	UploadFile(ctx context.Context, userID uuid.UUID, file *multipart.FileHeader, options *dto.UploadFileRequest) (*models.File, error)
-	GetFile(ctx context.Context, fileID uuid.UUID) (*models.File, error)
+	GetFile(ctx context.Context, actor Actor, fileID uuid.UUID) (*models.File, error)
 	GetUserFiles(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.File, int64, error)
-	DeleteFile(ctx context.Context, fileID uuid.UUID) error
+	DeleteFile(ctx context.Context, actor Actor, fileID uuid.UUID) error
 	ListFiles(ctx context.Context, offset, limit int) ([]*models.File, int64, error)
 }
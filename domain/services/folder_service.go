@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"mime/multipart"
 
 	"github.com/google/uuid"
@@ -9,6 +10,13 @@ import (
 	"gofiber-template/domain/dto"
 )
 
+var (
+	ErrFolderNotFound     = errors.New("folder not found")
+	ErrFolderItemNotFound = errors.New("item not found")
+)
+
+// FolderService only lets users manage their own folders; anyone can read
+// a public one
 type FolderService interface {
 	// Folder operations
 	CreateFolder(ctx context.Context, userID uuid.UUID, req *dto.CreateFolderRequest) (*dto.FolderResponse, error)
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"gofiber-template/domain/dto"
+)
+
+// HealthProbe checks one dependency. Check returns a short detail, such as
+// the schema version, or an error when the dependency is unusable.
+type HealthProbe struct {
+	Name     string
+	Critical bool          // a failure makes the instance unhealthy rather than degraded
+	CacheFor time.Duration // reuse the last result this long; for probes of external APIs
+	Check    func(ctx context.Context) (string, error)
+}
+
+type HealthService interface {
+	// Liveness reports whether the process is up; it checks no dependencies
+	Liveness(ctx context.Context) *dto.HealthReport
+	// Readiness runs every probe concurrently
+	Readiness(ctx context.Context) *dto.HealthReport
+}
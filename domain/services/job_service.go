@@ -2,11 +2,15 @@ package services
 
 import (
 	"context"
+	"errors"
 	"gofiber-template/domain/dto"
 	"gofiber-template/domain/models"
 	"github.com/google/uuid"
 )
 
+// ErrJobRunning is returned when a job is triggered while a run of it is in progress
+var ErrJobRunning = errors.New("job is already running")
+
 type JobService interface {
 	CreateJob(ctx context.Context, req *dto.CreateJobRequest) (*models.Job, error)
 	GetJob(ctx context.Context, jobID uuid.UUID) (*models.Job, error)
@@ -16,4 +20,21 @@ type JobService interface {
 	StartJob(ctx context.Context, jobID uuid.UUID) error
 	StopJob(ctx context.Context, jobID uuid.UUID) error
 	ExecuteJob(ctx context.Context, job *models.Job) error
-}
\ No newline at end of file
+
+	// RunJob starts a run of the job now, outside its schedule, and returns
+	// the run record without waiting for it to finish
+	RunJob(ctx context.Context, jobID uuid.UUID) (*models.JobRun, error)
+	ListJobRuns(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, int64, error)
+}
+
+// JobHandler runs one kind of job. A job names its handler and stores the
+// handler's parameters as a JSON payload.
+type JobHandler interface {
+	Name() string
+	// ParsePayload decodes and validates a payload into the handler's own
+	// type, filling defaults for omitted fields
+	ParsePayload(payload string) (interface{}, error)
+	// Run runs the job with a parsed payload and returns a short summary of
+	// what it did
+	Run(ctx context.Context, payload interface{}) (string, error)
+}
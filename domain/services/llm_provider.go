@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+)
+
+// LLM message roles
+const (
+	LLMRoleSystem    = "system"
+	LLMRoleUser      = "user"
+	LLMRoleAssistant = "assistant"
+)
+
+// LLM features, used to break down token usage and cost
+const (
+	LLMFeatureAISearch      = "ai_search"
+	LLMFeatureChat          = "chat"
+	LLMFeaturePlaceOverview = "place_overview"
+	LLMFeatureGuideInfo     = "guide_info"
+)
+
+// LLMMessage is a single message in a chat completion
+type LLMMessage struct {
+	Role    string
+	Content string
+}
+
+// LLMRequest describes a chat completion request independent of the provider
+type LLMRequest struct {
+	Model       string // empty uses the provider default
+	Messages    []LLMMessage
+	MaxTokens   int
+	Temperature float64
+	JSONMode    bool   // ask the model to answer with a single JSON object
+	Feature     string // what the answer is for (LLMFeature*); used for usage accounting and tracing
+}
+
+// LLMResponse is the completed answer of a chat completion
+type LLMResponse struct {
+	Content          string
+	Model            string
+	FinishReason     string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// LLMStreamHandler receives each content delta of a streamed completion.
+// Returning an error stops the stream.
+type LLMStreamHandler func(delta string) error
+
+// LLMProvider is a chat completion backend (OpenAI, Azure, vLLM, Ollama, fake)
+type LLMProvider interface {
+	// Name returns the provider name used in logs and config
+	Name() string
+
+	// Chat sends a blocking chat completion request
+	Chat(ctx context.Context, req *LLMRequest) (*LLMResponse, error)
+
+	// ChatStream streams a chat completion, calling onDelta for each content delta.
+	// The returned response holds the full content received so far, even on error.
+	ChatStream(ctx context.Context, req *LLMRequest, onDelta LLMStreamHandler) (*LLMResponse, error)
+}
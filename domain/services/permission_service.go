@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+)
+
+var (
+	ErrForbidden            = errors.New("you do not have permission to access this resource")
+	ErrUnknownPermission    = errors.New("permission does not exist")
+	ErrPermissionNotGranted = errors.New("role does not have this permission")
+	ErrProtectedPermission  = errors.New("admins must keep the roles:manage permission")
+)
+
+// Actor is the signed-in user a request is made for. Services compare it
+// with a resource's owner and fall back to the role's ":any" permission.
+type Actor struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+type PermissionService interface {
+	// HasPermission fails closed: it returns false if permissions can't be loaded
+	HasPermission(ctx context.Context, role, permission string) bool
+	ListPermissions(ctx context.Context) (*dto.PermissionListResponse, error)
+	GrantPermission(ctx context.Context, role, permission string) error
+	RevokePermission(ctx context.Context, role, permission string) error
+}
@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
 	"gofiber-template/domain/dto"
 )
 
+// ErrInvalidCursor is returned when a pagination cursor is malformed or belongs to another search
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type SearchService interface {
 	// Unified search
 	Search(ctx context.Context, userID uuid.UUID, req *dto.SearchRequest) (*dto.SearchResponse, error)
@@ -26,8 +30,13 @@ type SearchService interface {
 	SearchPlaces(ctx context.Context, userID uuid.UUID, req *dto.PlaceSearchRequest) (*dto.PlaceSearchResponse, error)
 	GetPlaceDetails(ctx context.Context, placeID string, userLat, userLng float64, lang string) (*dto.PlaceDetailResponse, error)
 	GetPlaceDetailsEnhanced(ctx context.Context, placeID string, userLat, userLng float64, lang string, includeAI bool) (*dto.PlaceDetailEnhancedResponse, error)
+	RefreshPlaceAIContent(ctx context.Context, placeID, lang string) error
 	SearchNearbyPlaces(ctx context.Context, req *dto.NearbyPlacesRequest) (*dto.PlaceSearchResponse, error)
 
+	// Geo queries over stored places (no Google call)
+	SearchPlacesInBounds(ctx context.Context, req *dto.PlacesInBoundsRequest) (*dto.PlaceSearchResponse, error)
+	SearchNearestPlaces(ctx context.Context, req *dto.NearestPlacesRequest) (*dto.PlaceSearchResponse, error)
+
 	// Search history
 	GetSearchHistory(ctx context.Context, userID uuid.UUID, req *dto.GetSearchHistoryRequest) (*dto.SearchHistoryListResponse, error)
 	ClearSearchHistory(ctx context.Context, userID uuid.UUID, req *dto.ClearSearchHistoryRequest) error
@@ -2,30 +2,115 @@ package services
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/google/uuid"
 	"gofiber-template/domain/dto"
 	"gofiber-template/domain/models"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token ไม่ถูกต้องหรือหมดอายุ")
+	ErrRefreshTokenReused  = errors.New("ตรวจพบการใช้ refresh token ซ้ำ กรุณาเข้าสู่ระบบใหม่")
+	ErrAccountDisabled     = errors.New("บัญชีนี้ถูกระงับการใช้งาน")
+	ErrSessionNotFound     = errors.New("ไม่พบ session")
+
+	ErrInvalidEmailToken    = errors.New("ลิงก์ไม่ถูกต้องหรือหมดอายุแล้ว")
+	ErrEmailAlreadyVerified = errors.New("อีเมลนี้ยืนยันแล้ว")
+	ErrEmailThrottled       = errors.New("ส่งอีเมลบ่อยเกินไป กรุณารอสักครู่แล้วลองใหม่")
+
+	ErrUnsupportedProvider   = errors.New("ไม่รองรับการเข้าสู่ระบบด้วยผู้ให้บริการนี้")
+	ErrIdentityNotFound      = errors.New("ยังไม่ได้เชื่อมต่อบัญชีนี้")
+	ErrLastLoginMethod       = errors.New("ไม่สามารถยกเลิกการเชื่อมต่อได้ เพราะเป็นช่องทางเข้าสู่ระบบเดียวที่เหลืออยู่")
+	ErrInvalidLinkToken      = errors.New("คำขอเชื่อมต่อบัญชีไม่ถูกต้องหรือหมดอายุแล้ว")
+
+	ErrInvalidTwoFactorCode      = errors.New("รหัสยืนยันตัวตนไม่ถูกต้อง")
+	ErrInvalidTwoFactorChallenge = errors.New("การเข้าสู่ระบบหมดเวลา กรุณาเข้าสู่ระบบใหม่")
+	ErrTwoFactorThrottled        = errors.New("ใส่รหัสผิดหลายครั้งเกินไป กรุณารอ 15 นาทีแล้วลองใหม่")
+	ErrTwoFactorNotSetUp         = errors.New("ยังไม่ได้ตั้งค่าการยืนยันตัวตนสองขั้นตอน")
+	ErrTwoFactorAlreadyEnabled   = errors.New("เปิดใช้การยืนยันตัวตนสองขั้นตอนอยู่แล้ว")
+	ErrTwoFactorRequired         = errors.New("ผู้ดูแลระบบต้องเปิดใช้การยืนยันตัวตนสองขั้นตอน")
+
+	ErrDataExportInProgress = errors.New("กำลังเตรียมข้อมูลของคุณอยู่ กรุณารอให้เสร็จก่อนขอใหม่")
+	ErrDataExportNotFound   = errors.New("ไม่พบไฟล์ข้อมูล หรือไฟล์หมดอายุแล้ว")
+	ErrStorageUnavailable   = errors.New("ระบบจัดเก็บไฟล์ไม่พร้อมใช้งาน กรุณาลองใหม่ภายหลัง")
+	ErrDeletionNotScheduled = errors.New("บัญชีนี้ไม่ได้อยู่ระหว่างรอการลบ")
+)
+
+// TwoFactorChallengeError is returned by the sign-in methods when the
+// password or provider check passed but a second factor is still needed.
+// The handler sends the challenge to the client instead of tokens.
+type TwoFactorChallengeError struct {
+	Challenge *dto.TwoFactorChallenge
+}
+
+func (e *TwoFactorChallengeError) Error() string {
+	return "two-factor authentication required"
+}
+
 type UserService interface {
 	Register(ctx context.Context, req *dto.CreateUserRequest) (*models.User, error)
-	Login(ctx context.Context, req *dto.LoginRequest) (string, *models.User, error)
+	Login(ctx context.Context, req *dto.LoginRequest, client dto.ClientInfo) (*dto.AuthTokens, *models.User, error)
 	GetProfile(ctx context.Context, userID uuid.UUID) (*models.User, error)
 	UpdateProfile(ctx context.Context, userID uuid.UUID, req *dto.UpdateUserRequest) (*models.User, error)
-	DeleteUser(ctx context.Context, userID uuid.UUID) error
 	ListUsers(ctx context.Context, offset, limit int) ([]*models.User, int64, error)
-	GenerateJWT(user *models.User) (string, error)
-	ValidateJWT(token string) (*models.User, error)
+	SetUserActive(ctx context.Context, userID uuid.UUID, active bool) (*models.User, error)
+
+	// Session methods
+	IssueTokens(ctx context.Context, user *models.User, client dto.ClientInfo) (*dto.AuthTokens, error)
+	RefreshTokens(ctx context.Context, refreshToken string, client dto.ClientInfo) (*dto.AuthTokens, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	LogoutByRefreshToken(ctx context.Context, refreshToken string) error
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]*models.UserSession, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error)
 
 	// Profile methods
 	UpdateProfileInfo(ctx context.Context, userID uuid.UUID, req *dto.UpdateProfileRequest) (*models.User, error)
 	UpdateAvatar(ctx context.Context, userID uuid.UUID, fileData []byte, contentType string) (*dto.UpdateAvatarResponse, error)
 	DeleteAvatar(ctx context.Context, userID uuid.UUID) error
 
+	// Email verification and password reset
+	SendVerificationEmail(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	// Linked identities
+	ListIdentities(ctx context.Context, userID uuid.UUID) (*dto.IdentityListResponse, error)
+	CreateIdentityLinkToken(ctx context.Context, userID uuid.UUID, provider string) (string, error)
+	LinkIdentity(ctx context.Context, provider, code string, stateData dto.OAuthStateData) (*dto.LinkIdentityResult, error)
+	UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error
+	MergeAccounts(ctx context.Context, userID uuid.UUID, mergeToken string) (*models.User, error)
+
+	// Two-factor authentication
+	GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorStatusResponse, error)
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*dto.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	SetupTwoFactorForChallenge(ctx context.Context, challengeToken string) (*dto.TwoFactorSetupResponse, error)
+	CompleteTwoFactorLogin(ctx context.Context, challengeToken, code string, client dto.ClientInfo) (*dto.AuthTokens, *models.User, []string, error)
+
+	// Personal data: "download my data" exports and account deletion
+	RequestDataExport(ctx context.Context, userID uuid.UUID) (*models.DataExport, error)
+	ListDataExports(ctx context.Context, userID uuid.UUID) ([]*models.DataExport, error)
+	// OpenDataExport returns a ready export and its ZIP; the caller closes it
+	OpenDataExport(ctx context.Context, userID, exportID uuid.UUID) (*models.DataExport, io.ReadCloser, error)
+	// ScheduleDeletion deletes the account once the grace period has passed;
+	// until then RestoreAccount undoes it
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	RestoreAccount(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	// PurgeDueAccounts deletes accounts whose grace period is over, with
+	// everything they own in the database and object storage
+	PurgeDueAccounts(ctx context.Context) (int, error)
+	// CleanupDataExports deletes expired exports and fails stuck ones
+	CleanupDataExports(ctx context.Context) (int, error)
+
 	// OAuth methods
 	GetGoogleAuthURL(state string) string
-	HandleGoogleCallback(ctx context.Context, code string, stateData dto.OAuthStateData) (string, *models.User, bool, error)
+	HandleGoogleCallback(ctx context.Context, code string, stateData dto.OAuthStateData, client dto.ClientInfo) (*dto.AuthTokens, *models.User, bool, error)
 	GetLineAuthURL(state string) string
-	HandleLineCallback(ctx context.Context, code string, stateData dto.OAuthStateData) (string, *models.User, bool, error)
+	HandleLineCallback(ctx context.Context, code string, stateData dto.OAuthStateData, client dto.ClientInfo) (*dto.AuthTokens, *models.User, bool, error)
 }
\ No newline at end of file
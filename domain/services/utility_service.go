@@ -2,10 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"gofiber-template/domain/dto"
 )
 
+// ErrLocationNotFound is returned when geocoding finds no match for an address or point
+var ErrLocationNotFound = errors.New("location not found")
+
 type UtilityService interface {
 	// Translation
 	Translate(ctx context.Context, req *dto.TranslateRequest) (*dto.TranslateResponse, error)
@@ -19,8 +23,5 @@ type UtilityService interface {
 
 	// Geocoding
 	Geocode(ctx context.Context, req *dto.GeocodeRequest) (*dto.GeocodeResponse, error)
-	ReverseGeocode(ctx context.Context, req *dto.LocationRequest) (*dto.ReverseGeocodeResponse, error)
-
-	// Health check
-	HealthCheck(ctx context.Context) (*dto.HealthCheckResponse, error)
+	ReverseGeocode(ctx context.Context, req *dto.ReverseGeocodeRequest) (*dto.ReverseGeocodeResponse, error)
 }
@@ -4,6 +4,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -16,21 +17,47 @@ const (
 	PrefixNearbyPlaces = "places:nearby"
 	PrefixYouTube      = "youtube"
 	PrefixTranslate    = "translate"
+	PrefixGeocode      = "geocode"
 	PrefixUserSession  = "user:session"
+	PrefixRateLimit    = "ratelimit"
+	PrefixMailThrottle = "mail:throttle"
+	PrefixTwoFactor    = "user:2fa"
+	PrefixAPIBudget    = "budget"
+	PrefixDetectLang   = "detect"
 )
 
+// prefixes lists the key prefixes longest first, so PrefixOf finds
+// "search:ai" before "search"
+var prefixes = []string{
+	PrefixPlaceDetails, PrefixNearbyPlaces, PrefixMailThrottle, PrefixUserSession,
+	PrefixSearchAI, PrefixTwoFactor, PrefixRateLimit, PrefixTranslate, PrefixAPIBudget,
+	PrefixYouTube, PrefixGeocode, PrefixSearch, PrefixDetectLang, PrefixPlace,
+}
+
 // Cache TTLs - Optimized for tourism data (rarely changes)
 const (
-	TTLSearch       = 7 * 24 * time.Hour // 7 days - search results stable
-	TTLSearchAI     = 7 * 24 * time.Hour // 7 days - AI search results
-	TTLPlace        = 7 * 24 * time.Hour // 7 days - place info rarely changes
-	TTLPlaceDetails = 7 * 24 * time.Hour // 7 days - place details stable
-	TTLNearbyPlaces = 7 * 24 * time.Hour // 7 days - nearby places stable
-	TTLYouTube      = 7 * 24 * time.Hour // 7 days - video info stable
-	TTLTranslate    = 7 * 24 * time.Hour // 7 days - translations don't change
-	TTLUserSession  = 24 * time.Hour     // 24 hours - user sessions
+	TTLSearch       = 7 * 24 * time.Hour  // 7 days - search results stable
+	TTLSearchAI     = 7 * 24 * time.Hour  // 7 days - AI search results
+	TTLPlace        = 7 * 24 * time.Hour  // 7 days - place info rarely changes
+	TTLPlaceDetails = 7 * 24 * time.Hour  // 7 days - place details stable
+	TTLNearbyPlaces = 7 * 24 * time.Hour  // 7 days - nearby places stable
+	TTLYouTube      = 7 * 24 * time.Hour  // 7 days - video info stable
+	TTLTranslate    = 7 * 24 * time.Hour  // 7 days - translations don't change
+	TTLGeocode      = 30 * 24 * time.Hour // 30 days - addresses and boundaries rarely change
+	TTLUserSession  = 24 * time.Hour      // 24 hours - user sessions
 )
 
+// PrefixOf returns the prefix a key was built with, or "other" for keys that
+// don't come from this file. Metrics are labelled by prefix, never by key.
+func PrefixOf(key string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix+":") {
+			return prefix
+		}
+	}
+	return "other"
+}
+
 // hashString creates MD5 hash of a string
 func hashString(s string) string {
 	hash := md5.Sum([]byte(s))
@@ -77,6 +104,12 @@ func PlaceTextSearchKey(query, lang string) string {
 	return fmt.Sprintf("%s:text:%s:%s", PrefixPlace, hashString(query), lang)
 }
 
+// PlaceSearchPageKey generates cache key for one Places API result page of a search.
+// baseKey is the NearbyPlacesKey or PlaceTextSearchKey of the search.
+func PlaceSearchPageKey(baseKey string, page int) string {
+	return fmt.Sprintf("%s:page:%d", baseKey, page)
+}
+
 // YouTubeKey generates cache key for YouTube search results
 func YouTubeKey(query string, limit int) string {
 	key := fmt.Sprintf("%s:%d", query, limit)
@@ -89,9 +122,53 @@ func TranslateKey(text, sourceLang, targetLang string) string {
 	return fmt.Sprintf("%s:%s", PrefixTranslate, hashString(key))
 }
 
+// GeocodeKey generates cache key for forward geocoding
+func GeocodeKey(address, lang string) string {
+	return fmt.Sprintf("%s:%s:%s", PrefixGeocode, hashString(address), lang)
+}
+
+// ReverseGeocodeKey generates cache key for reverse geocoding.
+// Coordinates are rounded to 5 decimals (~1 m) so nearby taps share an entry.
+func ReverseGeocodeKey(lat, lng float64, lang string) string {
+	return fmt.Sprintf("%s:reverse:%.5f,%.5f:%s", PrefixGeocode, lat, lng, lang)
+}
+
+// RateLimitKey generates the counter key for one budget of one subject,
+// e.g. "ratelimit:search:user:<uuid>" or "ratelimit:search:guest:<ip>"
+func RateLimitKey(budget, subject string) string {
+	return fmt.Sprintf("%s:%s:%s", PrefixRateLimit, budget, subject)
+}
+
+// RevokedSessionKey marks a revoked session so its still-unexpired access
+// tokens are rejected; it only needs to live as long as an access token
+func RevokedSessionKey(sessionID string) string {
+	return fmt.Sprintf("%s:revoked:%s", PrefixUserSession, sessionID)
+}
+
+// MailThrottleKey limits how often one kind of email goes to one user
+func MailThrottleKey(purpose, userID string) string {
+	return fmt.Sprintf("%s:%s:%s", PrefixMailThrottle, purpose, userID)
+}
+
+// TwoFactorUsedCodeKey marks a TOTP time step as used so a code works once
+func TwoFactorUsedCodeKey(userID string, step int64) string {
+	return fmt.Sprintf("%s:used:%s:%d", PrefixTwoFactor, userID, step)
+}
+
+// TwoFactorAttemptsKey counts failed 2FA codes for a user
+func TwoFactorAttemptsKey(userID string) string {
+	return fmt.Sprintf("%s:attempts:%s", PrefixTwoFactor, userID)
+}
+
+// APIBudgetSpendKey holds a service's estimated spend for one period,
+// e.g. "budget:google_places:daily:2026-10-16" or "budget:youtube:monthly:2026-10"
+func APIBudgetSpendKey(service, period, date string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", PrefixAPIBudget, service, period, date)
+}
+
 // DetectLanguageKey generates cache key for language detection
 func DetectLanguageKey(text string) string {
-	return fmt.Sprintf("detect:%s", hashString(text))
+	return fmt.Sprintf("%s:%s", PrefixDetectLang, hashString(text))
 }
 
 // VideoDetailsKey generates cache key for video details
@@ -9,21 +9,30 @@ import (
 	"time"
 
 	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/metrics"
+	"gofiber-template/pkg/tracing"
 )
 
+// probeURL is fetched by Probe; any answer means Google's API front end is
+// reachable, and nothing is billed
+const probeURL = "https://www.googleapis.com/"
+
 // GoogleClient is the base client for all Google APIs
 type GoogleClient struct {
+	service    string // metrics label, e.g. "google_places"
 	apiKey     string
 	httpClient *http.Client
 }
 
-// NewGoogleClient creates a new Google API client
-func NewGoogleClient(apiKey string) *GoogleClient {
+// NewGoogleClient creates a new Google API client. service names the API
+// in metrics, matching the service names of the API request log.
+func NewGoogleClient(service, apiKey string) *GoogleClient {
 	return &GoogleClient{
-		apiKey: apiKey,
-		httpClient: &http.Client{
+		service: service,
+		apiKey:  apiKey,
+		httpClient: tracing.NewHTTPClient(&http.Client{
 			Timeout: 30 * time.Second,
-		},
+		}),
 	}
 }
 
@@ -41,6 +50,7 @@ func (c *GoogleClient) doRequest(ctx context.Context, url string, result interfa
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		metrics.ObserveExternalAPI(c.service, time.Since(startTime), metrics.ErrorTransport)
 		logger.ErrorContext(ctx, "Google API HTTP request failed",
 			"error", err.Error(),
 			"response_time_ms", time.Since(startTime).Milliseconds(),
@@ -51,6 +61,7 @@ func (c *GoogleClient) doRequest(ctx context.Context, url string, result interfa
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		metrics.ObserveExternalAPI(c.service, time.Since(startTime), metrics.ErrorTransport)
 		logger.ErrorContext(ctx, "Google API response read failed",
 			"error", err.Error(),
 			"status_code", resp.StatusCode,
@@ -59,6 +70,7 @@ func (c *GoogleClient) doRequest(ctx context.Context, url string, result interfa
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		metrics.ObserveExternalAPI(c.service, time.Since(startTime), metrics.ErrorStatus)
 		logger.ErrorContext(ctx, "Google API error response",
 			"status_code", resp.StatusCode,
 			"response_body", string(body),
@@ -68,6 +80,7 @@ func (c *GoogleClient) doRequest(ctx context.Context, url string, result interfa
 	}
 
 	if err := json.Unmarshal(body, result); err != nil {
+		metrics.ObserveExternalAPI(c.service, time.Since(startTime), metrics.ErrorDecode)
 		logger.ErrorContext(ctx, "Google API response parse failed",
 			"error", err.Error(),
 			"response_body", string(body),
@@ -75,6 +88,7 @@ func (c *GoogleClient) doRequest(ctx context.Context, url string, result interfa
 		return fmt.Errorf("decode response: %w", err)
 	}
 
+	metrics.ObserveExternalAPI(c.service, time.Since(startTime), "")
 	logger.DebugContext(ctx, "Google API request completed",
 		"response_time_ms", time.Since(startTime).Milliseconds(),
 	)
@@ -82,6 +96,25 @@ func (c *GoogleClient) doRequest(ctx context.Context, url string, result interfa
 	return nil
 }
 
+// Probe checks that Google's APIs can be reached, without spending quota
+func (c *GoogleClient) Probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, probeURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("API error (status %d)", resp.StatusCode)
+	}
+	return nil
+}
+
 // GetAPIKey returns the API key
 func (c *GoogleClient) GetAPIKey() string {
 	return c.apiKey
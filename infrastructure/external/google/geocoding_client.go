@@ -0,0 +1,117 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const geocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+// GeocodingClient handles Google Geocoding API
+type GeocodingClient struct {
+	*GoogleClient
+}
+
+// NewGeocodingClient creates a new Geocoding client
+func NewGeocodingClient(apiKey string) *GeocodingClient {
+	return &GeocodingClient{
+		GoogleClient: NewGoogleClient("google_geocoding", apiKey),
+	}
+}
+
+// GeocodeRequest represents forward geocoding parameters
+type GeocodeRequest struct {
+	Address  string
+	Language string
+	Region   string // e.g., "th" to bias results to Thailand
+}
+
+// ReverseGeocodeRequest represents reverse geocoding parameters
+type ReverseGeocodeRequest struct {
+	Lat      float64
+	Lng      float64
+	Language string
+}
+
+// GeocodeResponse represents Geocoding API response
+type GeocodeResponse struct {
+	Status       string          `json:"status"`
+	Results      []GeocodeResult `json:"results"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+}
+
+// GeocodeResult represents a single geocoding result
+type GeocodeResult struct {
+	PlaceID           string             `json:"place_id"`
+	FormattedAddress  string             `json:"formatted_address"`
+	AddressComponents []AddressComponent `json:"address_components"`
+	Geometry          Geometry           `json:"geometry"`
+	Types             []string           `json:"types"`
+}
+
+// AddressComponent is one part of a geocoded address
+type AddressComponent struct {
+	LongName  string   `json:"long_name"`
+	ShortName string   `json:"short_name"`
+	Types     []string `json:"types"`
+}
+
+// Component returns the long name of the first component with the given type
+func (r *GeocodeResult) Component(componentType string) string {
+	for _, c := range r.AddressComponents {
+		for _, t := range c.Types {
+			if t == componentType {
+				return c.LongName
+			}
+		}
+	}
+	return ""
+}
+
+// Geocode converts an address into coordinates
+func (c *GeocodingClient) Geocode(ctx context.Context, req *GeocodeRequest) (*GeocodeResponse, error) {
+	params := url.Values{}
+	params.Set("key", c.apiKey)
+	params.Set("address", req.Address)
+
+	if req.Language != "" {
+		params.Set("language", req.Language)
+	} else {
+		params.Set("language", "th")
+	}
+	if req.Region != "" {
+		params.Set("region", req.Region)
+	}
+
+	return c.doGeocodeRequest(ctx, fmt.Sprintf("%s?%s", geocodeURL, params.Encode()))
+}
+
+// ReverseGeocode converts coordinates into addresses, most specific first
+func (c *GeocodingClient) ReverseGeocode(ctx context.Context, req *ReverseGeocodeRequest) (*GeocodeResponse, error) {
+	params := url.Values{}
+	params.Set("key", c.apiKey)
+	params.Set("latlng", strconv.FormatFloat(req.Lat, 'f', -1, 64)+","+strconv.FormatFloat(req.Lng, 'f', -1, 64))
+
+	if req.Language != "" {
+		params.Set("language", req.Language)
+	} else {
+		params.Set("language", "th")
+	}
+
+	return c.doGeocodeRequest(ctx, fmt.Sprintf("%s?%s", geocodeURL, params.Encode()))
+}
+
+func (c *GeocodingClient) doGeocodeRequest(ctx context.Context, geocodeURL string) (*GeocodeResponse, error) {
+	var result GeocodeResponse
+	if err := c.doRequest(ctx, geocodeURL, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Status != "OK" && result.Status != "ZERO_RESULTS" {
+		return nil, fmt.Errorf("Geocoding API error: %s - %s", result.Status, result.ErrorMessage)
+	}
+
+	return &result, nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -24,27 +25,37 @@ type PlacesClient struct {
 // NewPlacesClient creates a new Places client
 func NewPlacesClient(apiKey string) *PlacesClient {
 	return &PlacesClient{
-		GoogleClient: NewGoogleClient(apiKey),
+		GoogleClient: NewGoogleClient("google_places", apiKey),
 	}
 }
 
 // NearbySearchRequest represents nearby search parameters
 type NearbySearchRequest struct {
-	Lat      float64
-	Lng      float64
-	Radius   int    // meters
-	Type     string // restaurant, tourist_attraction, etc.
-	Keyword  string
-	Language string
+	Lat       float64
+	Lng       float64
+	Radius    int    // meters
+	Type      string // restaurant, tourist_attraction, etc.
+	Keyword   string
+	Language  string
+	PageToken string // next_page_token from a previous response
 }
 
 // TextSearchRequest represents text search parameters
 type TextSearchRequest struct {
-	Query    string
-	Language string
-	Region   string // e.g., "th" for Thailand
+	Query     string
+	Language  string
+	Region    string // e.g., "th" for Thailand
+	PageToken string // next_page_token from a previous response
 }
 
+const (
+	// PlacesPageSize is the fixed number of results per Places API page
+	PlacesPageSize = 20
+	// pageTokenRetries bounds retries while a fresh next_page_token becomes valid
+	pageTokenRetries = 3
+	pageTokenDelay   = 2 * time.Second
+)
+
 // NearbySearchResponse represents Places API nearby search response
 type NearbySearchResponse struct {
 	Status           string   `json:"status"`
@@ -153,19 +164,13 @@ func (c *PlacesClient) TextSearch(ctx context.Context, req *TextSearchRequest) (
 	if req.Region != "" {
 		params.Set("region", req.Region)
 	}
-
-	searchURL := fmt.Sprintf("%s?%s", placesTextSearchURL, params.Encode())
-
-	var result NearbySearchResponse
-	if err := c.doRequest(ctx, searchURL, &result); err != nil {
-		return nil, err
+	if req.PageToken != "" {
+		params.Set("pagetoken", req.PageToken)
 	}
 
-	if result.Status != "OK" && result.Status != "ZERO_RESULTS" {
-		return nil, fmt.Errorf("Places API error: %s - %s", result.Status, result.ErrorMessage)
-	}
+	searchURL := fmt.Sprintf("%s?%s", placesTextSearchURL, params.Encode())
 
-	return &result, nil
+	return c.doSearchRequest(ctx, searchURL, req.PageToken != "")
 }
 
 // NearbySearch searches for places nearby
@@ -186,19 +191,39 @@ func (c *PlacesClient) NearbySearch(ctx context.Context, req *NearbySearchReques
 	} else {
 		params.Set("language", "th")
 	}
+	if req.PageToken != "" {
+		params.Set("pagetoken", req.PageToken)
+	}
 
 	searchURL := fmt.Sprintf("%s?%s", placesNearbyURL, params.Encode())
 
-	var result NearbySearchResponse
-	if err := c.doRequest(ctx, searchURL, &result); err != nil {
-		return nil, err
-	}
+	return c.doSearchRequest(ctx, searchURL, req.PageToken != "")
+}
 
-	if result.Status != "OK" && result.Status != "ZERO_RESULTS" {
-		return nil, fmt.Errorf("Places API error: %s - %s", result.Status, result.ErrorMessage)
+// doSearchRequest runs a text or nearby search. A next_page_token only becomes
+// valid a short time after it is issued, so paged requests retry INVALID_REQUEST.
+func (c *PlacesClient) doSearchRequest(ctx context.Context, searchURL string, paged bool) (*NearbySearchResponse, error) {
+	for attempt := 1; ; attempt++ {
+		var result NearbySearchResponse
+		if err := c.doRequest(ctx, searchURL, &result); err != nil {
+			return nil, err
+		}
+
+		if paged && result.Status == "INVALID_REQUEST" && attempt < pageTokenRetries {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(pageTokenDelay):
+			}
+			continue
+		}
+
+		if result.Status != "OK" && result.Status != "ZERO_RESULTS" {
+			return nil, fmt.Errorf("Places API error: %s - %s", result.Status, result.ErrorMessage)
+		}
+
+		return &result, nil
 	}
-
-	return &result, nil
 }
 
 // GetPlaceDetails gets detailed information about a place
@@ -23,7 +23,7 @@ type SearchClient struct {
 // NewSearchClient creates a new Search client
 func NewSearchClient(apiKey, searchEngineID string) *SearchClient {
 	return &SearchClient{
-		GoogleClient:   NewGoogleClient(apiKey),
+		GoogleClient:   NewGoogleClient("google_search", apiKey),
 		searchEngineID: searchEngineID,
 	}
 }
@@ -21,7 +21,7 @@ type TranslateClient struct {
 // NewTranslateClient creates a new Translate client
 func NewTranslateClient(apiKey string) *TranslateClient {
 	return &TranslateClient{
-		GoogleClient: NewGoogleClient(apiKey),
+		GoogleClient: NewGoogleClient("google_translate", apiKey),
 	}
 }
 
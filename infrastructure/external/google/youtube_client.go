@@ -21,7 +21,7 @@ type YouTubeClient struct {
 // NewYouTubeClient creates a new YouTube client
 func NewYouTubeClient(apiKey string) *YouTubeClient {
 	return &YouTubeClient{
-		GoogleClient: NewGoogleClient(apiKey),
+		GoogleClient: NewGoogleClient("youtube", apiKey),
 	}
 }
 
@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gofiber-template/domain/services"
+)
+
+// FakeProviderName is the provider name used in config to select the fake provider
+const FakeProviderName = "fake"
+
+// FakeProvider is a deterministic LLMProvider for tests and local development.
+// It answers with a canned response when the last user message contains a
+// registered key, otherwise it echoes the last user message.
+type FakeProvider struct {
+	mu        sync.RWMutex
+	responses map[string]string
+	requests  []services.LLMRequest
+}
+
+// Ensure FakeProvider implements services.LLMProvider
+var _ services.LLMProvider = (*FakeProvider)(nil)
+
+// NewFakeProvider creates a fake provider
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		responses: make(map[string]string),
+	}
+}
+
+// SetResponse registers a canned response for prompts containing match
+func (p *FakeProvider) SetResponse(match, response string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses[match] = response
+}
+
+// Requests returns every request received so far
+func (p *FakeProvider) Requests() []services.LLMRequest {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]services.LLMRequest(nil), p.requests...)
+}
+
+// Name returns the provider name
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+// Chat returns the deterministic answer for the request
+func (p *FakeProvider) Chat(ctx context.Context, req *services.LLMRequest) (*services.LLMResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.respond(req), nil
+}
+
+// ChatStream emits the deterministic answer word by word
+func (p *FakeProvider) ChatStream(ctx context.Context, req *services.LLMRequest, onDelta services.LLMStreamHandler) (*services.LLMResponse, error) {
+	full := p.respond(req)
+	result := *full
+	result.Content = ""
+
+	for _, delta := range splitKeepSpaces(full.Content) {
+		if err := ctx.Err(); err != nil {
+			return &result, err
+		}
+		result.Content += delta
+		if onDelta != nil {
+			if err := onDelta(delta); err != nil {
+				return &result, err
+			}
+		}
+	}
+
+	return full, nil
+}
+
+func (p *FakeProvider) respond(req *services.LLMRequest) *services.LLMResponse {
+	p.mu.Lock()
+	p.requests = append(p.requests, *req)
+	p.mu.Unlock()
+
+	var prompt string
+	promptWords := 0
+	for _, m := range req.Messages {
+		promptWords += len(strings.Fields(m.Content))
+		if m.Role == services.LLMRoleUser {
+			prompt = m.Content
+		}
+	}
+
+	content := p.match(prompt)
+	if content == "" {
+		if req.JSONMode {
+			content = "{}"
+		} else {
+			content = fmt.Sprintf("[fake] %s", prompt)
+		}
+	}
+
+	model := req.Model
+	if model == "" {
+		model = FakeProviderName
+	}
+
+	completionWords := len(strings.Fields(content))
+	return &services.LLMResponse{
+		Content:          content,
+		Model:            model,
+		FinishReason:     "stop",
+		PromptTokens:     promptWords,
+		CompletionTokens: completionWords,
+		TotalTokens:      promptWords + completionWords,
+	}
+}
+
+// match returns the canned response whose key is the longest match, for determinism
+func (p *FakeProvider) match(prompt string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	best := ""
+	response := ""
+	for key, value := range p.responses {
+		if strings.Contains(prompt, key) && len(key) > len(best) {
+			best = key
+			response = value
+		}
+	}
+	return response
+}
+
+// splitKeepSpaces splits text into words, keeping the trailing whitespace with each word
+func splitKeepSpaces(text string) []string {
+	var parts []string
+	start := 0
+	inSpace := false
+	for i, r := range text {
+		isSpace := r == ' ' || r == '\n' || r == '\t'
+		if inSpace && !isSpace {
+			parts = append(parts, text[start:i])
+			start = i
+		}
+		inSpace = isSpace
+	}
+	if start < len(text) {
+		parts = append(parts, text[start:])
+	}
+	return parts
+}
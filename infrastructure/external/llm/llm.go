@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"gofiber-template/domain/services"
+)
+
+// Registry holds the configured providers by name
+type Registry struct {
+	providers map[string]services.LLMProvider
+}
+
+// NewRegistry creates an empty provider registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]services.LLMProvider),
+	}
+}
+
+// Register adds a provider under its name
+func (r *Registry) Register(provider services.LLMProvider) {
+	r.providers[provider.Name()] = provider
+}
+
+// Get returns the provider with the given name
+func (r *Registry) Get(name string) (services.LLMProvider, bool) {
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// ForFeature returns the named provider bound to the given model.
+// An empty model keeps the provider default.
+func (r *Registry) ForFeature(providerName, model string) (services.LLMProvider, error) {
+	provider, ok := r.Get(providerName)
+	if !ok {
+		return nil, fmt.Errorf("unknown LLM provider: %s", providerName)
+	}
+	return WithModel(provider, model), nil
+}
+
+// modelProvider fills in a default model on every request
+type modelProvider struct {
+	services.LLMProvider
+	model string
+}
+
+// WithModel wraps a provider so requests without a model use the given one
+func WithModel(provider services.LLMProvider, model string) services.LLMProvider {
+	if model == "" {
+		return provider
+	}
+	return &modelProvider{LLMProvider: provider, model: model}
+}
+
+func (p *modelProvider) Chat(ctx context.Context, req *services.LLMRequest) (*services.LLMResponse, error) {
+	return p.LLMProvider.Chat(ctx, p.withModel(req))
+}
+
+func (p *modelProvider) ChatStream(ctx context.Context, req *services.LLMRequest, onDelta services.LLMStreamHandler) (*services.LLMResponse, error) {
+	return p.LLMProvider.ChatStream(ctx, p.withModel(req), onDelta)
+}
+
+func (p *modelProvider) withModel(req *services.LLMRequest) *services.LLMRequest {
+	if req.Model != "" {
+		return req
+	}
+	bound := *req
+	bound.Model = p.model
+	return &bound
+}
@@ -7,33 +7,86 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"gofiber-template/domain/services"
 	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/metrics"
+	"gofiber-template/pkg/tracing"
 )
 
 const (
-	openaiChatURL = "https://api.openai.com/v1/chat/completions"
+	// DefaultBaseURL is the OpenAI API base URL
+	DefaultBaseURL = "https://api.openai.com/v1"
+	// DefaultModel is used when no model is configured
+	DefaultModel = "gpt-4-turbo-preview"
 )
 
-// AIClient handles OpenAI API
+// Config configures an OpenAI-compatible endpoint.
+// Azure uses BaseURL https://{resource}.openai.azure.com/openai/deployments/{deployment}
+// with AuthHeader "api-key" and an APIVersion; vLLM and Ollama only need BaseURL.
+type Config struct {
+	Name       string // provider name, defaults to "openai"
+	APIKey     string
+	Model      string
+	BaseURL    string
+	AuthHeader string // "Authorization" (Bearer) by default
+	APIVersion string // appended as api-version query parameter when set
+
+	// StreamUsage asks for token usage at the end of streams
+	// (stream_options), which older Azure API versions reject
+	StreamUsage bool
+}
+
+// AIClient handles OpenAI-compatible chat completion APIs
 type AIClient struct {
-	apiKey     string
-	model      string
-	httpClient *http.Client
+	name        string
+	apiKey      string
+	model       string
+	baseURL     string
+	authHeader  string
+	apiVersion  string
+	streamUsage bool
+	httpClient  *http.Client
 }
 
+// Ensure AIClient implements services.LLMProvider
+var _ services.LLMProvider = (*AIClient)(nil)
+
 // NewAIClient creates a new OpenAI client
 func NewAIClient(apiKey, model string) *AIClient {
-	if model == "" {
-		model = "gpt-4-turbo-preview"
+	return NewCompatibleClient(Config{
+		APIKey: apiKey,
+		Model:  model,
+	})
+}
+
+// NewCompatibleClient creates a client for any OpenAI-compatible endpoint
+func NewCompatibleClient(cfg Config) *AIClient {
+	if cfg.Name == "" {
+		cfg.Name = "openai"
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.AuthHeader == "" {
+		cfg.AuthHeader = "Authorization"
 	}
 	return &AIClient{
-		apiKey: apiKey,
-		model:  model,
-		httpClient: &http.Client{
+		name:        cfg.Name,
+		apiKey:      cfg.APIKey,
+		model:       cfg.Model,
+		baseURL:     strings.TrimSuffix(cfg.BaseURL, "/"),
+		authHeader:  cfg.AuthHeader,
+		apiVersion:  cfg.APIVersion,
+		streamUsage: cfg.StreamUsage,
+		httpClient: tracing.NewHTTPClient(&http.Client{
 			Timeout: 60 * time.Second,
-		},
+		}),
 	}
 }
 
@@ -43,13 +96,25 @@ type ChatMessage struct {
 	Content string `json:"content"`
 }
 
+// ResponseFormat selects structured output (json_object)
+type ResponseFormat struct {
+	Type string `json:"type"`
+}
+
 // ChatRequest represents the request to OpenAI API
 type ChatRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	MaxTokens   int           `json:"max_tokens,omitempty"`
-	Temperature float64       `json:"temperature,omitempty"`
-	Stream      bool          `json:"stream,omitempty"`
+	Model          string          `json:"model"`
+	Messages       []ChatMessage   `json:"messages"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	Stream         bool            `json:"stream,omitempty"`
+	StreamOptions  *StreamOptions  `json:"stream_options,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// StreamOptions asks for a final chunk carrying the token usage
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // ChatResponse represents the response from OpenAI API
@@ -76,56 +141,42 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// Name returns the provider name
+func (c *AIClient) Name() string {
+	return c.name
+}
+
 // Chat sends a chat completion request
-func (c *AIClient) Chat(ctx context.Context, messages []ChatMessage, maxTokens int, temperature float64) (*ChatResponse, error) {
+func (c *AIClient) Chat(ctx context.Context, llmReq *services.LLMRequest) (*services.LLMResponse, error) {
 	startTime := time.Now()
 
-	if maxTokens == 0 {
-		maxTokens = 2000
-	}
-	if temperature == 0 {
-		temperature = 0.7
-	}
+	reqBody := c.buildRequest(llmReq, false)
 
 	logger.InfoContext(ctx, "OpenAI Chat request started",
-		"model", c.model,
-		"message_count", len(messages),
-		"max_tokens", maxTokens,
+		"provider", c.name,
+		"model", reqBody.Model,
+		"message_count", len(reqBody.Messages),
+		"max_tokens", reqBody.MaxTokens,
+		"json_mode", llmReq.JSONMode,
 	)
 
-	reqBody := ChatRequest{
-		Model:       c.model,
-		Messages:    messages,
-		MaxTokens:   maxTokens,
-		Temperature: temperature,
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		logger.ErrorContext(ctx, "OpenAI request marshal failed",
-			"error", err.Error(),
-		)
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", openaiChatURL, bytes.NewBuffer(jsonBody))
+	req, err := c.newRequest(ctx, reqBody)
 	if err != nil {
 		logger.ErrorContext(ctx, "OpenAI request creation failed",
 			"error", err.Error(),
 		)
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		logger.ErrorContext(ctx, "OpenAI HTTP request failed",
 			"error", err.Error(),
-			"model", c.model,
+			"provider", c.name,
+			"model", reqBody.Model,
 			"response_time_ms", time.Since(startTime).Milliseconds(),
 		)
+		metrics.ObserveExternalAPI(c.name, time.Since(startTime), metrics.ErrorTransport)
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
 	defer resp.Body.Close()
@@ -136,6 +187,7 @@ func (c *AIClient) Chat(ctx context.Context, messages []ChatMessage, maxTokens i
 			"error", err.Error(),
 			"status_code", resp.StatusCode,
 		)
+		metrics.ObserveExternalAPI(c.name, time.Since(startTime), metrics.ErrorTransport)
 		return nil, fmt.Errorf("read response: %w", err)
 	}
 
@@ -143,9 +195,11 @@ func (c *AIClient) Chat(ctx context.Context, messages []ChatMessage, maxTokens i
 		logger.ErrorContext(ctx, "OpenAI API error response",
 			"status_code", resp.StatusCode,
 			"response_body", string(body),
-			"model", c.model,
+			"provider", c.name,
+			"model", reqBody.Model,
 			"response_time_ms", time.Since(startTime).Milliseconds(),
 		)
+		metrics.ObserveExternalAPI(c.name, time.Since(startTime), metrics.ErrorStatus)
 		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
@@ -155,145 +209,128 @@ func (c *AIClient) Chat(ctx context.Context, messages []ChatMessage, maxTokens i
 			"error", err.Error(),
 			"response_body", string(body),
 		)
+		metrics.ObserveExternalAPI(c.name, time.Since(startTime), metrics.ErrorDecode)
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
+	if len(result.Choices) == 0 {
+		metrics.ObserveExternalAPI(c.name, time.Since(startTime), metrics.ErrorDecode)
+		return nil, fmt.Errorf("no response from %s", c.name)
+	}
+
+	metrics.ObserveExternalAPI(c.name, time.Since(startTime), "")
 	logger.InfoContext(ctx, "OpenAI Chat request completed",
-		"model", c.model,
+		"provider", c.name,
+		"model", result.Model,
 		"prompt_tokens", result.Usage.PromptTokens,
 		"completion_tokens", result.Usage.CompletionTokens,
 		"total_tokens", result.Usage.TotalTokens,
 		"response_time_ms", time.Since(startTime).Milliseconds(),
 	)
 
-	return &result, nil
+	return &services.LLMResponse{
+		Content:          result.Choices[0].Message.Content,
+		Model:            result.Model,
+		FinishReason:     result.Choices[0].FinishReason,
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+		TotalTokens:      result.Usage.TotalTokens,
+	}, nil
 }
 
-// SearchResultContext represents context from search results
-type SearchResultContext struct {
-	Title   string
-	Snippet string
-	URL     string
-}
-
-// GetSystemPromptByLang returns system prompt based on language
-func GetSystemPromptByLang(lang string) string {
-	if lang == "en" {
-		return `You are a travel information assistant for students at Sukhothai Thammathirat Open University (STOU).
-Summarize information from the provided sources concisely, clearly, and usefully.
-
-Rules for responding:
-1. Respond in English
-2. Format as Markdown
-3. Summarize with main headings and bullet points
-4. Cite the source of information
-5. If there is price information, opening hours, or important details, include them
-6. Suggest 2-3 relevant follow-up questions`
+// Probe checks that the endpoint is reachable and accepts the API key by
+// listing models, which costs no tokens
+func (c *AIClient) Probe(ctx context.Context) error {
+	url := c.baseURL + "/models"
+	if c.apiVersion != "" {
+		url += "?api-version=" + c.apiVersion
 	}
-	return `คุณเป็นผู้ช่วยค้นหาข้อมูลท่องเที่ยวสำหรับนักศึกษามหาวิทยาลัยสุโขทัยธรรมาธิราช (มสธ.)
-ให้สรุปข้อมูลจาก sources ที่ได้รับอย่างกระชับ ชัดเจน และเป็นประโยชน์
-
-กฎในการตอบ:
-1. ตอบเป็นภาษาไทย
-2. จัดรูปแบบเป็น Markdown
-3. สรุปเป็นหัวข้อหลักๆ พร้อม bullet points
-4. ระบุ source ที่มาของข้อมูล
-5. หากมีข้อมูลราคา เวลาเปิด-ปิด หรือข้อมูลสำคัญ ให้ระบุด้วย
-6. เสนอคำถาม follow-up ที่เกี่ยวข้อง 2-3 ข้อ`
-}
 
-// GetChatSystemPromptByLang returns chat system prompt based on language
-func GetChatSystemPromptByLang(lang string) string {
-	if lang == "en" {
-		return `You are a travel information assistant for STOU students.
-Answer travel-related questions in a friendly manner and provide useful information.
-Respond in English and use Markdown format.`
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
 	}
-	return `คุณเป็นผู้ช่วยค้นหาข้อมูลท่องเที่ยวสำหรับนักศึกษา มสธ.
-ตอบคำถามเกี่ยวกับการท่องเที่ยวอย่างเป็นมิตรและให้ข้อมูลที่เป็นประโยชน์
-ตอบเป็นภาษาไทยและใช้ Markdown format`
-}
+	c.setAuth(req)
 
-// GenerateTravelSummary generates a travel summary from search results
-func (c *AIClient) GenerateTravelSummary(ctx context.Context, query string, searchResults []SearchResultContext, lang string) (*ChatResponse, error) {
-	logger.InfoContext(ctx, "GenerateTravelSummary started",
-		"query", query,
-		"lang", lang,
-		"search_results_count", len(searchResults),
-	)
-
-	systemPrompt := GetSystemPromptByLang(lang)
-
-	// Build user prompt with search results
-	var userPrompt string
-	if lang == "en" {
-		userPrompt = fmt.Sprintf("Search query: %s\n\nInformation from various sources:\n", query)
-		for i, result := range searchResults {
-			userPrompt += fmt.Sprintf("\n[Source %d: %s]\n%s\nURL: %s\n",
-				i+1, result.Title, result.Snippet, result.URL)
-		}
-		userPrompt += "\nPlease summarize the above information systematically."
-	} else {
-		userPrompt = fmt.Sprintf("คำค้นหา: %s\n\nข้อมูลจากแหล่งต่างๆ:\n", query)
-		for i, result := range searchResults {
-			userPrompt += fmt.Sprintf("\n[Source %d: %s]\n%s\nURL: %s\n",
-				i+1, result.Title, result.Snippet, result.URL)
-		}
-		userPrompt += "\nกรุณาสรุปข้อมูลข้างต้นอย่างเป็นระบบ"
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
 	}
+	resp.Body.Close()
 
-	messages := []ChatMessage{
-		{Role: "system", Content: systemPrompt},
-		{Role: "user", Content: userPrompt},
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error (status %d)", resp.StatusCode)
 	}
+	return nil
+}
 
-	response, err := c.Chat(ctx, messages, 2000, 0.7)
-	if err != nil {
-		logger.ErrorContext(ctx, "GenerateTravelSummary failed",
-			"query", query,
-			"lang", lang,
-			"error", err.Error(),
-		)
-		return nil, err
+// buildRequest converts a provider-independent request into the OpenAI wire format
+func (c *AIClient) buildRequest(llmReq *services.LLMRequest, stream bool) ChatRequest {
+	model := llmReq.Model
+	if model == "" {
+		model = c.model
+	}
+	maxTokens := llmReq.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 2000
+	}
+	temperature := llmReq.Temperature
+	if temperature == 0 {
+		temperature = 0.7
 	}
 
-	logger.InfoContext(ctx, "GenerateTravelSummary completed",
-		"query", query,
-		"lang", lang,
-	)
+	messages := make([]ChatMessage, 0, len(llmReq.Messages))
+	for _, m := range llmReq.Messages {
+		messages = append(messages, ChatMessage{Role: m.Role, Content: m.Content})
+	}
 
-	return response, nil
+	reqBody := ChatRequest{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+		Stream:      stream,
+	}
+	if stream && c.streamUsage {
+		reqBody.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+	if llmReq.JSONMode {
+		reqBody.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+	return reqBody
 }
 
-// ContinueChat continues an existing chat conversation
-func (c *AIClient) ContinueChat(ctx context.Context, history []ChatMessage, newMessage string, lang string) (*ChatResponse, error) {
-	logger.InfoContext(ctx, "ContinueChat started",
-		"lang", lang,
-		"history_count", len(history),
-		"new_message_length", len(newMessage),
-	)
-
-	systemPrompt := GetChatSystemPromptByLang(lang)
+// newRequest builds the HTTP request for the chat completions endpoint
+func (c *AIClient) newRequest(ctx context.Context, reqBody ChatRequest) (*http.Request, error) {
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
 
-	messages := []ChatMessage{
-		{Role: "system", Content: systemPrompt},
+	url := c.baseURL + "/chat/completions"
+	if c.apiVersion != "" {
+		url += "?api-version=" + c.apiVersion
 	}
-	messages = append(messages, history...)
-	messages = append(messages, ChatMessage{Role: "user", Content: newMessage})
 
-	response, err := c.Chat(ctx, messages, 1500, 0.7)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		logger.ErrorContext(ctx, "ContinueChat failed",
-			"lang", lang,
-			"history_count", len(history),
-			"error", err.Error(),
-		)
-		return nil, err
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	logger.InfoContext(ctx, "ContinueChat completed",
-		"lang", lang,
-	)
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuth(req)
 
-	return response, nil
+	return req, nil
+}
+
+// setAuth adds the API key in the endpoint's auth header
+func (c *AIClient) setAuth(req *http.Request) {
+	if c.apiKey == "" {
+		return
+	}
+	if c.authHeader == "Authorization" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	} else {
+		req.Header.Set(c.authHeader, c.apiKey)
+	}
 }
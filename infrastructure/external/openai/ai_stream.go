@@ -0,0 +1,159 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+)
+
+// ChatStreamChunk represents one server-sent chunk of a streamed completion
+type ChatStreamChunk struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"`
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []StreamChoice `json:"choices"`
+	Usage   *Usage         `json:"usage,omitempty"`
+}
+
+// StreamChoice represents a single choice delta in a streamed chunk
+type StreamChoice struct {
+	Index        int         `json:"index"`
+	Delta        ChatMessage `json:"delta"`
+	FinishReason *string     `json:"finish_reason"`
+}
+
+// ChatStream sends a chat completion request with stream enabled.
+// Each content delta is passed to onDelta; the full content is returned once the stream ends.
+func (c *AIClient) ChatStream(ctx context.Context, llmReq *services.LLMRequest, onDelta services.LLMStreamHandler) (*services.LLMResponse, error) {
+	startTime := time.Now()
+
+	reqBody := c.buildRequest(llmReq, true)
+
+	logger.InfoContext(ctx, "OpenAI ChatStream request started",
+		"provider", c.name,
+		"model", reqBody.Model,
+		"message_count", len(reqBody.Messages),
+		"max_tokens", reqBody.MaxTokens,
+	)
+
+	req, err := c.newRequest(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The regular client has a hard timeout that would cut long streams short,
+	// so streaming relies on ctx for cancellation instead.
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		logger.ErrorContext(ctx, "OpenAI stream HTTP request failed",
+			"error", err.Error(),
+			"provider", c.name,
+			"model", reqBody.Model,
+			"response_time_ms", time.Since(startTime).Milliseconds(),
+		)
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		logger.ErrorContext(ctx, "OpenAI API error response",
+			"status_code", resp.StatusCode,
+			"response_body", string(body),
+			"provider", c.name,
+			"model", reqBody.Model,
+			"response_time_ms", time.Since(startTime).Milliseconds(),
+		)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var content strings.Builder
+	result := &services.LLMResponse{Model: reqBody.Model}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			logger.WarnContext(ctx, "OpenAI stream chunk parse failed",
+				"error", err.Error(),
+				"chunk", data,
+			)
+			continue
+		}
+
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			result.PromptTokens = chunk.Usage.PromptTokens
+			result.CompletionTokens = chunk.Usage.CompletionTokens
+			result.TotalTokens = chunk.Usage.TotalTokens
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != nil {
+				result.FinishReason = *choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				if err := onDelta(choice.Delta.Content); err != nil {
+					logger.WarnContext(ctx, "OpenAI stream stopped by consumer",
+						"error", err.Error(),
+						"content_length", content.Len(),
+						"response_time_ms", time.Since(startTime).Milliseconds(),
+					)
+					result.Content = content.String()
+					return result, err
+				}
+			}
+		}
+	}
+
+	result.Content = content.String()
+
+	if err := scanner.Err(); err != nil {
+		logger.ErrorContext(ctx, "OpenAI stream read failed",
+			"error", err.Error(),
+			"content_length", content.Len(),
+			"response_time_ms", time.Since(startTime).Milliseconds(),
+		)
+		return result, fmt.Errorf("read stream: %w", err)
+	}
+
+	logger.InfoContext(ctx, "OpenAI ChatStream request completed",
+		"provider", c.name,
+		"model", result.Model,
+		"prompt_tokens", result.PromptTokens,
+		"completion_tokens", result.CompletionTokens,
+		"content_length", content.Len(),
+		"response_time_ms", time.Since(startTime).Milliseconds(),
+	)
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type LogConfig struct {
+	Dir      string // .eml files are written here; empty only logs
+	From     string
+	FromName string
+}
+
+// LogMailer doesn't deliver anything. It logs each message and, when a
+// directory is set, saves it as an .eml file that mail clients can open.
+type LogMailer struct {
+	config LogConfig
+	from   mail.Address
+}
+
+func NewLogMailer(config LogConfig) Mailer {
+	return &LogMailer{
+		config: config,
+		from:   mail.Address{Name: config.FromName, Address: config.From},
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("📧 Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+
+	if m.config.Dir == "" {
+		return nil
+	}
+
+	body, err := buildMIME(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.config.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	return os.WriteFile(filepath.Join(m.config.Dir, name), body, 0o644)
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, s)
+}
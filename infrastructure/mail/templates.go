@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template names. Each has a <name>.<lang>.tmpl file defining "subject",
+// "text" and "html".
+const (
+	TemplateVerifyEmail   = "verify_email"
+	TemplateResetPassword = "reset_password"
+)
+
+// defaultLanguage is used when the user's language has no template
+const defaultLanguage = "th"
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// TemplateData is what the templates can use
+type TemplateData struct {
+	AppName   string
+	Name      string
+	Link      string
+	ExpiresIn string // already localized, see HumanDuration
+}
+
+type localizedTemplate struct {
+	text *texttemplate.Template // subject and plain-text body
+	html *htmltemplate.Template // HTML body, escaped
+}
+
+var templates = loadTemplates()
+
+func loadTemplates() map[string]localizedTemplate {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]localizedTemplate, len(files))
+	for _, f := range files {
+		path := "templates/" + f.Name()
+		key := strings.TrimSuffix(f.Name(), ".tmpl") // e.g. verify_email.th
+		loaded[key] = localizedTemplate{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFS, path)),
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFS, path)),
+		}
+	}
+	return loaded
+}
+
+// Render fills a template in the given language, falling back to Thai.
+// The caller sets the recipient.
+func Render(name, lang string, data TemplateData) (*Message, error) {
+	tmpl, ok := templates[name+"."+lang]
+	if !ok {
+		tmpl, ok = templates[name+"."+defaultLanguage]
+	}
+	if !ok {
+		return nil, fmt.Errorf("mail template %q not found", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
+
+// HumanDuration formats a link lifetime for the email body, e.g. "48 ชั่วโมง"
+// or "1 hour"
+func HumanDuration(d time.Duration, lang string) string {
+	value, unit := int(d.Minutes()), "minute"
+	if d >= time.Hour && d%time.Hour == 0 {
+		value, unit = int(d.Hours()), "hour"
+	}
+
+	if lang == "en" {
+		if value != 1 {
+			unit += "s"
+		}
+		return fmt.Sprintf("%d %s", value, unit)
+	}
+
+	if unit == "hour" {
+		return fmt.Sprintf("%d ชั่วโมง", value)
+	}
+	return fmt.Sprintf("%d นาที", value)
+}
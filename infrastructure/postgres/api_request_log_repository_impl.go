@@ -121,6 +121,30 @@ func (r *APIRequestLogRepositoryImpl) GetServiceCosts(ctx context.Context, start
 	return costs, err
 }
 
+func (r *APIRequestLogRepositoryImpl) GetLLMUsage(ctx context.Context, startDate, endDate time.Time) ([]models.LLMUsageStats, error) {
+	var stats []models.LLMUsageStats
+
+	err := r.db.WithContext(ctx).
+		Model(&models.APIRequestLog{}).
+		Select(`
+			endpoint as feature,
+			service_name as provider,
+			model,
+			COUNT(*) as total_requests,
+			COALESCE(SUM(prompt_tokens), 0) as prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) as completion_tokens,
+			COALESCE(SUM(estimated_cost), 0) as total_cost
+		`).
+		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("source = ?", "api").
+		Where("model <> ''").
+		Group("endpoint, service_name, model").
+		Order("total_cost DESC").
+		Scan(&stats).Error
+
+	return stats, err
+}
+
 func (r *APIRequestLogRepositoryImpl) GetTotalCost(ctx context.Context, startDate, endDate time.Time) (float64, error) {
 	var totalCost float64
 
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type DataExportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) repositories.DataExportRepository {
+	return &DataExportRepositoryImpl{db: db}
+}
+
+func (r *DataExportRepositoryImpl) Create(ctx context.Context, export *models.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *DataExportRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepositoryImpl) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.DataExport, error) {
+	var exports []*models.DataExport
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepositoryImpl) Update(ctx context.Context, export *models.DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
+
+func (r *DataExportRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.DataExport{}).Error
+}
+
+func (r *DataExportRepositoryImpl) ListExpired(ctx context.Context, before time.Time) ([]*models.DataExport, error) {
+	var exports []*models.DataExport
+	err := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepositoryImpl) FailStale(ctx context.Context, createdBefore, expiresAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.DataExport{}).
+		Where("status = ? AND created_at < ?", models.DataExportPending, createdBefore).
+		Updates(map[string]interface{}{
+			"status":     models.DataExportFailed,
+			"error":      "build did not finish",
+			"expires_at": expiresAt,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -1,11 +1,11 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
-	"gofiber-template/domain/models"
 )
 
 type DatabaseConfig struct {
@@ -31,19 +31,25 @@ func NewDatabase(config DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.User{},
-		&models.Task{},
-		&models.File{},
-		&models.Job{},
-		&models.Folder{},
-		&models.FolderItem{},
-		&models.Favorite{},
-		&models.SearchHistory{},
-		&models.AIChatSession{},
-		&models.AIChatMessage{},
-		&models.PlaceAIContent{},
-		&models.APIRequestLog{},
-	)
-}
\ No newline at end of file
+// Ping runs SELECT 1 and returns the schema version, the highest applied
+// migration, or "unversioned" before the first `migrate up`
+func Ping(ctx context.Context, db *gorm.DB) (string, error) {
+	var one int
+	if err := db.WithContext(ctx).Raw("SELECT 1").Scan(&one).Error; err != nil {
+		return "", fmt.Errorf("select 1: %w", err)
+	}
+
+	var tracked bool
+	if err := db.WithContext(ctx).Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&tracked).Error; err != nil {
+		return "", fmt.Errorf("read schema version: %w", err)
+	}
+	if !tracked {
+		return "unversioned", nil
+	}
+
+	var version string
+	if err := db.WithContext(ctx).Raw("SELECT COALESCE(MAX(version)::text, '') FROM schema_migrations").Scan(&version).Error; err != nil {
+		return "", fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type EmailTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewEmailTokenRepository(db *gorm.DB) repositories.EmailTokenRepository {
+	return &EmailTokenRepositoryImpl{db: db}
+}
+
+func (r *EmailTokenRepositoryImpl) Create(ctx context.Context, token *models.EmailToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only the latest link for a purpose works
+		err := tx.Model(&models.EmailToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *EmailTokenRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.EmailToken, error) {
+	var token models.EmailToken
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *EmailTokenRepositoryImpl) Consume(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.EmailToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -34,6 +34,7 @@ func (r *FavoriteRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*mo
 func (r *FavoriteRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*models.Favorite, error) {
 	var favorites []*models.Favorite
 	err := r.db.WithContext(ctx).
+		Preload("Place").
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Offset(offset).
@@ -45,6 +46,7 @@ func (r *FavoriteRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UU
 func (r *FavoriteRepositoryImpl) GetByUserIDAndType(ctx context.Context, userID uuid.UUID, favType string, offset, limit int) ([]*models.Favorite, error) {
 	var favorites []*models.Favorite
 	err := r.db.WithContext(ctx).
+		Preload("Place").
 		Where("user_id = ? AND type = ?", userID, favType).
 		Order("created_at DESC").
 		Offset(offset).
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"math"
+	"strings"
+
+	"gofiber-template/domain/repositories"
+)
+
+const (
+	geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+	// placeGeohashPrecision is the stored precision (~5 m cells)
+	placeGeohashPrecision = 9
+
+	// geohashMaxCoverCells bounds the number of LIKE prefixes per query
+	geohashMaxCoverCells = 16
+
+	metersPerDegreeLat = 111320.0
+)
+
+// encodeGeohash returns the geohash of a point at the given precision
+func encodeGeohash(lat, lng float64, precision int) string {
+	latMin, latMax := -90.0, 90.0
+	lngMin, lngMax := -180.0, 180.0
+
+	var sb strings.Builder
+	bit, ch, even := 0, 0, true
+	for sb.Len() < precision {
+		if even {
+			mid := (lngMin + lngMax) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				lngMin = mid
+			} else {
+				ch <<= 1
+				lngMax = mid
+			}
+		} else {
+			mid := (latMin + latMax) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latMin = mid
+			} else {
+				ch <<= 1
+				latMax = mid
+			}
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			sb.WriteByte(geohashBase32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// geohashCellSize returns a cell's height and width in degrees
+func geohashCellSize(precision int) (float64, float64) {
+	bits := precision * 5
+	latBits := bits / 2
+	lngBits := bits - latBits
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+// geohashCover returns the prefixes of the cells covering a box, at the finest
+// precision that needs no more than geohashMaxCoverCells cells
+func geohashCover(b repositories.GeoBounds) []string {
+	precision := placeGeohashPrecision
+	for ; precision > 1; precision-- {
+		h, w := geohashCellSize(precision)
+		rows := int(math.Floor((b.North+90)/h)-math.Floor((b.South+90)/h)) + 1
+		cols := int(math.Floor((b.East+180)/w)-math.Floor((b.West+180)/w)) + 1
+		if rows*cols <= geohashMaxCoverCells {
+			break
+		}
+	}
+
+	// Stepping by the cell size from the south-west corner visits every cell;
+	// the last step is clamped onto the north/east edge
+	h, w := geohashCellSize(precision)
+	seen := make(map[string]bool)
+	var cells []string
+	for lat := b.South; ; lat += h {
+		lat = math.Min(lat, b.North)
+		for lng := b.West; ; lng += w {
+			lng = math.Min(lng, b.East)
+			if hash := encodeGeohash(lat, lng, precision); !seen[hash] {
+				seen[hash] = true
+				cells = append(cells, hash)
+			}
+			if lng >= b.East {
+				break
+			}
+		}
+		if lat >= b.North {
+			break
+		}
+	}
+	return cells
+}
+
+// boundsAround returns the box enclosing a circle
+func boundsAround(lat, lng float64, radiusMeters float64) repositories.GeoBounds {
+	dLat := radiusMeters / metersPerDegreeLat
+	dLng := 180.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 0.000001 {
+		dLng = math.Min(radiusMeters/(metersPerDegreeLat*cos), 180)
+	}
+	return repositories.GeoBounds{
+		South: math.Max(lat-dLat, -90),
+		North: math.Min(lat+dLat, 90),
+		West:  math.Max(lng-dLng, -180),
+		East:  math.Min(lng+dLng, 180),
+	}
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type JobRunRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) repositories.JobRunRepository {
+	return &JobRunRepositoryImpl{db: db}
+}
+
+func (r *JobRunRepositoryImpl) Create(ctx context.Context, run *models.JobRun) error {
+	return r.db.WithContext(ctx).Omit("Job").Create(run).Error
+}
+
+func (r *JobRunRepositoryImpl) Update(ctx context.Context, run *models.JobRun) error {
+	return r.db.WithContext(ctx).Omit("Job").Save(run).Error
+}
+
+func (r *JobRunRepositoryImpl) ListByJobID(ctx context.Context, jobID uuid.UUID, offset, limit int) ([]*models.JobRun, error) {
+	var runs []*models.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Order("started_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+func (r *JobRunRepositoryImpl) CountByJobID(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.JobRun{}).Where("job_id = ?", jobID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS api_request_logs;
+DROP TABLE IF EXISTS place_ai_contents;
+DROP TABLE IF EXISTS ai_chat_messages;
+DROP TABLE IF EXISTS ai_chat_sessions;
+DROP TABLE IF EXISTS search_history;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS folder_items;
+DROP TABLE IF EXISTS folders;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS files;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,204 @@
+-- Schema as created by GORM AutoMigrate before the first feature migration.
+-- Everything is IF NOT EXISTS so databases set up by AutoMigrate adopt it as is.
+
+CREATE TABLE IF NOT EXISTS users (
+    id uuid DEFAULT gen_random_uuid(),
+    email text NOT NULL,
+    username text NOT NULL,
+    password text,
+    first_name text,
+    last_name text,
+    avatar varchar(500),
+    student_id varchar(11),
+    language varchar(5) DEFAULT 'th',
+    theme varchar(10) DEFAULT 'light',
+    role text DEFAULT 'user',
+    is_active boolean DEFAULT true,
+    google_id varchar(255),
+    line_id varchar(255),
+    auth_provider varchar(20) DEFAULT 'local',
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_student_id ON users (student_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_id ON users (google_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_line_id ON users (line_id);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id uuid DEFAULT gen_random_uuid(),
+    title text NOT NULL,
+    description text,
+    status text DEFAULT 'pending',
+    priority bigint DEFAULT 1,
+    due_date timestamptz,
+    user_id uuid NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_tasks_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS files (
+    id uuid DEFAULT gen_random_uuid(),
+    file_name text NOT NULL,
+    file_size bigint,
+    mime_type text,
+    url text NOT NULL,
+    cdn_path text,
+    user_id uuid NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_files_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id uuid DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    cron_expr text NOT NULL,
+    payload jsonb,
+    status text DEFAULT 'active',
+    last_run timestamptz,
+    next_run timestamptz,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS folders (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    name varchar(255) NOT NULL,
+    description text,
+    cover_image_url text,
+    is_public boolean DEFAULT false,
+    item_count bigint DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_folders_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_folders_user_id ON folders (user_id);
+
+CREATE TABLE IF NOT EXISTS folder_items (
+    id uuid DEFAULT gen_random_uuid(),
+    folder_id uuid NOT NULL,
+    type varchar(50) NOT NULL,
+    title varchar(255) NOT NULL,
+    url text NOT NULL,
+    thumbnail_url text,
+    description text,
+    metadata JSONB DEFAULT '{}',
+    sort_order bigint DEFAULT 0,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_folders_items FOREIGN KEY (folder_id) REFERENCES folders(id)
+);
+CREATE INDEX IF NOT EXISTS idx_folder_items_folder_id ON folder_items (folder_id);
+
+CREATE TABLE IF NOT EXISTS favorites (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    type varchar(50) NOT NULL,
+    external_id varchar(255),
+    title varchar(255) NOT NULL,
+    url text NOT NULL,
+    thumbnail_url text,
+    rating decimal(2,1),
+    review_count bigint DEFAULT 0,
+    address text,
+    metadata JSONB DEFAULT '{}',
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_favorites_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_favorites_external_id ON favorites (external_id);
+CREATE INDEX IF NOT EXISTS idx_favorites_user_id ON favorites (user_id);
+
+CREATE TABLE IF NOT EXISTS search_history (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    query varchar(500) NOT NULL,
+    search_type varchar(50) NOT NULL DEFAULT 'all',
+    result_count bigint DEFAULT 0,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_search_history_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_search_history_created_at ON search_history (created_at);
+CREATE INDEX IF NOT EXISTS idx_search_history_user_id ON search_history (user_id);
+
+CREATE TABLE IF NOT EXISTS ai_chat_sessions (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    title varchar(255),
+    initial_query varchar(500),
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_ai_chat_sessions_user FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX IF NOT EXISTS idx_ai_chat_sessions_user_id ON ai_chat_sessions (user_id);
+
+CREATE TABLE IF NOT EXISTS ai_chat_messages (
+    id uuid DEFAULT gen_random_uuid(),
+    session_id uuid NOT NULL,
+    role varchar(20) NOT NULL,
+    content text NOT NULL,
+    sources JSONB DEFAULT '[]',
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_ai_chat_sessions_messages FOREIGN KEY (session_id) REFERENCES ai_chat_sessions(id)
+);
+CREATE INDEX IF NOT EXISTS idx_ai_chat_messages_session_id ON ai_chat_messages (session_id);
+
+CREATE TABLE IF NOT EXISTS place_ai_contents (
+    id uuid DEFAULT gen_random_uuid(),
+    place_id varchar(255) NOT NULL,
+    place_name varchar(500) NOT NULL,
+    summary text,
+    history text,
+    highlights JSONB DEFAULT '[]',
+    best_time_to_visit text,
+    tips JSONB DEFAULT '[]',
+    quick_facts JSONB DEFAULT '[]',
+    talking_points JSONB DEFAULT '[]',
+    common_questions JSONB DEFAULT '[]',
+    related_videos JSONB DEFAULT '[]',
+    language varchar(10) DEFAULT 'th',
+    generated_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_place_ai_contents_expires_at ON place_ai_contents (expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_place_lang ON place_ai_contents (place_id,language);
+
+CREATE TABLE IF NOT EXISTS api_request_logs (
+    id uuid DEFAULT gen_random_uuid(),
+    service_name varchar(50) NOT NULL,
+    endpoint varchar(100) NOT NULL,
+    source varchar(20) NOT NULL,
+    cache_key varchar(255),
+    request_params text,
+    response_size bigint DEFAULT 0,
+    estimated_cost decimal(10,6) DEFAULT 0,
+    fields_used varchar(500),
+    user_id uuid,
+    ip_address varchar(45),
+    user_agent varchar(500),
+    success boolean DEFAULT true,
+    error_message text,
+    duration_ms bigint DEFAULT 0,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_api_request_logs_source ON api_request_logs (source);
+CREATE INDEX IF NOT EXISTS idx_api_request_logs_service_name ON api_request_logs (service_name);
+CREATE INDEX IF NOT EXISTS idx_api_request_logs_created_at ON api_request_logs (created_at);
+CREATE INDEX IF NOT EXISTS idx_api_request_logs_user_id ON api_request_logs (user_id);
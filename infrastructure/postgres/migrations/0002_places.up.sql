@@ -0,0 +1,24 @@
+-- Place catalogue: places fetched from Google, served while fresh.
+CREATE TABLE IF NOT EXISTS places (
+    place_id varchar(255),
+    name varchar(500) NOT NULL,
+    address text,
+    lat decimal NOT NULL,
+    lng decimal NOT NULL,
+    types JSONB DEFAULT '[]',
+    rating decimal(2,1),
+    review_count bigint DEFAULT 0,
+    price_level bigint DEFAULT 0,
+    phone varchar(50),
+    website text,
+    google_maps_url text,
+    photos JSONB DEFAULT '[]',
+    localized JSONB DEFAULT '{}',
+    last_fetched_at timestamptz NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (place_id)
+);
+CREATE INDEX IF NOT EXISTS idx_places_last_fetched_at ON places (last_fetched_at);
+CREATE INDEX IF NOT EXISTS idx_places_rating ON places (rating);
+CREATE INDEX IF NOT EXISTS idx_places_location ON places (lat,lng);
@@ -0,0 +1,5 @@
+-- The postgis extension is left installed; other schemas may use it.
+DROP INDEX IF EXISTS idx_places_location_gist;
+ALTER TABLE places DROP COLUMN IF EXISTS location;
+DROP INDEX IF EXISTS idx_places_geohash;
+ALTER TABLE places DROP COLUMN IF EXISTS geohash;
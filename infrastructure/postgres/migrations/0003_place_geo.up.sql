@@ -0,0 +1,62 @@
+-- Geo indexing for places. The geohash prefix index always exists so
+-- queries work without PostGIS; where the extension is available, a
+-- generated geography column with a GiST index serves radius and KNN queries.
+ALTER TABLE places ADD COLUMN IF NOT EXISTS geohash varchar(12);
+CREATE INDEX IF NOT EXISTS idx_places_geohash ON places (geohash varchar_pattern_ops);
+
+-- Geohashes of places stored before the column existed, at the precision
+-- the code stores (9 characters, ~5 m cells)
+DO $$
+DECLARE
+    base32 CONSTANT text := '0123456789bcdefghjkmnpqrstuvwxyz';
+    place record;
+    lat_min float8; lat_max float8; lng_min float8; lng_max float8; mid float8;
+    hash text; bits int; ch int; even boolean;
+BEGIN
+    FOR place IN
+        SELECT place_id, lat::float8 AS lat, lng::float8 AS lng
+        FROM places
+        WHERE geohash IS NULL OR geohash = ''
+    LOOP
+        lat_min := -90; lat_max := 90; lng_min := -180; lng_max := 180;
+        hash := ''; bits := 0; ch := 0; even := true;
+        WHILE length(hash) < 9 LOOP
+            IF even THEN
+                mid := (lng_min + lng_max) / 2;
+                IF place.lng >= mid THEN ch := ch * 2 + 1; lng_min := mid; ELSE ch := ch * 2; lng_max := mid; END IF;
+            ELSE
+                mid := (lat_min + lat_max) / 2;
+                IF place.lat >= mid THEN ch := ch * 2 + 1; lat_min := mid; ELSE ch := ch * 2; lat_max := mid; END IF;
+            END IF;
+            even := NOT even;
+            bits := bits + 1;
+            IF bits = 5 THEN
+                hash := hash || substr(base32, ch + 1, 1);
+                bits := 0;
+                ch := 0;
+            END IF;
+        END LOOP;
+        UPDATE places SET geohash = hash WHERE place_id = place.place_id;
+    END LOOP;
+END
+$$;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'postgis') THEN
+        RAISE NOTICE 'PostGIS not available, place geo queries use the geohash index';
+        RETURN;
+    END IF;
+
+    BEGIN
+        CREATE EXTENSION IF NOT EXISTS postgis;
+    EXCEPTION WHEN insufficient_privilege THEN
+        RAISE NOTICE 'Not allowed to create the PostGIS extension, place geo queries use the geohash index';
+        RETURN;
+    END;
+
+    EXECUTE 'ALTER TABLE places ADD COLUMN IF NOT EXISTS location geography(Point, 4326)
+             GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(lng, lat), 4326)::geography) STORED';
+    EXECUTE 'CREATE INDEX IF NOT EXISTS idx_places_location_gist ON places USING GIST (location)';
+END
+$$;
@@ -0,0 +1,17 @@
+-- Put one Google and one LINE identity per user back into the old columns.
+-- Further linked identities are lost.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_id varchar(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS line_id varchar(255);
+
+UPDATE users SET google_id = i.subject
+FROM user_identities i
+WHERE i.user_id = users.id AND i.provider = 'google';
+
+UPDATE users SET line_id = i.subject
+FROM user_identities i
+WHERE i.user_id = users.id AND i.provider = 'line';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_id ON users (google_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_line_id ON users (line_id);
+
+DROP TABLE IF EXISTS user_identities;
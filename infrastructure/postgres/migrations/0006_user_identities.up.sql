@@ -0,0 +1,41 @@
+-- Linked Google/LINE accounts, several per user.
+CREATE TABLE IF NOT EXISTS user_identities (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    provider varchar(20) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(255),
+    linked_at timestamptz NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_provider_subject ON user_identities (provider,subject);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_user_provider ON user_identities (user_id,provider);
+
+-- Copy users.google_id and users.line_id into user_identities and drop the
+-- old columns. Databases AutoMigrate already moved have neither column.
+DO $$
+DECLARE
+    legacy record;
+BEGIN
+    FOR legacy IN
+        SELECT column_name, provider
+        FROM (VALUES ('google_id', 'google'), ('line_id', 'line')) AS l (column_name, provider)
+        WHERE EXISTS (
+            SELECT 1 FROM information_schema.columns
+            WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = l.column_name
+        )
+    LOOP
+        EXECUTE format(
+            'INSERT INTO user_identities (id, user_id, provider, subject, email, linked_at, created_at, updated_at)
+             SELECT gen_random_uuid(), id, %L, %I, email, created_at, NOW(), NOW()
+             FROM users
+             WHERE %I IS NOT NULL AND %I <> ''''
+             ON CONFLICT DO NOTHING',
+            legacy.provider, legacy.column_name, legacy.column_name, legacy.column_name);
+        EXECUTE format('ALTER TABLE users DROP COLUMN %I', legacy.column_name);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,14 @@
+-- TOTP two-factor authentication.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret varchar(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_user_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes (user_id);
@@ -0,0 +1,15 @@
+-- Database-backed role permissions. The rows are seeded at startup.
+CREATE TABLE IF NOT EXISTS permissions (
+    name varchar(100),
+    description varchar(255),
+    created_at timestamptz,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role varchar(50),
+    permission varchar(100),
+    created_at timestamptz,
+    PRIMARY KEY (role,permission),
+    CONSTRAINT fk_role_permissions_permission_ref FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+);
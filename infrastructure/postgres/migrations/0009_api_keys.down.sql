@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_api_request_logs_api_key_id;
+ALTER TABLE api_request_logs DROP COLUMN IF EXISTS api_key_id;
+DROP TABLE IF EXISTS api_keys;
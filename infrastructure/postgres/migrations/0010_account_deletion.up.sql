@@ -0,0 +1,21 @@
+-- Personal data exports and scheduled account deletion.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    status varchar(20) NOT NULL,
+    object_key varchar(255),
+    size bigint DEFAULT 0,
+    error text,
+    completed_at timestamptz,
+    expires_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_data_exports_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports (expires_at);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports (status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_api_request_logs_model;
+ALTER TABLE api_request_logs DROP COLUMN IF EXISTS completion_tokens;
+ALTER TABLE api_request_logs DROP COLUMN IF EXISTS prompt_tokens;
+ALTER TABLE api_request_logs DROP COLUMN IF EXISTS model;
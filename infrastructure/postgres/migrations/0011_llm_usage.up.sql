@@ -0,0 +1,5 @@
+-- Model and token counts of logged LLM calls.
+ALTER TABLE api_request_logs ADD COLUMN IF NOT EXISTS model varchar(100);
+ALTER TABLE api_request_logs ADD COLUMN IF NOT EXISTS prompt_tokens bigint DEFAULT 0;
+ALTER TABLE api_request_logs ADD COLUMN IF NOT EXISTS completion_tokens bigint DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_api_request_logs_model ON api_request_logs (model);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_places_types;
+DROP INDEX IF EXISTS idx_places_localized_trgm;
+DROP INDEX IF EXISTS idx_places_address_trgm;
+DROP INDEX IF EXISTS idx_places_name_trgm;
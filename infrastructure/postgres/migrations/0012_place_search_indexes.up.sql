@@ -0,0 +1,24 @@
+-- Trigram indexes for the ILIKE '%...%' address filter of the place
+-- catalogue, and a GIN index for its types @> filter. Without pg_trgm the
+-- filter still works, with a sequential scan.
+CREATE INDEX IF NOT EXISTS idx_places_types ON places USING GIN (types jsonb_path_ops);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'pg_trgm') THEN
+        RAISE NOTICE 'pg_trgm not available, place text filters are not indexed';
+        RETURN;
+    END IF;
+
+    BEGIN
+        CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    EXCEPTION WHEN insufficient_privilege THEN
+        RAISE NOTICE 'Not allowed to create the pg_trgm extension, place text filters are not indexed';
+        RETURN;
+    END;
+
+    EXECUTE 'CREATE INDEX IF NOT EXISTS idx_places_name_trgm ON places USING GIN (name gin_trgm_ops)';
+    EXECUTE 'CREATE INDEX IF NOT EXISTS idx_places_address_trgm ON places USING GIN (address gin_trgm_ops)';
+    EXECUTE 'CREATE INDEX IF NOT EXISTS idx_places_localized_trgm ON places USING GIN ((localized::text) gin_trgm_ops)';
+END
+$$;
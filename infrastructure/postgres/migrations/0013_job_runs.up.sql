@@ -0,0 +1,22 @@
+-- Jobs name the handler that runs them, and every run is recorded.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS handler varchar(50) NOT NULL DEFAULT '';
+
+-- Handlers decode the payload, which can't be NULL for the string field
+UPDATE jobs SET payload = '{}' WHERE payload IS NULL;
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id uuid DEFAULT gen_random_uuid(),
+    job_id uuid NOT NULL,
+    handler varchar(50) NOT NULL,
+    trigger varchar(20) NOT NULL,
+    status varchar(20) NOT NULL,
+    started_at timestamptz NOT NULL,
+    finished_at timestamptz,
+    duration_ms bigint,
+    output text,
+    error text,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_job_runs_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started ON job_runs (job_id, started_at DESC);
@@ -0,0 +1,4 @@
+-- An account may link several Google/LINE accounts of the same provider; a
+-- provider account still belongs to one user through (provider, subject).
+DROP INDEX IF EXISTS idx_user_identities_user_provider;
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
@@ -0,0 +1,11 @@
+// Package migrations holds the versioned SQL migrations of the database.
+// Each version has a NNNN_name.up.sql and a NNNN_name.down.sql file; they
+// are embedded in the binary and applied with `api migrate`.
+package migrations
+
+import "embed"
+
+// Files are the migration files, embedded at build time
+//
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gofiber-template/infrastructure/postgres/migrations"
+)
+
+func migrationFiles(names ...string) fstest.MapFS {
+	files := fstest.MapFS{}
+	for _, name := range names {
+		files[name] = &fstest.MapFile{Data: []byte("-- " + name)}
+	}
+	return files
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int64
+		wantErr string
+	}{
+		{
+			name: "ordered by version, not by name",
+			files: migrationFiles(
+				"0010_tenth.up.sql", "0010_tenth.down.sql",
+				"0002_second.up.sql", "0002_second.down.sql",
+				"0001_first.up.sql", "0001_first.down.sql",
+			),
+			want: []int64{1, 2, 10},
+		},
+		{
+			name: "versions without zero padding sort numerically",
+			files: migrationFiles(
+				"9_nine.up.sql", "9_nine.down.sql",
+				"10_ten.up.sql", "10_ten.down.sql",
+			),
+			want: []int64{9, 10},
+		},
+		{
+			name: "other files are ignored",
+			files: migrationFiles(
+				"0001_first.up.sql", "0001_first.down.sql",
+				"README.md", "migrations.go", "0002_Bad-Name.up.sql", "0003_no_direction.sql",
+			),
+			want: []int64{1},
+		},
+		{
+			name:  "empty directory",
+			files: fstest.MapFS{},
+			want:  []int64{},
+		},
+		{
+			name:    "missing down script",
+			files:   migrationFiles("0001_first.up.sql"),
+			wantErr: "needs both an up and a down script",
+		},
+		{
+			name:    "missing up script",
+			files:   migrationFiles("0001_first.down.sql"),
+			wantErr: "needs both an up and a down script",
+		},
+		{
+			name: "one version with two names",
+			files: migrationFiles(
+				"0001_first.up.sql", "0001_first.down.sql",
+				"0001_other.up.sql", "0001_other.down.sql",
+			),
+			wantErr: "migration 1 has two names",
+		},
+		{
+			name: "one version written two ways",
+			files: migrationFiles(
+				"0001_first.up.sql", "0001_first.down.sql",
+				"1_first.up.sql",
+			),
+			wantErr: "migration 1 has two up scripts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadMigrations() error = %v", err)
+			}
+
+			versions := make([]int64, 0, len(got))
+			for _, m := range got {
+				versions = append(versions, m.Version)
+				if m.Up == "" || m.Down == "" {
+					t.Errorf("migration %d is missing a script", m.Version)
+				}
+			}
+			if !reflect.DeepEqual(versions, tt.want) {
+				t.Errorf("versions = %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsPairsScripts(t *testing.T) {
+	got, err := loadMigrations(fstest.MapFS{
+		"0001_users.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+		"0001_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	})
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	want := []Migration{{Version: 1, Name: "users", Up: "CREATE TABLE users ();", Down: "DROP TABLE users;"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadMigrations() = %+v, want %+v", got, want)
+	}
+}
+
+// The shipped migrations must load and leave no gaps in their numbering
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := loadMigrations(migrations.Files)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s at position %d, want version %d", m.Version, m.Name, i, i+1)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type PermissionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) repositories.PermissionRepository {
+	return &PermissionRepositoryImpl{db: db}
+}
+
+func (r *PermissionRepositoryImpl) ListPermissions(ctx context.Context) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	err := r.db.WithContext(ctx).Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *PermissionRepositoryImpl) ListRolePermissions(ctx context.Context) ([]*models.RolePermission, error) {
+	var grants []*models.RolePermission
+	err := r.db.WithContext(ctx).Order("role, permission").Find(&grants).Error
+	return grants, err
+}
+
+func (r *PermissionRepositoryImpl) Grant(ctx context.Context, role, permission string) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.RolePermission{Role: role, Permission: permission, CreatedAt: time.Now()}).Error
+}
+
+func (r *PermissionRepositoryImpl) Revoke(ctx context.Context, role, permission string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("role = ? AND permission = ?", role, permission).
+		Delete(&models.RolePermission{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gofiber-template/domain/models"
+)
+
+// SeedPermissions adds permissions the code knows about but the database
+// doesn't yet, granting each to its default roles. Existing permissions are
+// skipped, so grants revoked through the admin API stay revoked. It runs at
+// every startup, after the schema is migrated.
+func SeedPermissions(db *gorm.DB) error {
+	for _, def := range models.DefaultPermissions {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Permission{
+				Name:        def.Name,
+				Description: def.Description,
+				CreatedAt:   time.Now(),
+			})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+
+			for _, role := range def.Roles {
+				grant := &models.RolePermission{Role: role, Permission: def.Name, CreatedAt: time.Now()}
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(grant).Error; err != nil {
+					return err
+				}
+			}
+			log.Printf("✓ Added permission %s for %v", def.Name, def.Roles)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed permission %s: %v", def.Name, err)
+		}
+	}
+	return nil
+}
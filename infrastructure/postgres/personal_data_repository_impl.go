@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+// personalDataQuery selects one kind of a user's data for exports
+type personalDataQuery struct {
+	name  string   // file name in the export, without .json
+	query string   // ? is the user ID
+	omit  []string // secrets that are never exported
+}
+
+// personalDataQueries lists every table that holds personal data. A new
+// user-owned table belongs here and in purgedTables.
+var personalDataQueries = []personalDataQuery{
+	{name: "profile", query: "SELECT * FROM users WHERE id = ?", omit: []string{"password", "totp_secret"}},
+	{name: "identities", query: "SELECT * FROM user_identities WHERE user_id = ? ORDER BY linked_at"},
+	{name: "sessions", query: "SELECT * FROM user_sessions WHERE user_id = ? ORDER BY created_at"},
+	{name: "favorites", query: "SELECT * FROM favorites WHERE user_id = ? ORDER BY created_at"},
+	{name: "folders", query: "SELECT * FROM folders WHERE user_id = ? ORDER BY created_at"},
+	{name: "folder_items", query: `
+		SELECT i.* FROM folder_items i
+		JOIN folders f ON f.id = i.folder_id
+		WHERE f.user_id = ?
+		ORDER BY i.folder_id, i.sort_order`},
+	{name: "search_history", query: "SELECT * FROM search_history WHERE user_id = ? ORDER BY created_at"},
+	{name: "ai_chat_sessions", query: "SELECT * FROM ai_chat_sessions WHERE user_id = ? ORDER BY created_at"},
+	{name: "ai_chat_messages", query: `
+		SELECT m.* FROM ai_chat_messages m
+		JOIN ai_chat_sessions s ON s.id = m.session_id
+		WHERE s.user_id = ?
+		ORDER BY m.session_id, m.created_at`},
+	{name: "files", query: "SELECT * FROM files WHERE user_id = ? ORDER BY created_at"},
+	{name: "tasks", query: "SELECT * FROM tasks WHERE user_id = ? ORDER BY created_at"},
+	{name: "api_keys", query: "SELECT * FROM api_keys WHERE user_id = ? ORDER BY created_at", omit: []string{"key_hash"}},
+	{name: "api_request_logs", query: "SELECT * FROM api_request_logs WHERE user_id = ? ORDER BY created_at"},
+}
+
+// purgedTables are emptied of the user's rows before the user is deleted.
+// Children come before their parents: folder items and chat messages are
+// deleted through their folder and session first.
+var purgedTables = []string{
+	models.RefreshToken{}.TableName(),
+	models.UserSession{}.TableName(),
+	models.EmailToken{}.TableName(),
+	models.RecoveryCode{}.TableName(),
+	models.UserIdentity{}.TableName(),
+	models.APIKey{}.TableName(),
+	models.DataExport{}.TableName(),
+	models.Favorite{}.TableName(),
+	models.Folder{}.TableName(),
+	models.SearchHistory{}.TableName(),
+	models.AIChatSession{}.TableName(),
+	models.File{}.TableName(),
+	models.Task{}.TableName(),
+}
+
+type PersonalDataRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPersonalDataRepository(db *gorm.DB) repositories.PersonalDataRepository {
+	return &PersonalDataRepositoryImpl{db: db}
+}
+
+func (r *PersonalDataRepositoryImpl) Export(ctx context.Context, userID uuid.UUID) ([]models.PersonalDataSet, error) {
+	sets := make([]models.PersonalDataSet, 0, len(personalDataQueries))
+	for _, q := range personalDataQueries {
+		var rows []map[string]interface{}
+		if err := r.db.WithContext(ctx).Raw(q.query, userID).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			for _, column := range q.omit {
+				delete(row, column)
+			}
+			for column, value := range row {
+				// jsonb columns come back as raw bytes
+				if b, ok := value.([]byte); ok {
+					if json.Valid(b) {
+						row[column] = json.RawMessage(b)
+					} else {
+						row[column] = string(b)
+					}
+				}
+			}
+		}
+		if rows == nil {
+			rows = []map[string]interface{}{}
+		}
+
+		sets = append(sets, models.PersonalDataSet{Name: q.name, Rows: rows})
+	}
+	return sets, nil
+}
+
+func (r *PersonalDataRepositoryImpl) ObjectKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT cdn_path FROM files WHERE user_id = ? AND cdn_path <> ''
+		UNION
+		SELECT object_key FROM data_exports WHERE user_id = ? AND object_key <> ''`,
+		userID, userID).Scan(&keys).Error
+	return keys, err
+}
+
+func (r *PersonalDataRepositoryImpl) FolderItemURLs(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var urls []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT i.url FROM folder_items i
+		JOIN folders f ON f.id = i.folder_id
+		WHERE f.user_id = ? AND i.url <> ''`,
+		userID).Scan(&urls).Error
+	return urls, err
+}
+
+func (r *PersonalDataRepositoryImpl) Purge(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM folder_items WHERE folder_id IN (SELECT id FROM folders WHERE user_id = ?)`, userID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`DELETE FROM ai_chat_messages WHERE session_id IN (SELECT id FROM ai_chat_sessions WHERE user_id = ?)`, userID).Error
+		if err != nil {
+			return err
+		}
+
+		// Keep the cost statistics, drop everything that points to the person
+		err = tx.Exec(`
+			UPDATE api_request_logs
+			SET user_id = NULL, api_key_id = NULL, ip_address = '', user_agent = '', request_params = ''
+			WHERE user_id = ?`, userID).Error
+		if err != nil {
+			return err
+		}
+
+		for _, table := range purgedTables {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", userID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec("DELETE FROM users WHERE id = ?", userID).Error
+	})
+}
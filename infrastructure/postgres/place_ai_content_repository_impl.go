@@ -69,6 +69,16 @@ func (r *PlaceAIContentRepositoryImpl) DeleteByPlaceID(ctx context.Context, plac
 	return r.db.WithContext(ctx).Where("place_id = ?", placeID).Delete(&models.PlaceAIContent{}).Error
 }
 
+func (r *PlaceAIContentRepositoryImpl) ListExpiring(ctx context.Context, before time.Time, limit int) ([]*models.PlaceAIContent, error) {
+	var contents []*models.PlaceAIContent
+	err := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&contents).Error
+	return contents, err
+}
+
 func (r *PlaceAIContentRepositoryImpl) DeleteExpired(ctx context.Context) (int64, error) {
 	result := r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.PlaceAIContent{})
 	return result.RowsAffected, result.Error
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+// haversineSQL is the great-circle distance in meters from (?, ?) to a row;
+// bind lat, lat, lng
+const haversineSQL = "6371000 * 2 * ASIN(SQRT(POWER(SIN(RADIANS(lat - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(lat)) * POWER(SIN(RADIANS(lng - ?) / 2), 2)))"
+
+// pointSQL is a PostGIS geography point; bind lng, lat
+const pointSQL = "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography"
+
+// knnStartRadius and knnMaxRadius bound the expanding search used for
+// k-nearest queries on the geohash fallback
+const (
+	knnStartRadius = 1000
+	knnMaxRadius   = 200000
+)
+
+// hasPlaceLocation reports whether the PostGIS location column exists, which
+// migration 0003 adds only where the extension is available
+func hasPlaceLocation(db *gorm.DB) bool {
+	return db.Migrator().HasColumn(&models.Place{}, "location")
+}
+
+func (r *PlaceRepositoryImpl) FindWithinRadius(ctx context.Context, lat, lng float64, radius int, filter repositories.PlaceFilter, offset, limit int) ([]*models.Place, error) {
+	var places []*models.Place
+	err := r.withinRadius(r.db.WithContext(ctx), lat, lng, radius, filter).
+		Clauses(r.distanceOrder(lat, lng)).
+		Offset(offset).
+		Limit(limit).
+		Find(&places).Error
+	return places, err
+}
+
+func (r *PlaceRepositoryImpl) CountWithinRadius(ctx context.Context, lat, lng float64, radius int, filter repositories.PlaceFilter) (int64, error) {
+	var count int64
+	err := r.withinRadius(r.db.WithContext(ctx).Model(&models.Place{}), lat, lng, radius, filter).Count(&count).Error
+	return count, err
+}
+
+func (r *PlaceRepositoryImpl) FindInBounds(ctx context.Context, bounds repositories.GeoBounds, filter repositories.PlaceFilter, limit int) ([]*models.Place, error) {
+	db := r.applyFilter(r.db.WithContext(ctx), filter)
+	if r.postgis {
+		db = db.Where("location && ST_MakeEnvelope(?, ?, ?, ?, 4326)::geography", bounds.West, bounds.South, bounds.East, bounds.North)
+	} else {
+		db = r.inGeohashCells(db, bounds)
+	}
+
+	var places []*models.Place
+	err := db.
+		Where("lat BETWEEN ? AND ? AND lng BETWEEN ? AND ?", bounds.South, bounds.North, bounds.West, bounds.East).
+		Order("rating DESC").
+		Order("review_count DESC").
+		Limit(limit).
+		Find(&places).Error
+	return places, err
+}
+
+func (r *PlaceRepositoryImpl) FindNearest(ctx context.Context, lat, lng float64, k int, filter repositories.PlaceFilter) ([]*models.Place, error) {
+	var places []*models.Place
+
+	if r.postgis {
+		// The GiST index answers <-> ordering directly
+		err := r.applyFilter(r.db.WithContext(ctx), filter).
+			Clauses(r.distanceOrder(lat, lng)).
+			Limit(k).
+			Find(&places).Error
+		return places, err
+	}
+
+	// Without PostGIS, widen the radius until k places are inside it
+	for radius := knnStartRadius; ; radius *= 4 {
+		if radius > knnMaxRadius {
+			radius = knnMaxRadius
+		}
+		places = places[:0]
+		err := r.withinRadius(r.db.WithContext(ctx), lat, lng, radius, filter).
+			Clauses(r.distanceOrder(lat, lng)).
+			Limit(k).
+			Find(&places).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(places) >= k || radius == knnMaxRadius {
+			return places, nil
+		}
+	}
+}
+
+// withinRadius restricts db to places within radius meters of a point
+func (r *PlaceRepositoryImpl) withinRadius(db *gorm.DB, lat, lng float64, radius int, filter repositories.PlaceFilter) *gorm.DB {
+	db = r.applyFilter(db, filter)
+	if r.postgis {
+		return db.Where("ST_DWithin(location, "+pointSQL+", ?)", lng, lat, radius)
+	}
+	return r.inGeohashCells(db, boundsAround(lat, lng, float64(radius))).
+		Where(haversineSQL+" <= ?", lat, lat, lng, radius)
+}
+
+// inGeohashCells restricts db to the geohash cells covering a box, which the
+// varchar_pattern_ops index answers as prefix scans
+func (r *PlaceRepositoryImpl) inGeohashCells(db *gorm.DB, bounds repositories.GeoBounds) *gorm.DB {
+	cells := geohashCover(bounds)
+	conds := make([]string, 0, len(cells))
+	args := make([]interface{}, 0, len(cells))
+	for _, cell := range cells {
+		conds = append(conds, "geohash LIKE ?")
+		args = append(args, cell+"%")
+	}
+	return db.Where("("+strings.Join(conds, " OR ")+")", args...)
+}
+
+// distanceOrder orders rows nearest first
+func (r *PlaceRepositoryImpl) distanceOrder(lat, lng float64) clause.OrderBy {
+	if r.postgis {
+		return clause.OrderBy{Expression: clause.Expr{SQL: "location <-> " + pointSQL, Vars: []interface{}{lng, lat}, WithoutParentheses: true}}
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: haversineSQL, Vars: []interface{}{lat, lat, lng}, WithoutParentheses: true}}
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type PlaceRepositoryImpl struct {
+	db      *gorm.DB
+	postgis bool // geo queries use the location column instead of geohash cells
+}
+
+func NewPlaceRepository(db *gorm.DB) repositories.PlaceRepository {
+	return &PlaceRepositoryImpl{db: db, postgis: hasPlaceLocation(db)}
+}
+
+// Columns refreshed by every search result; phone, website, maps URL and
+// per-language details only come from Place Details
+var placeSearchColumns = []string{
+	"name", "address", "lat", "lng", "geohash", "types", "rating", "review_count", "price_level", "last_fetched_at", "updated_at",
+}
+
+var placeDetailsColumns = append([]string{"phone", "website", "google_maps_url", "photos"}, placeSearchColumns...)
+
+func (r *PlaceRepositoryImpl) UpsertDetails(ctx context.Context, place *models.Place, lang string) error {
+	// Details replace the whole language entry so removed reviews or hours disappear
+	localized := clause.Assignment{
+		Column: clause.Column{Name: "localized"},
+		Value: gorm.Expr(
+			"jsonb_set(COALESCE(places.localized, '{}'::jsonb), ARRAY[?]::text[], excluded.localized -> ?)",
+			lang, lang,
+		),
+	}
+	place.Geohash = encodeGeohash(place.Lat, place.Lng, placeGeohashPrecision)
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "place_id"}},
+			DoUpdates: append(clause.AssignmentColumns(placeDetailsColumns), localized),
+		}).
+		Create(place).Error
+}
+
+func (r *PlaceRepositoryImpl) UpsertSearchResults(ctx context.Context, places []*models.Place, lang string) error {
+	// A single INSERT ... ON CONFLICT cannot touch the same row twice
+	seen := make(map[string]bool, len(places))
+	unique := make([]*models.Place, 0, len(places))
+	for _, p := range places {
+		if p.PlaceID == "" || seen[p.PlaceID] {
+			continue
+		}
+		seen[p.PlaceID] = true
+		p.Geohash = encodeGeohash(p.Lat, p.Lng, placeGeohashPrecision)
+		unique = append(unique, p)
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	// Search results merge name/address into the language entry and keep
+	// stored photos, which come from Place Details and are more complete
+	updates := append(clause.AssignmentColumns(placeSearchColumns),
+		clause.Assignment{
+			Column: clause.Column{Name: "localized"},
+			Value: gorm.Expr(
+				"jsonb_set(COALESCE(places.localized, '{}'::jsonb), ARRAY[?]::text[], COALESCE(places.localized -> ?, '{}'::jsonb) || (excluded.localized -> ?))",
+				lang, lang, lang,
+			),
+		},
+		clause.Assignment{
+			Column: clause.Column{Name: "photos"},
+			Value:  gorm.Expr("CASE WHEN jsonb_array_length(COALESCE(places.photos, '[]'::jsonb)) > 0 THEN places.photos ELSE excluded.photos END"),
+		},
+	)
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "place_id"}},
+			DoUpdates: updates,
+		}).
+		Create(&unique).Error
+}
+
+func (r *PlaceRepositoryImpl) GetByPlaceID(ctx context.Context, placeID string) (*models.Place, error) {
+	var place models.Place
+	err := r.db.WithContext(ctx).Where("place_id = ?", placeID).First(&place).Error
+	if err != nil {
+		return nil, err
+	}
+	return &place, nil
+}
+
+func (r *PlaceRepositoryImpl) GetByPlaceIDs(ctx context.Context, placeIDs []string) ([]*models.Place, error) {
+	var places []*models.Place
+	if len(placeIDs) == 0 {
+		return places, nil
+	}
+	err := r.db.WithContext(ctx).Where("place_id IN ?", placeIDs).Find(&places).Error
+	return places, err
+}
+
+func (r *PlaceRepositoryImpl) Find(ctx context.Context, filter repositories.PlaceFilter, offset, limit int) ([]*models.Place, error) {
+	var places []*models.Place
+	err := r.applyFilter(r.db.WithContext(ctx), filter).
+		Order("rating DESC").
+		Order("review_count DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&places).Error
+	return places, err
+}
+
+func (r *PlaceRepositoryImpl) Count(ctx context.Context, filter repositories.PlaceFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(r.db.WithContext(ctx).Model(&models.Place{}), filter).Count(&count).Error
+	return count, err
+}
+
+func (r *PlaceRepositoryImpl) applyFilter(db *gorm.DB, filter repositories.PlaceFilter) *gorm.DB {
+	for _, word := range strings.Fields(filter.Text) {
+		like := "%" + word + "%"
+		db = db.Where("(name ILIKE ? OR address ILIKE ? OR localized::text ILIKE ?)", like, like, like)
+	}
+	if filter.AddressContains != "" {
+		like := "%" + filter.AddressContains + "%"
+		// Match the address in any stored language
+		db = db.Where("(address ILIKE ? OR localized::text ILIKE ?)", like, like)
+	}
+	if filter.Type != "" {
+		types, _ := json.Marshal([]string{filter.Type})
+		db = db.Where("types @> ?::jsonb", string(types))
+	}
+	if filter.MinRating > 0 {
+		db = db.Where("rating >= ?", filter.MinRating)
+	}
+	if filter.FetchedAfter != nil {
+		db = db.Where("last_fetched_at > ?", *filter.FetchedAfter)
+	}
+	return db
+}
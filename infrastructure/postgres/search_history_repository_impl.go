@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -86,3 +87,8 @@ func (r *SearchHistoryRepositoryImpl) GetRecentByUserID(ctx context.Context, use
 		Find(&histories).Error
 	return histories, err
 }
+
+func (r *SearchHistoryRepositoryImpl) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.SearchHistory{})
+	return result.RowsAffected, result.Error
+}
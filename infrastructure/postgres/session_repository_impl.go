@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gofiber-template/domain/models"
+	"gofiber-template/domain/repositories"
+)
+
+type SessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) repositories.SessionRepository {
+	return &SessionRepositoryImpl{db: db}
+}
+
+// Create stores a new session together with its first refresh token
+func (r *SessionRepositoryImpl) Create(ctx context.Context, session *models.UserSession, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+func (r *SessionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepositoryImpl) ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserSession, error) {
+	var sessions []*models.UserSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch records a refresh: device details, last use and the extended expiry
+func (r *SessionRepositoryImpl) Touch(ctx context.Context, session *models.UserSession) error {
+	return r.db.WithContext(ctx).
+		Model(&models.UserSession{}).
+		Where("id = ?", session.ID).
+		Updates(map[string]interface{}{
+			"user_agent":   session.UserAgent,
+			"ip_address":   session.IPAddress,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+func (r *SessionRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID, reason string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"revoked_reason": reason,
+			"updated_at":     now,
+		}).Error
+}
+
+// RevokeAllByUserID revokes every live session of a user, optionally keeping
+// one, and returns the IDs it revoked
+func (r *SessionRepositoryImpl) RevokeAllByUserID(ctx context.Context, userID uuid.UUID, reason string, exceptID *uuid.UUID) ([]uuid.UUID, error) {
+	now := time.Now()
+	var revoked []models.UserSession
+	query := r.db.WithContext(ctx).
+		Model(&revoked).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now)
+	if exceptID != nil {
+		query = query.Where("id <> ?", *exceptID)
+	}
+
+	err := query.Updates(map[string]interface{}{
+		"revoked_at":     now,
+		"revoked_reason": reason,
+		"updated_at":     now,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(revoked))
+	for i, s := range revoked {
+		ids[i] = s.ID
+	}
+	return ids, nil
+}
+
+func (r *SessionRepositoryImpl) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *SessionRepositoryImpl) RotateRefreshToken(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The used_at guard makes two concurrent refreshes with the same token
+		// race for one row; only the winner gets a new token
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", oldID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gofiber-template/domain/models"
@@ -51,38 +52,131 @@ func (r *UserRepositoryImpl) Update(ctx context.Context, id uuid.UUID, user *mod
 	return r.db.WithContext(ctx).Where("id = ?", id).Updates(user).Error
 }
 
+// SetActive updates is_active on its own; Update skips false as a zero value
+func (r *UserRepositoryImpl) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_active":  active,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *UserRepositoryImpl) SetTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabledAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"totp_secret":           secret,
+			"two_factor_enabled_at": enabledAt,
+			"updated_at":            time.Now(),
+		}).Error
+}
+
 func (r *UserRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.User{}).Error
 }
 
+func (r *UserRepositoryImpl) SetDeletionScheduledAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"deletion_scheduled_at": at,
+			"updated_at":            time.Now(),
+		}).Error
+}
+
+func (r *UserRepositoryImpl) ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*models.User, error) {
+	var users []*models.User
+	err := r.db.WithContext(ctx).
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at < ?", before).
+		Order("deletion_scheduled_at").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
 func (r *UserRepositoryImpl) List(ctx context.Context, offset, limit int) ([]*models.User, error) {
 	var users []*models.User
 	err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&users).Error
 	return users, err
 }
 
+func (r *UserRepositoryImpl) ListIDsWithPermission(ctx context.Context, permission string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Joins("JOIN role_permissions rp ON rp.role = users.role").
+		Where("rp.permission = ? AND users.is_active = ?", permission, true).
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
+
 func (r *UserRepositoryImpl) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.User{}).Count(&count).Error
 	return count, err
 }
 
-func (r *UserRepositoryImpl) GetByGoogleID(ctx context.Context, googleID string) (*models.User, error) {
-	var user models.User
-	err := r.db.WithContext(ctx).Where("google_id = ?", googleID).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+func (r *UserRepositoryImpl) CreateWithIdentity(ctx context.Context, user *models.User, identity *models.UserIdentity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
 }
 
-func (r *UserRepositoryImpl) GetByLineID(ctx context.Context, lineID string) (*models.User, error) {
-	var user models.User
-	err := r.db.WithContext(ctx).Where("line_id = ?", lineID).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+// userOwnedTables are moved wholesale when accounts are merged. Sessions,
+// refresh tokens and email tokens are dropped with the source user instead.
+var userOwnedTables = []string{
+	"user_identities",
+	"favorites",
+	"folders",
+	"search_history",
+	"ai_chat_sessions",
+	"files",
+	"tasks",
+	"api_request_logs",
+	"api_keys",
+	"data_exports",
+}
+
+func (r *UserRepositoryImpl) MergeInto(ctx context.Context, source, target *models.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Favorites the target already has would show up twice
+		err := tx.Exec(`
+			DELETE FROM favorites s
+			USING favorites t
+			WHERE s.user_id = ? AND t.user_id = ?
+			  AND (s.url = t.url OR (s.external_id <> '' AND s.external_id = t.external_id))`,
+			source.ID, target.ID).Error
+		if err != nil {
+			return err
+		}
+
+		for _, table := range userOwnedTables {
+			err := tx.Table(table).
+				Where("user_id = ?", source.ID).
+				Update("user_id", target.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Delete first so target can take over source's unique email or student ID
+		if err := tx.Where("id = ?", source.ID).Delete(&models.User{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ?", target.ID).
+			Select("email", "password", "student_id", "avatar", "email_verified_at", "updated_at").
+			Updates(target).Error
+	})
 }
 
 func (r *UserRepositoryImpl) GetByStudentID(ctx context.Context, studentID string) (*models.User, error) {
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// addSpendScript adds to a spend counter; a new counter gets its TTL
+var addSpendScript = redis.NewScript(`
+local spend = redis.call('INCRBYFLOAT', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return spend
+`)
+
+// raiseSpendScript raises a spend counter to at least ARGV[1] and returns
+// the spend before and after. Floats are returned as strings because Lua
+// numbers are truncated to integers on the way back.
+var raiseSpendScript = redis.NewScript(`
+local before = tonumber(redis.call('GET', KEYS[1]) or '0')
+local target = tonumber(ARGV[1])
+if target <= before then
+	return {tostring(before), tostring(before)}
+end
+redis.call('SET', KEYS[1], ARGV[1], 'KEEPTTL')
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return {tostring(before), ARGV[1]}
+`)
+
+// AddSpend adds amount to the spend counter at key and returns the new
+// total. It is atomic, so exactly one caller sees a threshold crossed.
+func (r *RedisClient) AddSpend(ctx context.Context, key string, amount float64, ttl time.Duration) (float64, error) {
+	return addSpendScript.Run(ctx, r.client, []string{key}, amount, ttl.Milliseconds()).Float64()
+}
+
+// RaiseSpend sets the spend counter at key to amount unless it's already
+// higher, and returns the spend before and after
+func (r *RedisClient) RaiseSpend(ctx context.Context, key string, amount float64, ttl time.Duration) (float64, float64, error) {
+	res, err := raiseSpendScript.Run(ctx, r.client, []string{key}, amount, ttl.Milliseconds()).StringSlice()
+	if err != nil {
+		return 0, 0, err
+	}
+	before, _ := strconv.ParseFloat(res[0], 64)
+	after, _ := strconv.ParseFloat(res[1], 64)
+	return before, after, nil
+}
+
+// GetSpend returns the spend counters at keys; a missing counter is 0
+func (r *RedisClient) GetSpend(ctx context.Context, keys ...string) ([]float64, error) {
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	spend := make([]float64, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			spend[i], _ = strconv.ParseFloat(s, 64)
+		}
+	}
+	return spend, nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/pkg/metrics"
+)
+
+// metricsHook counts GET lookups as cache hits and misses per key prefix.
+// Services use the go-redis client directly, so this is the one place every
+// lookup passes through.
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if key, ok := lookupKey(cmd); ok {
+			metrics.CacheLookup(cache.PrefixOf(key), lookupResult(err))
+		}
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			if key, ok := lookupKey(cmd); ok {
+				metrics.CacheLookup(cache.PrefixOf(key), lookupResult(cmd.Err()))
+			}
+		}
+		return err
+	}
+}
+
+// lookupKey returns the key of a GET command
+func lookupKey(cmd redis.Cmder) (string, bool) {
+	if cmd.Name() != "get" {
+		return "", false
+	}
+	args := cmd.Args()
+	if len(args) < 2 {
+		return "", false
+	}
+	key, ok := args[1].(string)
+	return key, ok
+}
+
+func lookupResult(err error) string {
+	switch {
+	case err == nil:
+		return metrics.CacheHit
+	case errors.Is(err, redis.Nil):
+		return metrics.CacheMiss
+	default:
+		return metrics.CacheError
+	}
+}
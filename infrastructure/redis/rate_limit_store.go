@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hitScript counts a hit in a fixed window. The window starts with the first
+// hit; a key that lost its TTL is given one again so it can't count forever.
+var hitScript = redis.NewScript(`
+local hits = redis.call('INCR', KEYS[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {hits, ttl}
+`)
+
+// unhitScript decrements only a live window; a window that expired meanwhile
+// must not come back as a key without a TTL
+var unhitScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('DECR', KEYS[1])
+end
+return 0
+`)
+
+// Hit counts one request against key and returns the hits so far in the
+// current window and the time until the window resets. It is atomic, so
+// every replica sharing this Redis sees the same count.
+func (r *RedisClient) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	res, err := hitScript.Run(ctx, r.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// Unhit takes back a hit counted by Hit, e.g. when the request failed
+func (r *RedisClient) Unhit(ctx context.Context, key string) error {
+	return unhitScript.Run(ctx, r.client, []string{key}).Err()
+}
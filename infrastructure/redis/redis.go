@@ -24,6 +24,8 @@ func NewRedisClient(config RedisConfig) *RedisClient {
 		Password: config.Password,
 		DB:       config.DB,
 	})
+	client.AddHook(tracingHook{})
+	client.AddHook(metricsHook{})
 
 	return &RedisClient{client: client}
 }
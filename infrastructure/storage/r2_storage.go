@@ -16,7 +16,11 @@ import (
 type R2Storage interface {
 	UploadFile(file io.Reader, path string, contentType string) (string, error)
 	DeleteFile(path string) error
+	// DownloadFile opens an object for reading; the caller closes it
+	DownloadFile(path string) (io.ReadCloser, error)
 	GetFileURL(path string) string
+	// Ping checks that the bucket exists and the credentials can reach it
+	Ping(ctx context.Context) error
 }
 
 type R2StorageImpl struct {
@@ -101,7 +105,32 @@ func (r *R2StorageImpl) DeleteFile(path string) error {
 	return nil
 }
 
+func (r *R2StorageImpl) DownloadFile(path string) (io.ReadCloser, error) {
+	// Clean path (remove leading slash)
+	cleanPath := strings.TrimPrefix(path, "/")
+
+	out, err := r.client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(cleanPath),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file from R2: %w", err)
+	}
+
+	return out.Body, nil
+}
+
 func (r *R2StorageImpl) GetFileURL(path string) string {
 	cleanPath := strings.TrimPrefix(path, "/")
 	return fmt.Sprintf("%s/%s", strings.TrimSuffix(r.publicURL, "/"), cleanPath)
 }
+
+func (r *R2StorageImpl) Ping(ctx context.Context) error {
+	_, err := r.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(r.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reach R2 bucket: %w", err)
+	}
+	return nil
+}
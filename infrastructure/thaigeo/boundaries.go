@@ -0,0 +1,198 @@
+package thaigeo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Boundary file names inside the boundaries file system. The files are the
+// OCHA/RTSD administrative boundaries (COD-AB) exported as GeoJSON, with
+// ADM<n>_PCODE, ADM<n>_TH and ADM<n>_EN feature properties.
+const (
+	ProvinceFile = "provinces.geojson" // ADM1
+	AmphoeFile   = "amphoes.geojson"   // ADM2
+	TambonFile   = "tambons.geojson"   // ADM3
+)
+
+// Area is one administrative unit with its boundary. Codes are DOPA codes:
+// 2 digits for a province, 4 for an amphoe and 6 for a tambon, each
+// starting with its parent's code.
+type Area struct {
+	Code   string
+	NameTH string
+	NameEN string
+
+	box      bbox
+	polygons []polygon
+}
+
+func (a *Area) contains(lat, lng float64) bool {
+	if !a.box.contains(lat, lng) {
+		return false
+	}
+	for _, p := range a.polygons {
+		if p.contains(lat, lng) {
+			return true
+		}
+	}
+	return false
+}
+
+// Location is the administrative hierarchy around a point. Amphoe and Tambon
+// are nil when that level isn't loaded or the point falls in a gap between
+// simplified boundaries.
+type Location struct {
+	Province *Area
+	Amphoe   *Area
+	Tambon   *Area
+}
+
+// ErrNoBoundaries means the province boundary file isn't there
+var ErrNoBoundaries = errors.New("boundary files not found")
+
+// Boundaries answers point-in-polygon lookups over Thai administrative areas
+type Boundaries struct {
+	provinces []*Area
+	index     *provinceIndex
+	children  map[string][]*Area // parent code -> amphoes or tambons
+	amphoes   int
+	tambons   int
+}
+
+// LoadBoundaries reads the boundary files in files. The province file is
+// required; amphoe and tambon files are optional and only narrow the lookup.
+func LoadBoundaries(files fs.FS) (*Boundaries, error) {
+	provinces, err := loadAreas(files, ProvinceFile, 1)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s is missing, see data/boundaries/README.md", ErrNoBoundaries, ProvinceFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Boundaries{
+		provinces: provinces,
+		index:     newProvinceIndex(provinceAreasToProvinces(provinces)),
+		children:  make(map[string][]*Area),
+	}
+
+	for level, file := range map[int]string{2: AmphoeFile, 3: TambonFile} {
+		areas, err := loadAreas(files, file, level)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, area := range areas {
+			parent := area.Code[:len(area.Code)-2]
+			b.children[parent] = append(b.children[parent], area)
+		}
+		if level == 2 {
+			b.amphoes = len(areas)
+		} else {
+			b.tambons = len(areas)
+		}
+	}
+
+	return b, nil
+}
+
+// Provinces returns the provinces of the boundary files ordered by code,
+// with their aliases
+func (b *Boundaries) Provinces() []Province {
+	return b.index.list
+}
+
+// Counts returns how many provinces, amphoes and tambons are loaded
+func (b *Boundaries) Counts() (provinces, amphoes, tambons int) {
+	return len(b.provinces), b.amphoes, b.tambons
+}
+
+// Lookup finds the province, amphoe and tambon containing a point. ok is
+// false when the point is outside Thailand (or at sea).
+func (b *Boundaries) Lookup(lat, lng float64) (Location, bool) {
+	var loc Location
+	loc.Province = findContaining(b.provinces, lat, lng)
+	if loc.Province == nil {
+		return loc, false
+	}
+	loc.Amphoe = findContaining(b.children[loc.Province.Code], lat, lng)
+	if loc.Amphoe != nil {
+		loc.Tambon = findContaining(b.children[loc.Amphoe.Code], lat, lng)
+	}
+	return loc, true
+}
+
+// ProvinceAt returns the province containing a point
+func (b *Boundaries) ProvinceAt(lat, lng float64) (*Province, bool) {
+	area := findContaining(b.provinces, lat, lng)
+	if area == nil {
+		return nil, false
+	}
+	p, ok := b.index.byCode[area.Code]
+	return p, ok
+}
+
+func findContaining(areas []*Area, lat, lng float64) *Area {
+	for _, area := range areas {
+		if area.contains(lat, lng) {
+			return area
+		}
+	}
+	return nil
+}
+
+// featureCollection is the subset of GeoJSON the boundary files use
+type featureCollection struct {
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   geometry               `json:"geometry"`
+	} `json:"features"`
+}
+
+// loadAreas reads one admin level from a GeoJSON file
+func loadAreas(files fs.FS, path string, level int) ([]*Area, error) {
+	data, err := fs.ReadFile(files, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fc featureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	prefix := fmt.Sprintf("ADM%d_", level)
+	areas := make([]*Area, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		code := strings.TrimPrefix(stringProperty(f.Properties, prefix+"PCODE"), "TH")
+		if len(code) != level*2 {
+			return nil, fmt.Errorf("%s: feature %d has invalid %sPCODE %q", path, i, prefix, code)
+		}
+
+		polys, box, err := f.Geometry.polygons()
+		if err != nil {
+			return nil, fmt.Errorf("%s: feature %s: %v", path, code, err)
+		}
+
+		areas = append(areas, &Area{
+			Code:     code,
+			NameTH:   stringProperty(f.Properties, prefix+"TH"),
+			NameEN:   stringProperty(f.Properties, prefix+"EN"),
+			box:      box,
+			polygons: polys,
+		})
+	}
+	return areas, nil
+}
+
+func stringProperty(props map[string]interface{}, key string) string {
+	if v, ok := props[key].(string); ok {
+		return strings.TrimSpace(v)
+	}
+	return ""
+}
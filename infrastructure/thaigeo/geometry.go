@@ -0,0 +1,86 @@
+package thaigeo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// ring is a closed polygon ring of [lng, lat] points, as in GeoJSON
+type ring [][2]float64
+
+// polygon is an outer ring followed by its holes
+type polygon []ring
+
+// bbox is a latitude/longitude box used to skip polygons cheaply
+type bbox struct {
+	minLat, minLng, maxLat, maxLng float64
+}
+
+func emptyBBox() bbox {
+	return bbox{minLat: math.Inf(1), minLng: math.Inf(1), maxLat: math.Inf(-1), maxLng: math.Inf(-1)}
+}
+
+func (b *bbox) extend(lng, lat float64) {
+	b.minLat = math.Min(b.minLat, lat)
+	b.maxLat = math.Max(b.maxLat, lat)
+	b.minLng = math.Min(b.minLng, lng)
+	b.maxLng = math.Max(b.maxLng, lng)
+}
+
+func (b bbox) contains(lat, lng float64) bool {
+	return lat >= b.minLat && lat <= b.maxLat && lng >= b.minLng && lng <= b.maxLng
+}
+
+// contains tests a point with the even-odd rule over all rings, so holes
+// (e.g. an enclave amphoe) are excluded without treating them separately
+func (p polygon) contains(lat, lng float64) bool {
+	inside := false
+	for _, r := range p {
+		for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+			xi, yi := r[i][0], r[i][1]
+			xj, yj := r[j][0], r[j][1]
+			if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// geometry is a GeoJSON Polygon or MultiPolygon
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// polygons decodes the geometry into polygons and their combined bounding box
+func (g geometry) polygons() ([]polygon, bbox, error) {
+	var polys []polygon
+	switch g.Type {
+	case "Polygon":
+		var p polygon
+		if err := json.Unmarshal(g.Coordinates, &p); err != nil {
+			return nil, bbox{}, err
+		}
+		polys = []polygon{p}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &polys); err != nil {
+			return nil, bbox{}, err
+		}
+	default:
+		return nil, bbox{}, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+
+	box := emptyBBox()
+	for _, p := range polys {
+		if len(p) == 0 {
+			continue
+		}
+		// Holes lie inside the outer ring, so it alone bounds the polygon
+		for _, pt := range p[0] {
+			box.extend(pt[0], pt[1])
+		}
+	}
+	return polys, box, nil
+}
@@ -0,0 +1,137 @@
+package thaigeo
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Province is one of Thailand's provinces. Code is the Department of
+// Provincial Administration code, which is also the ISO 3166-2:TH suffix;
+// code and names come from the ADM1 features of the boundary files.
+type Province struct {
+	Code    string
+	NameTH  string
+	NameEN  string
+	Aliases []string // colloquial and alternate romanized names
+}
+
+// provinceAliases are the names people use besides the official ones, by
+// DOPA code. The boundary files only carry official names.
+var provinceAliases = map[string][]string{
+	"10": {"กรุงเทพ", "กรุงเทพฯ", "กทม", "Krung Thep", "Krung Thep Maha Nakhon", "BKK"}, // Bangkok
+	"11": {"ปากน้ำ", "Samut Prakarn", "Paknam"},                                         // Samut Prakan
+	"12": {"นนท์"},                                                                      // Nonthaburi
+	"13": {"ปทุม"},                                                                      // Pathum Thani
+	"14": {"อยุธยา", "กรุงเก่า", "Ayutthaya", "Ayuthaya"},                               // Phra Nakhon Si Ayutthaya
+	"22": {"Chantaburi"},                                                                // Chanthaburi
+	"24": {"แปดริ้ว"},                                                                   // Chachoengsao
+	"27": {"Sa Kaew"},                                                                   // Sa Kaeo
+	"30": {"โคราช", "Korat", "Khorat"},                                                  // Nakhon Ratchasima
+	"33": {"Sri Saket"},                                                                 // Si Sa Ket
+	"34": {"อุบล", "Ubon"},                                                              // Ubon Ratchathani
+	"38": {"Bueng Kal"},                                                                 // Bueng Kan
+	"39": {"Nong Bua Lamphu"},                                                           // Nong Bua Lam Phu
+	"41": {"อุดร", "Udon"},                                                              // Udon Thani
+	"60": {"ปากน้ำโพ"},                                                                  // Nakhon Sawan
+	"74": {"มหาชัย", "Mahachai"},                                                        // Samut Sakhon
+	"75": {"แม่กลอง", "Mae Klong"},                                                      // Samut Songkhram
+	"76": {"เมืองเพชร", "Phetburi"},                                                     // Phetchaburi
+	"77": {"ประจวบ"},                                                                    // Prachuap Khiri Khan
+	"80": {"Nakhon Sri Thammarat"},                                                      // Nakhon Si Thammarat
+	"84": {"สุราษฎร์"},                                                                  // Surat Thani
+}
+
+// provinceIndex looks provinces up by code and by any of their names
+type provinceIndex struct {
+	list   []Province
+	byCode map[string]*Province
+	byName map[string]*Province
+}
+
+func newProvinceIndex(list []Province) *provinceIndex {
+	idx := &provinceIndex{
+		list:   list,
+		byCode: make(map[string]*Province, len(list)),
+		byName: make(map[string]*Province, len(list)*4),
+	}
+	for i := range idx.list {
+		p := &idx.list[i]
+		idx.byCode[p.Code] = p
+		idx.byName[normalizeName(p.NameTH)] = p
+		idx.byName[normalizeName(p.NameEN)] = p
+		for _, alias := range p.Aliases {
+			idx.byName[normalizeName(alias)] = p
+		}
+	}
+	return idx
+}
+
+// provinceAreasToProvinces builds the province list from ADM1 areas, adding aliases
+func provinceAreasToProvinces(areas []*Area) []Province {
+	list := make([]Province, 0, len(areas))
+	for _, area := range areas {
+		list = append(list, Province{
+			Code:    area.Code,
+			NameTH:  area.NameTH,
+			NameEN:  area.NameEN,
+			Aliases: provinceAliases[area.Code],
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// provinces backs the package-level lookups; empty until UseProvinces
+var provinces atomic.Pointer[provinceIndex]
+
+func init() {
+	provinces.Store(newProvinceIndex(nil))
+}
+
+// UseProvinces sets the provinces FindProvince and ProvinceByCode know,
+// normally Boundaries.Provinces() at startup
+func UseProvinces(list []Province) {
+	provinces.Store(newProvinceIndex(list))
+}
+
+// Provinces returns all provinces ordered by code
+func Provinces() []Province {
+	return provinces.Load().list
+}
+
+// ProvinceByCode finds a province by DOPA code ("50") or pcode ("TH50")
+func ProvinceByCode(code string) (*Province, bool) {
+	p, ok := provinces.Load().byCode[strings.TrimPrefix(code, "TH")]
+	return p, ok
+}
+
+// FindProvince matches a Thai or English province name or alias. Case, spaces,
+// hyphens and a leading "จังหวัด"/"จ."/"Changwat" or trailing "Province" are ignored,
+// so "Chiang Mai", "chiangmai" and "จ.เชียงใหม่" all match.
+func FindProvince(name string) (*Province, bool) {
+	p, ok := provinces.Load().byName[normalizeName(name)]
+	return p, ok
+}
+
+// IsProvinceName reports whether name is a province name or alias
+func IsProvinceName(name string) bool {
+	_, ok := FindProvince(name)
+	return ok
+}
+
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, prefix := range []string{"จังหวัด", "จ.", "changwat", "chang wat"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	name = strings.TrimSuffix(name, "province")
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '\'':
+			return -1
+		}
+		return r
+	}, name)
+}
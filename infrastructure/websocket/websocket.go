@@ -19,9 +19,10 @@ type WebSocketManager struct {
 }
 
 type Client struct {
-	Conn   *websocket.Conn
-	UserID uuid.UUID
-	RoomID string
+	Conn      *websocket.Conn
+	UserID    uuid.UUID
+	SessionID uuid.UUID // uuid.Nil for anonymous connections
+	RoomID    string
 }
 
 type Message struct {
@@ -115,11 +116,12 @@ func (m *WebSocketManager) sendMessage(conn *websocket.Conn, message Message) {
 	}
 }
 
-func (m *WebSocketManager) RegisterClient(conn *websocket.Conn, userID uuid.UUID, roomID string) {
+func (m *WebSocketManager) RegisterClient(conn *websocket.Conn, userID, sessionID uuid.UUID, roomID string) {
 	client := Client{
-		Conn:   conn,
-		UserID: userID,
-		RoomID: roomID,
+		Conn:      conn,
+		UserID:    userID,
+		SessionID: sessionID,
+		RoomID:    roomID,
 	}
 	m.register <- client
 }
@@ -128,6 +130,34 @@ func (m *WebSocketManager) UnregisterClient(conn *websocket.Conn) {
 	m.unregister <- conn
 }
 
+// CloseSession disconnects every connection opened with a session that was
+// revoked, telling the client why first. It returns how many were closed.
+func (m *WebSocketManager) CloseSession(sessionID uuid.UUID) int {
+	if sessionID == uuid.Nil {
+		return 0
+	}
+
+	m.mutex.RLock()
+	var conns []*websocket.Conn
+	for conn, client := range m.clients {
+		if client.SessionID == sessionID {
+			conns = append(conns, conn)
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, conn := range conns {
+		conn.WriteJSON(Message{
+			Type: "session_revoked",
+			Data: map[string]interface{}{
+				"sessionId": sessionID.String(),
+			},
+		})
+		m.unregister <- conn
+	}
+	return len(conns)
+}
+
 func (m *WebSocketManager) BroadcastToRoom(roomID string, messageType string, data interface{}) {
 	message := Message{
 		Type: messageType,
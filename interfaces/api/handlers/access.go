@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+// actorFrom identifies the signed-in user to services that check ownership
+func actorFrom(user *utils.UserContext) services.Actor {
+	return services.Actor{UserID: user.ID, Role: user.Role}
+}
+
+// accessErrorResponse answers an error from a service that checks ownership:
+// 403 when the user may not touch the resource, 404 when it (or its folder)
+// doesn't exist, otherwise status
+func accessErrorResponse(c *fiber.Ctx, status int, message string, err error, notFound ...error) error {
+	if errors.Is(err, services.ErrForbidden) {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Insufficient permissions", err)
+	}
+	for _, target := range notFound {
+		if errors.Is(err, target) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, message, err)
+		}
+	}
+	return utils.ErrorResponse(c, status, message, err)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"bufio"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
@@ -36,9 +38,9 @@ func (h *AIHandler) AISearch(c *fiber.Ctx) error {
 
 	userID := getUserIDFromContext(c)
 
-	result, err := h.aiService.AISearch(c.Context(), userID, &req)
+	result, err := h.aiService.AISearch(c.UserContext(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "AI search failed", err)
+		return upstreamErrorResponse(c, "AI search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "AI search completed", result)
@@ -64,9 +66,9 @@ func (h *AIHandler) CreateChatSession(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.aiService.CreateChatSession(c.Context(), user.ID, &req)
+	result, err := h.aiService.CreateChatSession(c.UserContext(), user.ID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create chat session", err)
+		return upstreamErrorResponse(c, "Failed to create chat session", err)
 	}
 
 	return utils.SuccessResponse(c, "Chat session created", result)
@@ -84,7 +86,7 @@ func (h *AIHandler) GetChatSession(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid session ID")
 	}
 
-	result, err := h.aiService.GetChatSession(c.Context(), user.ID, sessionID)
+	result, err := h.aiService.GetChatSession(c.UserContext(), user.ID, sessionID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "Chat session not found", err)
 	}
@@ -103,7 +105,7 @@ func (h *AIHandler) GetChatSessions(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid query parameters")
 	}
 
-	result, err := h.aiService.GetChatSessions(c.Context(), user.ID, &req)
+	result, err := h.aiService.GetChatSessions(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get chat sessions", err)
 	}
@@ -123,7 +125,7 @@ func (h *AIHandler) DeleteChatSession(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid session ID")
 	}
 
-	err = h.aiService.DeleteChatSession(c.Context(), user.ID, sessionID)
+	err = h.aiService.DeleteChatSession(c.UserContext(), user.ID, sessionID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete chat session", err)
 	}
@@ -137,7 +139,7 @@ func (h *AIHandler) ClearAllChatSessions(c *fiber.Ctx) error {
 		return utils.UnauthorizedResponse(c, "User not authenticated")
 	}
 
-	err = h.aiService.ClearAllChatSessions(c.Context(), user.ID)
+	err = h.aiService.ClearAllChatSessions(c.UserContext(), user.ID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to clear chat sessions", err)
 	}
@@ -172,10 +174,84 @@ func (h *AIHandler) SendMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.aiService.SendMessage(c.Context(), user.ID, &req)
+	result, err := h.aiService.SendMessage(c.UserContext(), user.ID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to send message", err)
+		return upstreamErrorResponse(c, "Failed to send message", err)
 	}
 
 	return utils.SuccessResponse(c, "Message sent", result)
 }
+
+func (h *AIHandler) AISearchStream(c *fiber.Ctx) error {
+	var req dto.AISearchRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	userID := getUserIDFromContext(c)
+
+	ctx := c.UserContext()
+	setSSEHeaders(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Errors are already sent to the client as error events and logged by the service
+		_ = h.aiService.AISearchStream(ctx, userID, &req, w)
+	})
+
+	return nil
+}
+
+func (h *AIHandler) SendMessageStream(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	sessionIDStr := c.Params("sessionId")
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid session ID")
+	}
+
+	var req dto.SendAIChatMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+	req.SessionID = sessionID
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	userID := user.ID
+
+	ctx := c.UserContext()
+	setSSEHeaders(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Errors are already sent to the client as error events and logged by the service
+		_ = h.aiService.SendMessageStream(ctx, userID, &req, w)
+	})
+
+	return nil
+}
+
+// setSSEHeaders prepares the response for Server-Sent Events
+func setSSEHeaders(c *fiber.Ctx) {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey creates a personal API key. The key is only in this response.
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.apiKeyService.CreateAPIKey(c.UserContext(), user.ID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAPIKeyExpiryTooLong), errors.Is(err, services.ErrAPIKeyDailyLimit):
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, services.ErrAPIKeyLimitReached):
+			return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create API key", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(utils.Response{
+		Success: true,
+		Message: "API key created successfully",
+		Data:    result,
+	})
+}
+
+// ListAPIKeys lists the user's API keys, without the keys themselves
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(c.UserContext(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve API keys", err)
+	}
+
+	return utils.SuccessResponse(c, "API keys retrieved successfully", keys)
+}
+
+// RevokeAPIKey deletes an API key; requests with it fail from then on
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid API key ID")
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.UserContext(), user.ID, keyID); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to revoke API key", err)
+	}
+
+	return utils.SuccessResponse(c, "API key revoked successfully", nil)
+}
@@ -34,7 +34,7 @@ func (h *APIStatsHandler) GetSummary(c *fiber.Ctx) error {
 		days = 90
 	}
 
-	summary, err := h.logger.GetSummary(c.Context(), days)
+	summary, err := h.logger.GetSummary(c.UserContext(), days)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get summary", err)
 	}
@@ -56,7 +56,7 @@ func (h *APIStatsHandler) GetServiceStats(c *fiber.Ctx) error {
 		days = 7
 	}
 
-	stats, err := h.logger.GetStatsByService(c.Context(), days)
+	stats, err := h.logger.GetStatsByService(c.UserContext(), days)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get stats", err)
 	}
@@ -83,7 +83,7 @@ func (h *APIStatsHandler) GetEndpointStats(c *fiber.Ctx) error {
 		days = 7
 	}
 
-	stats, err := h.logger.GetStatsByEndpoint(c.Context(), service, days)
+	stats, err := h.logger.GetStatsByEndpoint(c.UserContext(), service, days)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get stats", err)
 	}
@@ -112,7 +112,7 @@ func (h *APIStatsHandler) GetDailyStats(c *fiber.Ctx) error {
 		days = 90
 	}
 
-	stats, err := h.logger.GetDailyStats(c.Context(), days)
+	stats, err := h.logger.GetDailyStats(c.UserContext(), days)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get stats", err)
 	}
@@ -137,13 +137,13 @@ func (h *APIStatsHandler) GetCostBreakdown(c *fiber.Ctx) error {
 		days = 30
 	}
 
-	costs, err := h.logger.GetServiceCosts(c.Context(), days)
+	costs, err := h.logger.GetServiceCosts(c.UserContext(), days)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get costs", err)
 	}
 
-	totalCost, _ := h.logger.GetTotalCost(c.Context(), days)
-	cacheHitRate, _ := h.logger.GetCacheHitRate(c.Context(), days)
+	totalCost, _ := h.logger.GetTotalCost(c.UserContext(), days)
+	cacheHitRate, _ := h.logger.GetCacheHitRate(c.UserContext(), days)
 
 	return utils.SuccessResponse(c, "Cost breakdown", fiber.Map{
 		"period":       strconv.Itoa(days) + " days",
@@ -154,6 +154,31 @@ func (h *APIStatsHandler) GetCostBreakdown(c *fiber.Ctx) error {
 	})
 }
 
+// GetLLMUsage returns LLM token usage and cost by feature and model
+// @Summary Get LLM token usage and cost
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param days query int false "Number of days (default: 30)"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/api-stats/llm [get]
+func (h *APIStatsHandler) GetLLMUsage(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 30)
+	if days < 1 {
+		days = 30
+	}
+	if days > 90 {
+		days = 90
+	}
+
+	usage, err := h.logger.GetLLMUsage(c.UserContext(), days)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get LLM usage", err)
+	}
+
+	return utils.SuccessResponse(c, "LLM usage", usage)
+}
+
 // CleanupOldLogs removes old log entries
 // @Summary Cleanup old API logs
 // @Tags Admin
@@ -168,7 +193,7 @@ func (h *APIStatsHandler) CleanupOldLogs(c *fiber.Ctx) error {
 		days = 7 // Minimum 7 days retention
 	}
 
-	deleted, err := h.logger.CleanupOldLogs(c.Context(), days)
+	deleted, err := h.logger.CleanupOldLogs(c.UserContext(), days)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to cleanup", err)
 	}
@@ -39,7 +39,7 @@ func (h *FavoriteHandler) AddFavorite(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.favoriteService.AddFavorite(c.Context(), user.ID, &req)
+	result, err := h.favoriteService.AddFavorite(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to add favorite", err)
 	}
@@ -58,7 +58,7 @@ func (h *FavoriteHandler) GetFavorites(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid query parameters")
 	}
 
-	result, err := h.favoriteService.GetFavorites(c.Context(), user.ID, &req)
+	result, err := h.favoriteService.GetFavorites(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get favorites", err)
 	}
@@ -78,7 +78,7 @@ func (h *FavoriteHandler) RemoveFavorite(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid favorite ID")
 	}
 
-	err = h.favoriteService.RemoveFavorite(c.Context(), user.ID, favoriteID)
+	err = h.favoriteService.RemoveFavorite(c.UserContext(), user.ID, favoriteID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to remove favorite", err)
 	}
@@ -97,7 +97,7 @@ func (h *FavoriteHandler) CheckFavorite(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid query parameters")
 	}
 
-	result, err := h.favoriteService.CheckFavorite(c.Context(), user.ID, &req)
+	result, err := h.favoriteService.CheckFavorite(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to check favorite status", err)
 	}
@@ -125,7 +125,7 @@ func (h *FavoriteHandler) BatchCheckFavorites(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.favoriteService.BatchCheckFavorites(c.Context(), user.ID, req.ExternalIDs)
+	result, err := h.favoriteService.BatchCheckFavorites(c.UserContext(), user.ID, req.ExternalIDs)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to batch check favorites", err)
 	}
@@ -153,7 +153,7 @@ func (h *FavoriteHandler) ToggleFavorite(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.favoriteService.ToggleFavorite(c.Context(), user.ID, &req)
+	result, err := h.favoriteService.ToggleFavorite(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to toggle favorite", err)
 	}
@@ -73,7 +73,7 @@ func (h *FileHandler) UploadFile(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Cannot use both custom_path and structured path fields (category/entity_id/file_type) simultaneously")
 	}
 
-	fileModel, err := h.fileService.UploadFile(c.Context(), user.ID, file, options)
+	fileModel, err := h.fileService.UploadFile(c.UserContext(), user.ID, file, options)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "File upload failed", err)
 	}
@@ -98,15 +98,20 @@ func (h *FileHandler) UploadFile(c *fiber.Ctx) error {
 }
 
 func (h *FileHandler) GetFile(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	fileIDStr := c.Params("id")
 	fileID, err := uuid.Parse(fileIDStr)
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid file ID")
 	}
 
-	file, err := h.fileService.GetFile(c.Context(), fileID)
+	file, err := h.fileService.GetFile(c.UserContext(), actorFrom(user), fileID)
 	if err != nil {
-		return utils.NotFoundResponse(c, "File not found")
+		return accessErrorResponse(c, fiber.StatusInternalServerError, "File not found", err, services.ErrFileNotFound)
 	}
 
 	fileResponse := dto.FileToFileResponse(file)
@@ -114,15 +119,20 @@ func (h *FileHandler) GetFile(c *fiber.Ctx) error {
 }
 
 func (h *FileHandler) DeleteFile(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	fileIDStr := c.Params("id")
 	fileID, err := uuid.Parse(fileIDStr)
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid file ID")
 	}
 
-	err = h.fileService.DeleteFile(c.Context(), fileID)
+	err = h.fileService.DeleteFile(c.UserContext(), actorFrom(user), fileID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "File deletion failed", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "File deletion failed", err, services.ErrFileNotFound)
 	}
 
 	return utils.SuccessResponse(c, "File deleted successfully", nil)
@@ -147,7 +157,7 @@ func (h *FileHandler) GetUserFiles(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
 	}
 
-	files, total, err := h.fileService.GetUserFiles(c.Context(), user.ID, offset, limit)
+	files, total, err := h.fileService.GetUserFiles(c.UserContext(), user.ID, offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve files", err)
 	}
@@ -183,7 +193,7 @@ func (h *FileHandler) ListFiles(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
 	}
 
-	files, total, err := h.fileService.ListFiles(c.Context(), offset, limit)
+	files, total, err := h.fileService.ListFiles(c.UserContext(), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve files", err)
 	}
@@ -39,7 +39,7 @@ func (h *FolderHandler) CreateFolder(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.folderService.CreateFolder(c.Context(), user.ID, &req)
+	result, err := h.folderService.CreateFolder(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create folder", err)
 	}
@@ -59,9 +59,9 @@ func (h *FolderHandler) GetFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid folder ID")
 	}
 
-	result, err := h.folderService.GetFolder(c.Context(), user.ID, folderID)
+	result, err := h.folderService.GetFolder(c.UserContext(), user.ID, folderID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Folder not found", err)
+		return accessErrorResponse(c, fiber.StatusNotFound, "Folder not found", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder retrieved successfully", result)
@@ -78,7 +78,7 @@ func (h *FolderHandler) GetFolders(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid query parameters")
 	}
 
-	result, err := h.folderService.GetFolders(c.Context(), user.ID, &req)
+	result, err := h.folderService.GetFolders(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get folders", err)
 	}
@@ -103,9 +103,9 @@ func (h *FolderHandler) UpdateFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
-	result, err := h.folderService.UpdateFolder(c.Context(), user.ID, folderID, &req)
+	result, err := h.folderService.UpdateFolder(c.UserContext(), user.ID, folderID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update folder", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to update folder", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder updated successfully", result)
@@ -123,9 +123,9 @@ func (h *FolderHandler) DeleteFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid folder ID")
 	}
 
-	err = h.folderService.DeleteFolder(c.Context(), user.ID, folderID)
+	err = h.folderService.DeleteFolder(c.UserContext(), user.ID, folderID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete folder", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to delete folder", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder deleted successfully", nil)
@@ -157,9 +157,9 @@ func (h *FolderHandler) AddItemToFolder(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.folderService.AddItemToFolder(c.Context(), user.ID, folderID, &req)
+	result, err := h.folderService.AddItemToFolder(c.UserContext(), user.ID, folderID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to add item to folder", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to add item to folder", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Item added to folder successfully", result)
@@ -183,9 +183,9 @@ func (h *FolderHandler) GetFolderItems(c *fiber.Ctx) error {
 	}
 	req.FolderID = folderID
 
-	result, err := h.folderService.GetFolderItems(c.Context(), user.ID, &req)
+	result, err := h.folderService.GetFolderItems(c.UserContext(), user.ID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get folder items", err)
+		return accessErrorResponse(c, fiber.StatusInternalServerError, "Failed to get folder items", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder items retrieved successfully", result)
@@ -208,9 +208,9 @@ func (h *FolderHandler) UpdateFolderItem(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
-	result, err := h.folderService.UpdateFolderItem(c.Context(), user.ID, itemID, &req)
+	result, err := h.folderService.UpdateFolderItem(c.UserContext(), user.ID, itemID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update folder item", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to update folder item", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder item updated successfully", result)
@@ -228,9 +228,9 @@ func (h *FolderHandler) RemoveItemFromFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid item ID")
 	}
 
-	err = h.folderService.RemoveItemFromFolder(c.Context(), user.ID, itemID)
+	err = h.folderService.RemoveItemFromFolder(c.UserContext(), user.ID, itemID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to remove item from folder", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to remove item from folder", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Item removed from folder successfully", nil)
@@ -262,9 +262,9 @@ func (h *FolderHandler) ReorderFolderItems(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.folderService.ReorderFolderItems(c.Context(), user.ID, folderID, &req)
+	err = h.folderService.ReorderFolderItems(c.UserContext(), user.ID, folderID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to reorder folder items", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to reorder folder items", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder items reordered successfully", nil)
@@ -287,9 +287,9 @@ func (h *FolderHandler) ShareFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
-	result, err := h.folderService.ShareFolder(c.Context(), user.ID, folderID, &req)
+	result, err := h.folderService.ShareFolder(c.UserContext(), user.ID, folderID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to share folder", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Failed to share folder", err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Folder sharing updated successfully", result)
@@ -302,7 +302,7 @@ func (h *FolderHandler) GetPublicFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid folder ID")
 	}
 
-	result, err := h.folderService.GetPublicFolder(c.Context(), folderID)
+	result, err := h.folderService.GetPublicFolder(c.UserContext(), folderID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "Folder not found or not public", err)
 	}
@@ -325,7 +325,7 @@ func (h *FolderHandler) CheckItemInFolders(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "URL is required")
 	}
 
-	result, err := h.folderService.CheckItemInFolders(c.Context(), user.ID, req.URL)
+	result, err := h.folderService.CheckItemInFolders(c.UserContext(), user.ID, req.URL)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to check item", err)
 	}
@@ -353,7 +353,7 @@ func (h *FolderHandler) BatchCheckItemsInFolders(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.folderService.BatchCheckItemsInFolders(c.Context(), user.ID, req.URLs)
+	result, err := h.folderService.BatchCheckItemsInFolders(c.UserContext(), user.ID, req.URLs)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to batch check items", err)
 	}
@@ -378,9 +378,9 @@ func (h *FolderHandler) UploadItemToFolder(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "No file uploaded")
 	}
 
-	result, err := h.folderService.UploadItemToFolder(c.Context(), user.ID, folderID, file)
+	result, err := h.folderService.UploadItemToFolder(c.UserContext(), user.ID, folderID, file)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, err.Error(), err, services.ErrFolderNotFound, services.ErrFolderItemNotFound)
 	}
 
 	return utils.SuccessResponse(c, "File uploaded successfully", result)
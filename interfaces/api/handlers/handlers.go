@@ -7,15 +7,18 @@ import (
 
 // Services contains all the services needed for handlers
 type Services struct {
-	UserService     services.UserService
-	TaskService     services.TaskService
-	FileService     services.FileService
-	JobService      services.JobService
-	SearchService   services.SearchService
-	AIService       services.AIService
-	FolderService   services.FolderService
-	FavoriteService services.FavoriteService
-	UtilityService  services.UtilityService
+	UserService       services.UserService
+	TaskService       services.TaskService
+	FileService       services.FileService
+	JobService        services.JobService
+	SearchService     services.SearchService
+	AIService         services.AIService
+	FolderService     services.FolderService
+	FavoriteService   services.FavoriteService
+	UtilityService    services.UtilityService
+	PermissionService services.PermissionService
+	APIKeyService     services.APIKeyService
+	HealthService     services.HealthService
 }
 
 // Handlers contains all HTTP handlers
@@ -29,6 +32,9 @@ type Handlers struct {
 	FolderHandler   *FolderHandler
 	FavoriteHandler *FavoriteHandler
 	UtilityHandler  *UtilityHandler
+	RoleHandler     *RoleHandler
+	APIKeyHandler   *APIKeyHandler
+	HealthHandler   *HealthHandler
 }
 
 // NewHandlers creates a new instance of Handlers with all dependencies
@@ -43,5 +49,8 @@ func NewHandlers(services *Services, cfg *config.Config) *Handlers {
 		FolderHandler:   NewFolderHandler(services.FolderService),
 		FavoriteHandler: NewFavoriteHandler(services.FavoriteService),
 		UtilityHandler:  NewUtilityHandler(services.UtilityService, cfg),
+		RoleHandler:     NewRoleHandler(services.PermissionService),
+		APIKeyHandler:   NewAPIKeyHandler(services.APIKeyService),
+		HealthHandler:   NewHealthHandler(services.HealthService),
 	}
 }
\ No newline at end of file
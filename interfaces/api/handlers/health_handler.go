@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/dto"
+	"gofiber-template/domain/services"
+)
+
+// HealthHandler answers load balancer probes. Reports are sent bare, without
+// the success envelope, and the verdict is in the status code.
+type HealthHandler struct {
+	healthService services.HealthService
+}
+
+func NewHealthHandler(healthService services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Livez reports that the process is up and serving
+func (h *HealthHandler) Livez(c *fiber.Ctx) error {
+	return c.JSON(h.healthService.Liveness(c.UserContext()))
+}
+
+// Readyz checks the dependencies. Unhealthy answers 503 so the instance is
+// taken out of rotation; degraded still answers 200.
+func (h *HealthHandler) Readyz(c *fiber.Ctx) error {
+	report := h.healthService.Readiness(c.UserContext())
+
+	status := fiber.StatusOK
+	if report.Status == dto.HealthUnhealthy {
+		status = fiber.StatusServiceUnavailable
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
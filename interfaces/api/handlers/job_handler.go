@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -34,7 +35,7 @@ func (h *JobHandler) CreateJob(c *fiber.Ctx) error {
 		})
 	}
 
-	job, err := h.jobService.CreateJob(c.Context(), &req)
+	job, err := h.jobService.CreateJob(c.UserContext(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Job creation failed", err)
 	}
@@ -50,7 +51,7 @@ func (h *JobHandler) GetJob(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid job ID")
 	}
 
-	job, err := h.jobService.GetJob(c.Context(), jobID)
+	job, err := h.jobService.GetJob(c.UserContext(), jobID)
 	if err != nil {
 		return utils.NotFoundResponse(c, "Job not found")
 	}
@@ -71,7 +72,7 @@ func (h *JobHandler) UpdateJob(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
-	job, err := h.jobService.UpdateJob(c.Context(), jobID, &req)
+	job, err := h.jobService.UpdateJob(c.UserContext(), jobID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Job update failed", err)
 	}
@@ -87,7 +88,7 @@ func (h *JobHandler) DeleteJob(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid job ID")
 	}
 
-	err = h.jobService.DeleteJob(c.Context(), jobID)
+	err = h.jobService.DeleteJob(c.UserContext(), jobID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Job deletion failed", err)
 	}
@@ -102,7 +103,7 @@ func (h *JobHandler) StartJob(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid job ID")
 	}
 
-	err = h.jobService.StartJob(c.Context(), jobID)
+	err = h.jobService.StartJob(c.UserContext(), jobID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to start job", err)
 	}
@@ -117,7 +118,7 @@ func (h *JobHandler) StopJob(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid job ID")
 	}
 
-	err = h.jobService.StopJob(c.Context(), jobID)
+	err = h.jobService.StopJob(c.UserContext(), jobID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to stop job", err)
 	}
@@ -125,6 +126,64 @@ func (h *JobHandler) StopJob(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Job stopped successfully", nil)
 }
 
+// RunJob starts a run of the job now; poll GET /jobs/:id/runs for its outcome
+func (h *JobHandler) RunJob(c *fiber.Ctx) error {
+	jobIDStr := c.Params("id")
+	jobID, err := uuid.Parse(jobIDStr)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid job ID")
+	}
+
+	run, err := h.jobService.RunJob(c.UserContext(), jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrJobRunning) {
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Job is already running", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to run job", err)
+	}
+
+	return utils.SuccessResponse(c, "Job run started", dto.JobRunToJobRunResponse(run))
+}
+
+func (h *JobHandler) ListJobRuns(c *fiber.Ctx) error {
+	jobIDStr := c.Params("id")
+	jobID, err := uuid.Parse(jobIDStr)
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid job ID")
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid offset parameter")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
+	}
+
+	runs, total, err := h.jobService.ListJobRuns(c.UserContext(), jobID, offset, limit)
+	if err != nil {
+		return utils.NotFoundResponse(c, "Job not found")
+	}
+
+	runResponses := make([]dto.JobRunResponse, len(runs))
+	for i, run := range runs {
+		runResponses[i] = *dto.JobRunToJobRunResponse(run)
+	}
+
+	response := &dto.JobRunListResponse{
+		Runs: runResponses,
+		Meta: dto.PaginationMeta{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	return utils.SuccessResponse(c, "Job runs retrieved successfully", response)
+}
+
 func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
 	offsetStr := c.Query("offset", "0")
 	limitStr := c.Query("limit", "10")
@@ -139,7 +198,7 @@ func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
 	}
 
-	jobs, total, err := h.jobService.ListJobs(c.Context(), offset, limit)
+	jobs, total, err := h.jobService.ListJobs(c.UserContext(), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve jobs", err)
 	}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+type RoleHandler struct {
+	permissionService services.PermissionService
+}
+
+func NewRoleHandler(permissionService services.PermissionService) *RoleHandler {
+	return &RoleHandler{
+		permissionService: permissionService,
+	}
+}
+
+// ListPermissions lists every permission and the permissions of each role
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	result, err := h.permissionService.ListPermissions(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve permissions", err)
+	}
+
+	return utils.SuccessResponse(c, "Permissions retrieved successfully", result)
+}
+
+// GrantPermission gives a permission to every user with the role
+func (h *RoleHandler) GrantPermission(c *fiber.Ctx) error {
+	role, permission := c.Params("role"), c.Params("permission")
+
+	if err := h.permissionService.GrantPermission(c.UserContext(), role, permission); err != nil {
+		if errors.Is(err, services.ErrUnknownPermission) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Permission not found", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to grant permission", err)
+	}
+
+	return utils.SuccessResponse(c, "Permission granted successfully", nil)
+}
+
+// RevokePermission takes a permission away from the role
+func (h *RoleHandler) RevokePermission(c *fiber.Ctx) error {
+	role, permission := c.Params("role"), c.Params("permission")
+
+	if err := h.permissionService.RevokePermission(c.UserContext(), role, permission); err != nil {
+		switch {
+		case errors.Is(err, services.ErrPermissionNotGranted):
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Permission not granted", err)
+		case errors.Is(err, services.ErrProtectedPermission):
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Permission cannot be revoked", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to revoke permission", err)
+	}
+
+	return utils.SuccessResponse(c, "Permission revoked successfully", nil)
+}
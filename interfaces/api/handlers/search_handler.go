@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
@@ -36,9 +38,9 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 
 	userID := getUserIDFromContext(c)
 
-	result, err := h.searchService.Search(c.Context(), userID, &req)
+	result, err := h.searchService.Search(c.UserContext(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Search failed", err)
+		return upstreamErrorResponse(c, "Search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "Search completed", result)
@@ -52,9 +54,9 @@ func (h *SearchHandler) SearchWebsites(c *fiber.Ctx) error {
 
 	userID := getUserIDFromContext(c)
 
-	result, err := h.searchService.SearchWebsites(c.Context(), userID, &req)
+	result, err := h.searchService.SearchWebsites(c.UserContext(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Website search failed", err)
+		return upstreamErrorResponse(c, "Website search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "Website search completed", result)
@@ -68,9 +70,9 @@ func (h *SearchHandler) SearchImages(c *fiber.Ctx) error {
 
 	userID := getUserIDFromContext(c)
 
-	result, err := h.searchService.SearchImages(c.Context(), userID, &req)
+	result, err := h.searchService.SearchImages(c.UserContext(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Image search failed", err)
+		return upstreamErrorResponse(c, "Image search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "Image search completed", result)
@@ -84,9 +86,9 @@ func (h *SearchHandler) SearchVideos(c *fiber.Ctx) error {
 
 	userID := getUserIDFromContext(c)
 
-	result, err := h.searchService.SearchVideos(c.Context(), userID, &req)
+	result, err := h.searchService.SearchVideos(c.UserContext(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Video search failed", err)
+		return upstreamErrorResponse(c, "Video search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "Video search completed", result)
@@ -98,7 +100,7 @@ func (h *SearchHandler) GetVideoDetails(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Video ID is required")
 	}
 
-	result, err := h.searchService.GetVideoDetails(c.Context(), videoID)
+	result, err := h.searchService.GetVideoDetails(c.UserContext(), videoID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get video details", err)
 	}
@@ -114,9 +116,12 @@ func (h *SearchHandler) SearchPlaces(c *fiber.Ctx) error {
 
 	userID := getUserIDFromContext(c)
 
-	result, err := h.searchService.SearchPlaces(c.Context(), userID, &req)
+	result, err := h.searchService.SearchPlaces(c.UserContext(), userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Place search failed", err)
+		if errors.Is(err, services.ErrInvalidCursor) {
+			return utils.ValidationErrorResponse(c, "Invalid cursor")
+		}
+		return upstreamErrorResponse(c, "Place search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "Place search completed", result)
@@ -132,9 +137,9 @@ func (h *SearchHandler) GetPlaceDetails(c *fiber.Ctx) error {
 	userLng := c.QueryFloat("lng", 0)
 	lang := c.Query("lang", "th") // Default to Thai
 
-	result, err := h.searchService.GetPlaceDetails(c.Context(), placeID, userLat, userLng, lang)
+	result, err := h.searchService.GetPlaceDetails(c.UserContext(), placeID, userLat, userLng, lang)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get place details", err)
+		return upstreamErrorResponse(c, "Failed to get place details", err)
 	}
 
 	return utils.SuccessResponse(c, "Place details retrieved", result)
@@ -156,9 +161,9 @@ func (h *SearchHandler) GetPlaceDetailsEnhanced(c *fiber.Ctx) error {
 	_, userErr := utils.GetUserFromContext(c)
 	includeAI := userErr == nil // Only include AI for authenticated users
 
-	result, err := h.searchService.GetPlaceDetailsEnhanced(c.Context(), placeID, userLat, userLng, lang, includeAI)
+	result, err := h.searchService.GetPlaceDetailsEnhanced(c.UserContext(), placeID, userLat, userLng, lang, includeAI)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get enhanced place details", err)
+		return upstreamErrorResponse(c, "Failed to get enhanced place details", err)
 	}
 
 	return utils.SuccessResponse(c, "Enhanced place details retrieved", result)
@@ -179,14 +184,63 @@ func (h *SearchHandler) SearchNearbyPlaces(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.searchService.SearchNearbyPlaces(c.Context(), &req)
+	result, err := h.searchService.SearchNearbyPlaces(c.UserContext(), &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Nearby places search failed", err)
+		if errors.Is(err, services.ErrInvalidCursor) {
+			return utils.ValidationErrorResponse(c, "Invalid cursor")
+		}
+		return upstreamErrorResponse(c, "Nearby places search failed", err)
 	}
 
 	return utils.SuccessResponse(c, "Nearby places search completed", result)
 }
 
+func (h *SearchHandler) SearchPlacesInBounds(c *fiber.Ctx) error {
+	var req dto.PlacesInBoundsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.searchService.SearchPlacesInBounds(c.UserContext(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Map places search failed", err)
+	}
+
+	return utils.SuccessResponse(c, "Map places search completed", result)
+}
+
+func (h *SearchHandler) SearchNearestPlaces(c *fiber.Ctx) error {
+	var req dto.NearestPlacesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.searchService.SearchNearestPlaces(c.UserContext(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Nearest places search failed", err)
+	}
+
+	return utils.SuccessResponse(c, "Nearest places search completed", result)
+}
+
 func (h *SearchHandler) GetSearchHistory(c *fiber.Ctx) error {
 	user, err := utils.GetUserFromContext(c)
 	if err != nil {
@@ -198,7 +252,7 @@ func (h *SearchHandler) GetSearchHistory(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid query parameters")
 	}
 
-	result, err := h.searchService.GetSearchHistory(c.Context(), user.ID, &req)
+	result, err := h.searchService.GetSearchHistory(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get search history", err)
 	}
@@ -217,7 +271,7 @@ func (h *SearchHandler) ClearSearchHistory(c *fiber.Ctx) error {
 		req = dto.ClearSearchHistoryRequest{}
 	}
 
-	err = h.searchService.ClearSearchHistory(c.Context(), user.ID, &req)
+	err = h.searchService.ClearSearchHistory(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to clear search history", err)
 	}
@@ -237,7 +291,7 @@ func (h *SearchHandler) DeleteSearchHistoryItem(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid history ID")
 	}
 
-	err = h.searchService.DeleteSearchHistoryItem(c.Context(), user.ID, historyID)
+	err = h.searchService.DeleteSearchHistoryItem(c.UserContext(), user.ID, historyID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete history item", err)
 	}
@@ -39,7 +39,7 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	task, err := h.taskService.CreateTask(c.Context(), user.ID, &req)
+	task, err := h.taskService.CreateTask(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Task creation failed", err)
 	}
@@ -49,15 +49,20 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 }
 
 func (h *TaskHandler) GetTask(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	taskIDStr := c.Params("id")
 	taskID, err := uuid.Parse(taskIDStr)
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid task ID")
 	}
 
-	task, err := h.taskService.GetTask(c.Context(), taskID)
+	task, err := h.taskService.GetTask(c.UserContext(), actorFrom(user), taskID)
 	if err != nil {
-		return utils.NotFoundResponse(c, "Task not found")
+		return accessErrorResponse(c, fiber.StatusInternalServerError, "Task not found", err, services.ErrTaskNotFound)
 	}
 
 	taskResponse := dto.TaskToTaskResponse(task, &task.User)
@@ -65,6 +70,11 @@ func (h *TaskHandler) GetTask(c *fiber.Ctx) error {
 }
 
 func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	taskIDStr := c.Params("id")
 	taskID, err := uuid.Parse(taskIDStr)
 	if err != nil {
@@ -76,9 +86,9 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
-	task, err := h.taskService.UpdateTask(c.Context(), taskID, &req)
+	task, err := h.taskService.UpdateTask(c.UserContext(), actorFrom(user), taskID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Task update failed", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Task update failed", err, services.ErrTaskNotFound)
 	}
 
 	taskResponse := dto.TaskToTaskResponse(task, &task.User)
@@ -86,15 +96,20 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 }
 
 func (h *TaskHandler) DeleteTask(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
 	taskIDStr := c.Params("id")
 	taskID, err := uuid.Parse(taskIDStr)
 	if err != nil {
 		return utils.ValidationErrorResponse(c, "Invalid task ID")
 	}
 
-	err = h.taskService.DeleteTask(c.Context(), taskID)
+	err = h.taskService.DeleteTask(c.UserContext(), actorFrom(user), taskID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Task deletion failed", err)
+		return accessErrorResponse(c, fiber.StatusBadRequest, "Task deletion failed", err, services.ErrTaskNotFound)
 	}
 
 	return utils.SuccessResponse(c, "Task deleted successfully", nil)
@@ -119,7 +134,7 @@ func (h *TaskHandler) GetUserTasks(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
 	}
 
-	tasks, total, err := h.taskService.GetUserTasks(c.Context(), user.ID, offset, limit)
+	tasks, total, err := h.taskService.GetUserTasks(c.UserContext(), user.ID, offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve tasks", err)
 	}
@@ -155,7 +170,7 @@ func (h *TaskHandler) ListTasks(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
 	}
 
-	tasks, total, err := h.taskService.ListTasks(c.Context(), offset, limit)
+	tasks, total, err := h.taskService.ListTasks(c.UserContext(), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve tasks", err)
 	}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/logger"
+	"gofiber-template/pkg/utils"
+)
+
+// upstreamErrorResponse answers an error from a service backed by a paid
+// external API: 503 while the API's budget holds it back, otherwise 500
+func upstreamErrorResponse(c *fiber.Ctx, message string, err error) error {
+	if errors.Is(err, services.ErrAPIBudgetDegraded) || errors.Is(err, services.ErrAPIBudgetExhausted) {
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, message, err)
+	}
+	// Upstream errors can carry request URLs with API keys, so the detail
+	// stays in the log
+	logger.ErrorContext(c.UserContext(), message, "error", err)
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, message, nil)
+}
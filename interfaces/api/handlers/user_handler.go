@@ -3,13 +3,16 @@ package handlers
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"gofiber-template/domain/dto"
+	"gofiber-template/domain/models"
 	"gofiber-template/domain/services"
 	"gofiber-template/pkg/utils"
 )
@@ -39,23 +42,19 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.Register(c.Context(), &req)
+	user, err := h.userService.Register(c.UserContext(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Registration failed", err)
 	}
 
-	// Generate token for auto-login after registration
-	token, err := h.userService.GenerateJWT(user)
+	// Start a session for auto-login after registration
+	tokens, err := h.userService.IssueTokens(c.UserContext(), user, clientInfo(c))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to generate token", err)
 	}
 
 	// Return same format as login for auto-login
-	registerResponse := &dto.LoginResponse{
-		Token: token,
-		User:  *dto.UserToUserResponse(user),
-	}
-	return utils.SuccessResponse(c, "ลงทะเบียนสำเร็จ", registerResponse)
+	return utils.SuccessResponse(c, "ลงทะเบียนสำเร็จ", loginResponse(tokens, user))
 }
 
 func (h *UserHandler) Login(c *fiber.Ctx) error {
@@ -73,16 +72,74 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	token, user, err := h.userService.Login(c.Context(), &req)
+	tokens, user, err := h.userService.Login(c.UserContext(), &req, clientInfo(c))
 	if err != nil {
+		var challenge *services.TwoFactorChallengeError
+		if errors.As(err, &challenge) {
+			return utils.SuccessResponse(c, "Two-factor authentication required", &dto.TwoFactorChallengeResponse{
+				TwoFactorRequired:  true,
+				TwoFactorChallenge: *challenge.Challenge,
+			})
+		}
 		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Login failed", err)
 	}
 
-	loginResponse := &dto.LoginResponse{
-		Token: token,
-		User:  *dto.UserToUserResponse(user),
+	return utils.SuccessResponse(c, "Login successful", loginResponse(tokens, user))
+}
+
+// RefreshToken exchanges a refresh token for a new access/refresh token pair
+func (h *UserHandler) RefreshToken(c *fiber.Ctx) error {
+	var req dto.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	tokens, err := h.userService.RefreshTokens(c.UserContext(), req.RefreshToken, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, err.Error(), err)
+		case errors.Is(err, services.ErrAccountDisabled):
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to refresh token", err)
 	}
-	return utils.SuccessResponse(c, "Login successful", loginResponse)
+
+	return utils.SuccessResponse(c, "Token refreshed", tokens)
+}
+
+// Logout revokes the current session. It uses the access token when it's
+// still valid and falls back to the refresh token in the body otherwise.
+func (h *UserHandler) Logout(c *fiber.Ctx) error {
+	if user, ok := c.Locals("user").(*utils.UserContext); ok {
+		if err := h.userService.Logout(c.UserContext(), user.SessionID); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Logout failed", err)
+		}
+		return utils.SuccessResponse(c, "ออกจากระบบสำเร็จ", nil)
+	}
+
+	var req dto.LogoutRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return utils.UnauthorizedResponse(c, "Missing access token or refresh token")
+	}
+
+	if err := h.userService.LogoutByRefreshToken(c.UserContext(), req.RefreshToken); err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Logout failed", err)
+	}
+
+	return utils.SuccessResponse(c, "ออกจากระบบสำเร็จ", nil)
 }
 
 func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
@@ -91,7 +148,7 @@ func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
 		return utils.UnauthorizedResponse(c, "User not authenticated")
 	}
 
-	profile, err := h.userService.GetProfile(c.Context(), user.ID)
+	profile, err := h.userService.GetProfile(c.UserContext(), user.ID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found", err)
 	}
@@ -111,7 +168,7 @@ func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid request body")
 	}
 
-	updatedUser, err := h.userService.UpdateProfile(c.Context(), user.ID, &req)
+	updatedUser, err := h.userService.UpdateProfile(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Profile update failed", err)
 	}
@@ -141,7 +198,7 @@ func (h *UserHandler) UpdateProfileInfo(c *fiber.Ctx) error {
 		})
 	}
 
-	updatedUser, err := h.userService.UpdateProfileInfo(c.Context(), user.ID, &req)
+	updatedUser, err := h.userService.UpdateProfileInfo(c.UserContext(), user.ID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
 	}
@@ -183,7 +240,7 @@ func (h *UserHandler) UpdateAvatar(c *fiber.Ctx) error {
 	}
 
 	// Update avatar
-	result, err := h.userService.UpdateAvatar(c.Context(), user.ID, fileData, contentType)
+	result, err := h.userService.UpdateAvatar(c.UserContext(), user.ID, fileData, contentType)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
 	}
@@ -198,25 +255,45 @@ func (h *UserHandler) DeleteAvatar(c *fiber.Ctx) error {
 		return utils.UnauthorizedResponse(c, "User not authenticated")
 	}
 
-	if err := h.userService.DeleteAvatar(c.Context(), user.ID); err != nil {
+	if err := h.userService.DeleteAvatar(c.UserContext(), user.ID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error(), err)
 	}
 
 	return utils.SuccessResponse(c, "ลบรูปโปรไฟล์สำเร็จ", nil)
 }
 
+// DeleteUser schedules the account for deletion; RestoreAccount undoes it
+// until the grace period is over
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	user, err := utils.GetUserFromContext(c)
 	if err != nil {
 		return utils.UnauthorizedResponse(c, "User not authenticated")
 	}
 
-	err = h.userService.DeleteUser(c.Context(), user.ID)
+	profile, err := h.userService.ScheduleDeletion(c.UserContext(), user.ID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "User deletion failed", err)
 	}
 
-	return utils.SuccessResponse(c, "User deleted successfully", nil)
+	return utils.SuccessResponse(c, "บัญชีจะถูกลบเมื่อครบกำหนด สามารถกู้คืนได้ก่อนถึงวันดังกล่าว", dto.UserToUserResponse(profile))
+}
+
+// RestoreAccount cancels a scheduled account deletion
+func (h *UserHandler) RestoreAccount(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	profile, err := h.userService.RestoreAccount(c.UserContext(), user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrDeletionNotScheduled) {
+			return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to restore account", err)
+	}
+
+	return utils.SuccessResponse(c, "กู้คืนบัญชีสำเร็จ", dto.UserToUserResponse(profile))
 }
 
 func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
@@ -233,7 +310,7 @@ func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, "Invalid limit parameter")
 	}
 
-	users, total, err := h.userService.ListUsers(c.Context(), offset, limit)
+	users, total, err := h.userService.ListUsers(c.UserContext(), offset, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve users", err)
 	}
@@ -255,6 +332,595 @@ func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Users retrieved successfully", response)
 }
 
+// SetUserActive activates or deactivates a user (admin only). Deactivated
+// users are signed out of every device immediately.
+func (h *UserHandler) SetUserActive(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid user ID")
+	}
+
+	var req dto.UpdateUserStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	user, err := h.userService.SetUserActive(c.UserContext(), userID, *req.IsActive)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "User status update failed", err)
+	}
+
+	return utils.SuccessResponse(c, "User status updated successfully", dto.UserToUserResponse(user))
+}
+
+// ==================== Email Verification / Password Reset ====================
+
+// VerifyEmail confirms the user's email with the token from the verification link
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req dto.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	user, err := h.userService.VerifyEmail(c.UserContext(), req.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidEmailToken) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Email verification failed", err)
+	}
+
+	return utils.SuccessResponse(c, "ยืนยันอีเมลสำเร็จ", dto.UserToUserResponse(user))
+}
+
+// ResendVerificationEmail sends a new verification link to the signed-in user
+func (h *UserHandler) ResendVerificationEmail(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	if err := h.userService.SendVerificationEmail(c.UserContext(), user.ID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), err)
+		case errors.Is(err, services.ErrEmailThrottled):
+			return utils.ErrorResponse(c, fiber.StatusTooManyRequests, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+	}
+
+	return utils.SuccessResponse(c, "ส่งลิงก์ยืนยันอีเมลแล้ว", nil)
+}
+
+// ForgotPassword emails a password reset link. The response is the same
+// whether or not the email has an account.
+func (h *UserHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req dto.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	if err := h.userService.ForgotPassword(c.UserContext(), req.Email); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to send reset link", err)
+	}
+
+	return utils.SuccessResponse(c, "หากอีเมลนี้มีบัญชีอยู่ เราได้ส่งลิงก์ตั้งรหัสผ่านใหม่ไปให้แล้ว", nil)
+}
+
+// ResetPassword sets a new password with the token from the reset link
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	if err := h.userService.ResetPassword(c.UserContext(), req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidEmailToken):
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, services.ErrAccountDisabled):
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Password reset failed", err)
+	}
+
+	return utils.SuccessResponse(c, "ตั้งรหัสผ่านใหม่สำเร็จ กรุณาเข้าสู่ระบบอีกครั้ง", nil)
+}
+
+// ==================== Two-Factor Authentication ====================
+
+// VerifyTwoFactorLogin finishes a login that returned a 2FA challenge. For a
+// setup challenge the code comes from the newly enrolled app and the
+// response also carries the recovery codes.
+func (h *UserHandler) VerifyTwoFactorLogin(c *fiber.Ctx) error {
+	var req dto.TwoFactorLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	tokens, user, recoveryCodes, err := h.userService.CompleteTwoFactorLogin(c.UserContext(), req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	response := loginResponse(tokens, user)
+	response.RecoveryCodes = recoveryCodes
+	return utils.SuccessResponse(c, "Login successful", response)
+}
+
+// SetupTwoFactorForLogin returns the QR code for an account that must enrol
+// before it can finish signing in
+func (h *UserHandler) SetupTwoFactorForLogin(c *fiber.Ctx) error {
+	var req dto.TwoFactorChallengeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	setup, err := h.userService.SetupTwoFactorForChallenge(c.UserContext(), req.ChallengeToken)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Scan the QR code with an authenticator app", setup)
+}
+
+// GetTwoFactorStatus shows whether 2FA is enabled for the user
+func (h *UserHandler) GetTwoFactorStatus(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	status, err := h.userService.GetTwoFactorStatus(c.UserContext(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve two-factor status", err)
+	}
+
+	return utils.SuccessResponse(c, "Two-factor status retrieved successfully", status)
+}
+
+// SetupTwoFactor starts enrolling an authenticator app
+func (h *UserHandler) SetupTwoFactor(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	setup, err := h.userService.SetupTwoFactor(c.UserContext(), user.ID)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Scan the QR code with an authenticator app", setup)
+}
+
+// EnableTwoFactor confirms enrolment with a code from the app
+func (h *UserHandler) EnableTwoFactor(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	codes, err := h.userService.EnableTwoFactor(c.UserContext(), user.ID, req.Code)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "เปิดใช้การยืนยันตัวตนสองขั้นตอนแล้ว", &dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns 2FA off (not allowed for admins)
+func (h *UserHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	if err := h.userService.DisableTwoFactor(c.UserContext(), user.ID, req.Code); err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "ปิดการยืนยันตัวตนสองขั้นตอนแล้ว", nil)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes
+func (h *UserHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	codes, err := h.userService.RegenerateRecoveryCodes(c.UserContext(), user.ID, req.Code)
+	if err != nil {
+		return twoFactorErrorResponse(c, err)
+	}
+
+	return utils.SuccessResponse(c, "สร้างรหัสกู้คืนชุดใหม่แล้ว", &dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func twoFactorErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode), errors.Is(err, services.ErrInvalidTwoFactorChallenge):
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, err.Error(), err)
+	case errors.Is(err, services.ErrTwoFactorThrottled):
+		return utils.ErrorResponse(c, fiber.StatusTooManyRequests, err.Error(), err)
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled), errors.Is(err, services.ErrTwoFactorNotSetUp):
+		return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), err)
+	case errors.Is(err, services.ErrTwoFactorRequired), errors.Is(err, services.ErrAccountDisabled):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error(), err)
+	}
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Two-factor authentication failed", err)
+}
+
+// ==================== Session Handlers ====================
+
+// ListSessions lists the devices the user is signed in on
+func (h *UserHandler) ListSessions(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	sessions, err := h.userService.ListSessions(c.UserContext(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve sessions", err)
+	}
+
+	response := &dto.SessionListResponse{
+		Sessions: make([]dto.SessionResponse, len(sessions)),
+	}
+	for i, session := range sessions {
+		response.Sessions[i] = dto.SessionToSessionResponse(session, user.SessionID)
+	}
+
+	return utils.SuccessResponse(c, "Sessions retrieved successfully", response)
+}
+
+// RevokeSession signs one device out
+func (h *UserHandler) RevokeSession(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid session ID")
+	}
+
+	if err := h.userService.RevokeSession(c.UserContext(), user.ID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to revoke session", err)
+	}
+
+	return utils.SuccessResponse(c, "ออกจากระบบอุปกรณ์นี้แล้ว", nil)
+}
+
+// RevokeOtherSessions signs out every device except the one making the request
+func (h *UserHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	revoked, err := h.userService.RevokeOtherSessions(c.UserContext(), user.ID, user.SessionID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to revoke sessions", err)
+	}
+
+	return utils.SuccessResponse(c, "ออกจากระบบอุปกรณ์อื่นทั้งหมดแล้ว", &dto.RevokeSessionsResponse{Revoked: revoked})
+}
+
+// ==================== Personal Data Handlers ====================
+
+// RequestDataExport starts building a ZIP of the user's data; a
+// data_export_ready WebSocket message follows when it can be downloaded
+func (h *UserHandler) RequestDataExport(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	export, err := h.userService.RequestDataExport(c.UserContext(), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDataExportInProgress):
+			return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), err)
+		case errors.Is(err, services.ErrStorageUnavailable):
+			return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to request data export", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(utils.Response{
+		Success: true,
+		Message: "กำลังเตรียมข้อมูลของคุณ จะแจ้งเตือนเมื่อพร้อมดาวน์โหลด",
+		Data:    dto.DataExportToDataExportResponse(export),
+	})
+}
+
+// ListDataExports lists the user's data exports, newest first
+func (h *UserHandler) ListDataExports(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	exports, err := h.userService.ListDataExports(c.UserContext(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve data exports", err)
+	}
+
+	response := make([]dto.DataExportResponse, len(exports))
+	for i, export := range exports {
+		response[i] = dto.DataExportToDataExportResponse(export)
+	}
+
+	return utils.SuccessResponse(c, "Data exports retrieved successfully", response)
+}
+
+// DownloadDataExport streams a ready export's ZIP
+func (h *UserHandler) DownloadDataExport(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	exportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid export ID")
+	}
+
+	export, body, err := h.userService.OpenDataExport(c.UserContext(), user.ID, exportID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDataExportNotFound):
+			return utils.NotFoundResponse(c, err.Error())
+		case errors.Is(err, services.ErrStorageUnavailable):
+			return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to download data export", err)
+	}
+
+	fileName := fmt.Sprintf("my-data-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.SendStream(body, int(export.Size))
+}
+
+// ==================== Linked Identity Handlers ====================
+
+// ListIdentities lists the Google/LINE accounts linked to the user
+func (h *UserHandler) ListIdentities(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	identities, err := h.userService.ListIdentities(c.UserContext(), user.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve linked accounts", err)
+	}
+
+	return utils.SuccessResponse(c, "Linked accounts retrieved successfully", identities)
+}
+
+// LinkIdentity returns the provider URL that links it to the signed-in user.
+// The browser can't send the bearer token through the OAuth redirect, so the
+// frontend calls this first and then navigates to authUrl.
+func (h *UserHandler) LinkIdentity(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	provider := c.Params("provider")
+	linkToken, err := h.userService.CreateIdentityLinkToken(c.UserContext(), user.ID, provider)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnsupportedProvider):
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to start linking", err)
+	}
+
+	// Create state data
+	stateData := dto.OAuthStateData{
+		FrontendURL: c.Query("frontend_url", os.Getenv("FRONTEND_URL")),
+		LinkToken:   linkToken,
+	}
+
+	// Encode state as base64
+	stateJSON, err := json.Marshal(stateData)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create OAuth state", err)
+	}
+	state := base64.URLEncoding.EncodeToString(stateJSON)
+
+	authURL := h.userService.GetGoogleAuthURL(state)
+	if provider == models.IdentityProviderLine {
+		authURL = h.userService.GetLineAuthURL(state)
+	}
+
+	return utils.SuccessResponse(c, "Continue with the provider to link", &dto.LinkIdentityResponse{AuthURL: authURL})
+}
+
+// UnlinkIdentity removes a linked Google/LINE account
+func (h *UserHandler) UnlinkIdentity(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	identityID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid identity ID")
+	}
+
+	if err := h.userService.UnlinkIdentity(c.UserContext(), user.ID, identityID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrIdentityNotFound):
+			return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error(), err)
+		case errors.Is(err, services.ErrLastLoginMethod):
+			return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to unlink account", err)
+	}
+
+	return utils.SuccessResponse(c, "ยกเลิกการเชื่อมต่อบัญชีแล้ว", nil)
+}
+
+// MergeAccount folds the other account from a link attempt into the signed-in user
+func (h *UserHandler) MergeAccount(c *fiber.Ctx) error {
+	user, err := utils.GetUserFromContext(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "User not authenticated")
+	}
+
+	var req dto.MergeAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid request body")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	merged, err := h.userService.MergeAccounts(c.UserContext(), user.ID, req.MergeToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLinkToken):
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, services.ErrAccountDisabled):
+			return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error(), err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to merge accounts", err)
+	}
+
+	return utils.SuccessResponse(c, "รวมบัญชีสำเร็จ", dto.UserToUserResponse(merged))
+}
+
+// finishIdentityLink handles an OAuth callback that was started by LinkIdentity
+func (h *UserHandler) finishIdentityLink(c *fiber.Ctx, provider, code string, stateData dto.OAuthStateData, frontendURL string) error {
+	result, err := h.userService.LinkIdentity(c.UserContext(), provider, code, stateData)
+	if err != nil {
+		errorURL := fmt.Sprintf("%s/auth/link-callback?provider=%s&error=%s", frontendURL, provider, url.QueryEscape(err.Error()))
+		return c.Redirect(errorURL, fiber.StatusTemporaryRedirect)
+	}
+
+	callbackURL := fmt.Sprintf("%s/auth/link-callback?provider=%s&linked=true", frontendURL, provider)
+	if result.MergeToken != "" {
+		callbackURL = fmt.Sprintf("%s/auth/link-callback?provider=%s&merge_token=%s", frontendURL, provider, url.QueryEscape(result.MergeToken))
+	}
+
+	return c.Redirect(callbackURL, fiber.StatusTemporaryRedirect)
+}
+
 // ==================== OAuth Handlers ====================
 
 // GoogleAuth redirects to Google OAuth
@@ -305,18 +971,30 @@ func (h *UserHandler) GoogleCallback(c *fiber.Ctx) error {
 		frontendURL = os.Getenv("FRONTEND_URL")
 	}
 
+	// Linking to a signed-in account rather than signing in
+	if stateData.LinkToken != "" {
+		return h.finishIdentityLink(c, models.IdentityProviderGoogle, code, stateData, frontendURL)
+	}
+
 	// Handle callback
-	token, user, isNewUser, err := h.userService.HandleGoogleCallback(c.Context(), code, stateData)
+	tokens, user, isNewUser, err := h.userService.HandleGoogleCallback(c.UserContext(), code, stateData, clientInfo(c))
 	if err != nil {
+		var challenge *services.TwoFactorChallengeError
+		if errors.As(err, &challenge) {
+			return c.Redirect(twoFactorCallbackURL(frontendURL, challenge.Challenge), fiber.StatusTemporaryRedirect)
+		}
+
 		// Redirect to frontend with error
 		errorURL := fmt.Sprintf("%s/auth/callback?error=%s", frontendURL, url.QueryEscape(err.Error()))
 		return c.Redirect(errorURL, fiber.StatusTemporaryRedirect)
 	}
 
-	// Create callback URL with token
-	callbackURL := fmt.Sprintf("%s/auth/callback?token=%s&is_new_user=%t&user_id=%s",
+	// Create callback URL with tokens
+	callbackURL := fmt.Sprintf("%s/auth/callback?token=%s&refresh_token=%s&expires_in=%d&is_new_user=%t&user_id=%s",
 		frontendURL,
-		url.QueryEscape(token),
+		url.QueryEscape(tokens.Token),
+		url.QueryEscape(tokens.RefreshToken),
+		tokens.ExpiresIn,
 		isNewUser,
 		user.ID.String(),
 	)
@@ -372,21 +1050,61 @@ func (h *UserHandler) LineCallback(c *fiber.Ctx) error {
 		frontendURL = os.Getenv("FRONTEND_URL")
 	}
 
+	// Linking to a signed-in account rather than signing in
+	if stateData.LinkToken != "" {
+		return h.finishIdentityLink(c, models.IdentityProviderLine, code, stateData, frontendURL)
+	}
+
 	// Handle callback
-	token, user, isNewUser, err := h.userService.HandleLineCallback(c.Context(), code, stateData)
+	tokens, user, isNewUser, err := h.userService.HandleLineCallback(c.UserContext(), code, stateData, clientInfo(c))
 	if err != nil {
+		var challenge *services.TwoFactorChallengeError
+		if errors.As(err, &challenge) {
+			return c.Redirect(twoFactorCallbackURL(frontendURL, challenge.Challenge), fiber.StatusTemporaryRedirect)
+		}
+
 		// Redirect to frontend with error
 		errorURL := fmt.Sprintf("%s/auth/callback?error=%s", frontendURL, url.QueryEscape(err.Error()))
 		return c.Redirect(errorURL, fiber.StatusTemporaryRedirect)
 	}
 
-	// Create callback URL with token
-	callbackURL := fmt.Sprintf("%s/auth/callback?token=%s&is_new_user=%t&user_id=%s",
+	// Create callback URL with tokens
+	callbackURL := fmt.Sprintf("%s/auth/callback?token=%s&refresh_token=%s&expires_in=%d&is_new_user=%t&user_id=%s",
 		frontendURL,
-		url.QueryEscape(token),
+		url.QueryEscape(tokens.Token),
+		url.QueryEscape(tokens.RefreshToken),
+		tokens.ExpiresIn,
 		isNewUser,
 		user.ID.String(),
 	)
 
 	return c.Redirect(callbackURL, fiber.StatusTemporaryRedirect)
-}
\ No newline at end of file
+}
+
+// twoFactorCallbackURL sends an OAuth sign-in that still needs a 2FA code
+// back to the frontend, which continues with /auth/2fa/verify
+func twoFactorCallbackURL(frontendURL string, challenge *dto.TwoFactorChallenge) string {
+	return fmt.Sprintf("%s/auth/callback?two_factor_token=%s&setup_required=%t&expires_in=%d",
+		frontendURL,
+		url.QueryEscape(challenge.ChallengeToken),
+		challenge.SetupRequired,
+		challenge.ExpiresIn,
+	)
+}
+
+// clientInfo describes the device making the request, for its session
+func clientInfo(c *fiber.Ctx) dto.ClientInfo {
+	return dto.ClientInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	}
+}
+
+func loginResponse(tokens *dto.AuthTokens, user *models.User) *dto.LoginResponse {
+	return &dto.LoginResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         *dto.UserToUserResponse(user),
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 
 	"gofiber-template/domain/dto"
@@ -36,7 +38,7 @@ func (h *UtilityHandler) Translate(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.utilityService.Translate(c.Context(), &req)
+	result, err := h.utilityService.Translate(c.UserContext(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Translation failed", err)
 	}
@@ -59,7 +61,7 @@ func (h *UtilityHandler) DetectLanguage(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.utilityService.DetectLanguage(c.Context(), &req)
+	result, err := h.utilityService.DetectLanguage(c.UserContext(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Language detection failed", err)
 	}
@@ -82,7 +84,7 @@ func (h *UtilityHandler) GenerateQRCode(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.utilityService.GenerateQRCode(c.Context(), &req)
+	result, err := h.utilityService.GenerateQRCode(c.UserContext(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "QR code generation failed", err)
 	}
@@ -105,7 +107,7 @@ func (h *UtilityHandler) CalculateDistance(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.utilityService.CalculateDistance(c.Context(), &req)
+	result, err := h.utilityService.CalculateDistance(c.UserContext(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Distance calculation failed", err)
 	}
@@ -113,13 +115,56 @@ func (h *UtilityHandler) CalculateDistance(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Distance calculated", result)
 }
 
-func (h *UtilityHandler) HealthCheck(c *fiber.Ctx) error {
-	result, err := h.utilityService.HealthCheck(c.Context())
+func (h *UtilityHandler) Geocode(c *fiber.Ctx) error {
+	var req dto.GeocodeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.utilityService.Geocode(c.UserContext(), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrLocationNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		}
+		return upstreamErrorResponse(c, "Geocoding failed", err)
+	}
+
+	return utils.SuccessResponse(c, "Address geocoded", result)
+}
+
+func (h *UtilityHandler) ReverseGeocode(c *fiber.Ctx) error {
+	var req dto.ReverseGeocodeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ValidationErrorResponse(c, "Invalid query parameters")
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		errors := utils.GetValidationErrors(err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"success": false,
+			"message": "Validation failed",
+			"errors":  errors,
+		})
+	}
+
+	result, err := h.utilityService.ReverseGeocode(c.UserContext(), &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Health check failed", err)
+		if errors.Is(err, services.ErrLocationNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "No address found at this location", err)
+		}
+		return upstreamErrorResponse(c, "Reverse geocoding failed", err)
 	}
 
-	return utils.SuccessResponse(c, "System healthy", result)
+	return utils.SuccessResponse(c, "Location reverse geocoded", result)
 }
 
 // GetPublicConfig returns public configuration for frontend
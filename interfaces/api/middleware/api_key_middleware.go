@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/domain/services"
+	"gofiber-template/pkg/utils"
+)
+
+// APIKeyAuthenticator resolves personal API keys to their owners
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*utils.UserContext, *utils.APIKeyContext, error)
+}
+
+var apiKeyAuthenticator APIKeyAuthenticator
+
+// UseAPIKeys makes APIKey accept keys. Call it before setting up routes.
+func UseAPIKeys(authenticator APIKeyAuthenticator) {
+	apiKeyAuthenticator = authenticator
+}
+
+// APIKey signs the request in as the key's owner when it has an X-API-Key
+// header, and lets it through untouched otherwise. Put it before Optional,
+// which keeps the key's user, and before the rate limiter, which then counts
+// the request against the key's daily limit.
+func APIKey(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rawKey := c.Get(utils.APIKeyHeader)
+		if rawKey == "" {
+			return c.Next()
+		}
+
+		if apiKeyAuthenticator == nil {
+			return utils.UnauthorizedResponse(c, "API keys are not accepted")
+		}
+
+		user, apiKey, err := apiKeyAuthenticator.AuthenticateAPIKey(c.UserContext(), rawKey)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAPIKey) {
+				return utils.UnauthorizedResponse(c, err.Error())
+			}
+			log.Printf("❌ API key check failed: %v", err)
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "API key check failed", err)
+		}
+
+		if !apiKey.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"message": "Insufficient permissions",
+				"error":   "API key is missing scope " + scope,
+			})
+		}
+
+		c.Locals("user", user)
+		utils.SetAPIKeyContext(c, apiKey)
+
+		return c.Next()
+	}
+}
@@ -1,13 +1,56 @@
 package middleware
 
 import (
+	"context"
+	"gofiber-template/infrastructure/cache"
 	"gofiber-template/pkg/utils"
 	"log"
 	"os"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
+// SessionDenylist holds sessions revoked before their access tokens expired
+type SessionDenylist interface {
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+var sessionDenylist SessionDenylist
+
+// UseSessionDenylist makes Protected and Optional reject access tokens of
+// revoked sessions. Call it before setting up routes.
+func UseSessionDenylist(denylist SessionDenylist) {
+	sessionDenylist = denylist
+}
+
+// isSessionRevoked fails open: if Redis is down, a revoked token keeps
+// working until it expires rather than every request being rejected
+func isSessionRevoked(c *fiber.Ctx, sessionID uuid.UUID) bool {
+	if sessionDenylist == nil {
+		return false
+	}
+	revoked, err := sessionDenylist.Exists(c.UserContext(), cache.RevokedSessionKey(sessionID.String()))
+	if err != nil {
+		log.Printf("⚠ Session denylist check failed for %s: %v", sessionID, err)
+		return false
+	}
+	return revoked
+}
+
+// PermissionChecker resolves what a role is allowed to do
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role, permission string) bool
+}
+
+var permissionChecker PermissionChecker
+
+// UsePermissions sets where RequirePermission looks up role permissions.
+// Call it before setting up routes.
+func UsePermissions(checker PermissionChecker) {
+	permissionChecker = checker
+}
+
 // Protected middleware validates JWT tokens and sets user context
 func Protected() fiber.Handler {
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -43,6 +86,10 @@ func Protected() fiber.Handler {
 			}
 		}
 
+		if isSessionRevoked(c, userCtx.SessionID) {
+			return utils.UnauthorizedResponse(c, "Session has been revoked")
+		}
+
 		log.Printf("✅ Token validated for user: %s (%s)", userCtx.Email, userCtx.ID)
 
 		// Set user context in fiber locals
@@ -77,16 +124,25 @@ func AdminOnly() fiber.Handler {
 	return RequireRole("admin")
 }
 
-// OwnerOnly middleware checks if user is the owner of the resource
-func OwnerOnly() fiber.Handler {
+// RequirePermission middleware checks that the user's role has a permission.
+// Without a PermissionChecker every request is refused.
+func RequirePermission(permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		user, err := utils.GetUserFromContext(c)
 		if err != nil {
 			return utils.UnauthorizedResponse(c, "User not authenticated")
 		}
 
-		c.Locals("requireOwnership", true)
-		c.Locals("ownerUserID", user.ID)
+		if permissionChecker == nil {
+			log.Printf("⚠ No permission checker set, denying %s to %s", permission, user.ID)
+		}
+		if permissionChecker == nil || !permissionChecker.HasPermission(c.UserContext(), user.Role, permission) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"message": "Insufficient permissions",
+				"error":   "Missing permission " + permission,
+			})
+		}
 
 		return c.Next()
 	}
@@ -95,6 +151,11 @@ func OwnerOnly() fiber.Handler {
 // Optional middleware that doesn't require authentication but sets user context if token is present
 func Optional() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// Already signed in with an API key
+		if c.Locals("user") != nil {
+			return c.Next()
+		}
+
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			return c.Next()
@@ -107,7 +168,7 @@ func Optional() fiber.Handler {
 
 		jwtSecret := os.Getenv("JWT_SECRET")
 		userCtx, err := utils.ValidateTokenStringToUUID(token, jwtSecret)
-		if err != nil {
+		if err != nil || isSessionRevoked(c, userCtx.SessionID) {
 			return c.Next()
 		}
 
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/pkg/metrics"
+)
+
+// MetricsMiddleware records the latency of every request by route pattern
+// and status code
+func MetricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// The error handler hasn't written the response yet
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		// Unmatched requests end on this middleware; label them together
+		// so scanners can't create a series per path
+		route := c.Route().Path
+		if status == fiber.StatusNotFound && route == "/" && c.Path() != "/" {
+			route = "unmatched"
+		}
+
+		metrics.ObserveHTTPRequest(c.Method(), route, strconv.Itoa(status), time.Since(start))
+		return err
+	}
+}
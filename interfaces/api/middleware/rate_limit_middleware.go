@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/infrastructure/cache"
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/utils"
+)
+
+// rateLimitWindow is the budget period; the messages below say "per day"
+const rateLimitWindow = 24 * time.Hour
+
+// RateLimitStore counts hits in a fixed window shared by all replicas
+type RateLimitStore interface {
+	Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	Unhit(ctx context.Context, key string) error
+}
+
+// rateLimitBudget is one daily budget and its 429 messages
+type rateLimitBudget struct {
+	name         string
+	limit        func(tier config.RateLimitTier) int
+	authMessage  string // %d is the user's daily limit
+	guestMessage string
+	keysOnly     bool // counts only requests made with an API key
+}
+
+var (
+	searchBudget = rateLimitBudget{
+		name:         "search",
+		limit:        func(t config.RateLimitTier) int { return t.Search },
+		authMessage:  "คุณค้นหาเกินจำนวนที่กำหนดสำหรับวันนี้ (%d ครั้ง/วัน) กรุณาลองใหม่พรุ่งนี้",
+		guestMessage: "คุณค้นหาเกินจำนวนที่กำหนดสำหรับวันนี้ กรุณาเข้าสู่ระบบเพื่อค้นหาเพิ่มเติม",
+	}
+	placesBudget = rateLimitBudget{
+		name:         "places",
+		limit:        func(t config.RateLimitTier) int { return t.Places },
+		authMessage:  "คุณดูรายละเอียดสถานที่เกินจำนวนที่กำหนดสำหรับวันนี้ (%d ครั้ง/วัน)",
+		guestMessage: "คุณค้นหาสถานที่เกินจำนวนที่กำหนดสำหรับวันนี้ กรุณาเข้าสู่ระบบเพื่อค้นหาเพิ่มเติม",
+	}
+	mediaBudget = rateLimitBudget{
+		name:         "media",
+		limit:        func(t config.RateLimitTier) int { return t.Media },
+		authMessage:  "คุณค้นหารูปภาพ/วิดีโอเกินจำนวนที่กำหนดสำหรับวันนี้ (%d ครั้ง/วัน)",
+		guestMessage: "คุณค้นหารูปภาพ/วิดีโอเกินจำนวนที่กำหนดสำหรับวันนี้ กรุณาเข้าสู่ระบบเพื่อค้นหาเพิ่มเติม",
+	}
+	// apiKeyBudget replaces every other budget for requests made with an API
+	// key: each key has one daily limit across all endpoints
+	apiKeyBudget = rateLimitBudget{
+		name:        "apikey",
+		authMessage: "API key นี้ใช้งานเกินโควต้ารายวันแล้ว (%d ครั้ง/วัน)",
+		keysOnly:    true,
+	}
+)
+
+// RateLimitMiddleware enforces daily budgets in Redis: guests per IP with the
+// guest budget, signed-in users per account with their role's tier, and API
+// keys per key with the key's own limit. Run it after APIKey and
+// Optional/Protected so the caller is known.
+type RateLimitMiddleware struct {
+	store  RateLimitStore
+	config config.RateLimitConfig
+}
+
+func NewRateLimitMiddleware(store RateLimitStore, cfg config.RateLimitConfig) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store:  store,
+		config: cfg,
+	}
+}
+
+// SearchLimit limits search for both guests and authenticated users
+func (m *RateLimitMiddleware) SearchLimit() fiber.Handler {
+	return m.limit(searchBudget)
+}
+
+// PlacesLimit limits places search for both guests and authenticated users
+func (m *RateLimitMiddleware) PlacesLimit() fiber.Handler {
+	return m.limit(placesBudget)
+}
+
+// MediaLimit limits media search for both guests and authenticated users
+func (m *RateLimitMiddleware) MediaLimit() fiber.Handler {
+	return m.limit(mediaBudget)
+}
+
+// APIKeyLimit counts requests made with an API key on routes that have no
+// other budget; everyone else passes
+func (m *RateLimitMiddleware) APIKeyLimit() fiber.Handler {
+	return m.limit(apiKeyBudget)
+}
+
+// tierFor returns the budgets that apply to the request and whether the
+// caller is signed in
+func (m *RateLimitMiddleware) tierFor(c *fiber.Ctx) (config.RateLimitTier, string, bool) {
+	user, ok := c.Locals("user").(*utils.UserContext)
+	if !ok {
+		return m.config.Guest, "guest:" + c.IP(), false
+	}
+
+	tier, found := m.config.Tiers[user.Role]
+	if !found {
+		tier = m.config.Tiers[m.config.DefaultTier]
+	}
+	return tier, "user:" + user.ID.String(), true
+}
+
+func (m *RateLimitMiddleware) limit(budget rateLimitBudget) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var allowed int
+		var subject string
+		authenticated := true
+		if apiKey := utils.GetAPIKeyFromContext(c.UserContext()); apiKey != nil {
+			budget, subject, allowed = apiKeyBudget, "key:"+apiKey.ID.String(), apiKey.DailyLimit
+		} else if budget.keysOnly {
+			return c.Next()
+		} else {
+			var tier config.RateLimitTier
+			tier, subject, authenticated = m.tierFor(c)
+			allowed = budget.limit(tier)
+		}
+		if allowed < 0 {
+			return c.Next()
+		}
+
+		key := cache.RateLimitKey(budget.name, subject)
+		hits, resetIn, err := m.store.Hit(c.UserContext(), key, rateLimitWindow)
+		if err != nil {
+			// Fail open: a Redis outage shouldn't take search down with it
+			log.Printf("⚠ Rate limit check failed for %s: %v", key, err)
+			return c.Next()
+		}
+
+		remaining := int64(allowed) - hits
+		setRateLimitHeaders(c, allowed, remaining, resetIn)
+
+		if remaining < 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(resetSeconds(resetIn)))
+			if authenticated {
+				return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+					"success": false,
+					"error": fiber.Map{
+						"code":    "RATE_LIMITED",
+						"message": fmt.Sprintf(budget.authMessage, allowed),
+						"action":  "wait",
+					},
+				})
+			}
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error": fiber.Map{
+					"code":    "RATE_LIMITED",
+					"message": budget.guestMessage,
+					"action":  "login_required",
+				},
+			})
+		}
+
+		err = c.Next()
+
+		// Server failures don't use up the budget. Client errors do, or
+		// malformed requests would be a free way around the limit.
+		if responseStatus(c, err) >= fiber.StatusInternalServerError {
+			if unhitErr := m.store.Unhit(c.UserContext(), key); unhitErr == nil {
+				setRateLimitHeaders(c, allowed, remaining+1, resetIn)
+			}
+		}
+
+		return err
+	}
+}
+
+// responseStatus is the status the request ends with: an error returned down
+// the chain is only turned into a response by the error handler later
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// setRateLimitHeaders writes the IETF RateLimit header fields
+// (draft-ietf-httpapi-ratelimit-headers)
+func setRateLimitHeaders(c *fiber.Ctx, limit int, remaining int64, resetIn time.Duration) {
+	if remaining < 0 {
+		remaining = 0
+	}
+	c.Set("RateLimit-Limit", strconv.Itoa(limit))
+	c.Set("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	c.Set("RateLimit-Reset", strconv.Itoa(resetSeconds(resetIn)))
+	c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit, int(rateLimitWindow.Seconds())))
+}
+
+func resetSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
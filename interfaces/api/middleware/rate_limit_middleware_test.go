@@ -0,0 +1,286 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/utils"
+)
+
+// fakeRateLimitStore counts hits in memory; resetIn is what Hit reports as
+// the time left in the window
+type fakeRateLimitStore struct {
+	hits    map[string]int64
+	windows map[string]time.Duration
+	resetIn time.Duration
+}
+
+func newFakeRateLimitStore() *fakeRateLimitStore {
+	return &fakeRateLimitStore{
+		hits:    make(map[string]int64),
+		windows: make(map[string]time.Duration),
+		resetIn: 90 * time.Minute,
+	}
+}
+
+func (s *fakeRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	s.hits[key]++
+	s.windows[key] = window
+	return s.hits[key], s.resetIn, nil
+}
+
+func (s *fakeRateLimitStore) Unhit(ctx context.Context, key string) error {
+	s.hits[key]--
+	return nil
+}
+
+var (
+	testUserID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	testKeyID  = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+)
+
+var testRateLimitConfig = config.RateLimitConfig{
+	Guest: config.RateLimitTier{Search: 2, Places: 1, Media: 1},
+	Tiers: map[string]config.RateLimitTier{
+		"user":    {Search: 5, Places: 3, Media: 3},
+		"premium": {Search: 50, Places: 30, Media: -1},
+	},
+	DefaultTier: "user",
+}
+
+// caller sets who makes the request, like Optional and APIKey do
+type caller struct {
+	role   string // "" for a guest
+	apiKey *utils.APIKeyContext
+}
+
+func newRateLimitTestApp(store RateLimitStore, who caller, limit func(*RateLimitMiddleware) fiber.Handler, handler fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler()})
+	app.Use(func(c *fiber.Ctx) error {
+		// TracingMiddleware derives the user context from the request
+		c.SetUserContext(c.Context())
+		if who.role != "" {
+			c.Locals("user", &utils.UserContext{ID: testUserID, Role: who.role})
+		}
+		if who.apiKey != nil {
+			utils.SetAPIKeyContext(c, who.apiKey)
+		}
+		return c.Next()
+	})
+	app.Get("/", limit(NewRateLimitMiddleware(store, testRateLimitConfig)), handler)
+	return app
+}
+
+func okHandler(c *fiber.Ctx) error {
+	return c.SendString("ok")
+}
+
+func TestRateLimitTierSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		who       caller
+		limit     func(*RateLimitMiddleware) fiber.Handler
+		wantKey   string // "" when nothing is counted
+		wantLimit string
+	}{
+		{
+			name:      "guest is counted per IP with the guest tier",
+			limit:     (*RateLimitMiddleware).SearchLimit,
+			wantKey:   "ratelimit:search:guest:0.0.0.0",
+			wantLimit: "2",
+		},
+		{
+			name:      "user gets the tier of their role",
+			who:       caller{role: "premium"},
+			limit:     (*RateLimitMiddleware).PlacesLimit,
+			wantKey:   "ratelimit:places:user:" + testUserID.String(),
+			wantLimit: "30",
+		},
+		{
+			name:      "role without a tier gets the default tier",
+			who:       caller{role: "editor"},
+			limit:     (*RateLimitMiddleware).SearchLimit,
+			wantKey:   "ratelimit:search:user:" + testUserID.String(),
+			wantLimit: "5",
+		},
+		{
+			name:  "negative limit is unlimited",
+			who:   caller{role: "premium"},
+			limit: (*RateLimitMiddleware).MediaLimit,
+		},
+		{
+			name:      "API key replaces the route's budget",
+			who:       caller{role: "user", apiKey: &utils.APIKeyContext{ID: testKeyID, DailyLimit: 1000}},
+			limit:     (*RateLimitMiddleware).SearchLimit,
+			wantKey:   "ratelimit:apikey:key:" + testKeyID.String(),
+			wantLimit: "1000",
+		},
+		{
+			name:      "API key budget on routes without another one",
+			who:       caller{role: "user", apiKey: &utils.APIKeyContext{ID: testKeyID, DailyLimit: 10}},
+			limit:     (*RateLimitMiddleware).APIKeyLimit,
+			wantKey:   "ratelimit:apikey:key:" + testKeyID.String(),
+			wantLimit: "10",
+		},
+		{
+			name:  "API key budget lets sessions through uncounted",
+			who:   caller{role: "user"},
+			limit: (*RateLimitMiddleware).APIKeyLimit,
+		},
+		{
+			name:  "API key without a daily limit",
+			who:   caller{role: "user", apiKey: &utils.APIKeyContext{ID: testKeyID, DailyLimit: -1}},
+			limit: (*RateLimitMiddleware).SearchLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeRateLimitStore()
+			app := newRateLimitTestApp(store, tt.who, tt.limit, okHandler)
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+
+			if tt.wantKey == "" {
+				if len(store.hits) != 0 {
+					t.Errorf("hits = %v, want none", store.hits)
+				}
+				if got := resp.Header.Get("RateLimit-Limit"); got != "" {
+					t.Errorf("RateLimit-Limit = %q, want none", got)
+				}
+				return
+			}
+			if store.hits[tt.wantKey] != 1 || len(store.hits) != 1 {
+				t.Errorf("hits = %v, want one on %s", store.hits, tt.wantKey)
+			}
+			if got := resp.Header.Get("RateLimit-Limit"); got != tt.wantLimit {
+				t.Errorf("RateLimit-Limit = %q, want %q", got, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestRateLimitWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		who           caller
+		requests      int
+		wantStatus    int
+		wantRemaining string
+		wantAction    string
+	}{
+		{name: "first request", requests: 1, wantStatus: fiber.StatusOK, wantRemaining: "1"},
+		{name: "last allowed request", requests: 2, wantStatus: fiber.StatusOK, wantRemaining: "0"},
+		{name: "guest over the limit", requests: 3, wantStatus: fiber.StatusTooManyRequests, wantRemaining: "0", wantAction: "login_required"},
+		{name: "user over the limit", who: caller{role: "user"}, requests: 6, wantStatus: fiber.StatusTooManyRequests, wantRemaining: "0", wantAction: "wait"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeRateLimitStore()
+			app := newRateLimitTestApp(store, tt.who, (*RateLimitMiddleware).SearchLimit, okHandler)
+
+			var status int
+			var header func(string) string
+			var body string
+			for i := 0; i < tt.requests; i++ {
+				r, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, _ := io.ReadAll(r.Body)
+				status, header, body = r.StatusCode, r.Header.Get, string(b)
+			}
+
+			for key, window := range store.windows {
+				if window != 24*time.Hour {
+					t.Errorf("window of %s = %v, want 24h", key, window)
+				}
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if got := header("RateLimit-Remaining"); got != tt.wantRemaining {
+				t.Errorf("RateLimit-Remaining = %q, want %q", got, tt.wantRemaining)
+			}
+			if got := header("RateLimit-Reset"); got != "5400" {
+				t.Errorf("RateLimit-Reset = %q, want the store's 5400", got)
+			}
+			if got := header("RateLimit-Policy"); !strings.HasSuffix(got, ";w=86400") {
+				t.Errorf("RateLimit-Policy = %q, want a one day window", got)
+			}
+
+			if tt.wantAction == "" {
+				if got := header(fiber.HeaderRetryAfter); got != "" {
+					t.Errorf("Retry-After = %q on an allowed request", got)
+				}
+				return
+			}
+			if got := header(fiber.HeaderRetryAfter); got != "5400" {
+				t.Errorf("Retry-After = %q, want 5400", got)
+			}
+			if !strings.Contains(body, `"action":"`+tt.wantAction+`"`) {
+				t.Errorf("body = %s, want action %q", body, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestRateLimitRefund(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    fiber.Handler
+		wantRefund bool
+	}{
+		{name: "success", handler: okHandler},
+		{name: "client error response", handler: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusBadRequest).SendString("bad")
+		}},
+		{name: "client error returned", handler: func(c *fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusNotFound, "missing")
+		}},
+		{name: "server error response", handler: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusBadGateway).SendString("upstream")
+		}, wantRefund: true},
+		{name: "server error returned", handler: func(c *fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+		}, wantRefund: true},
+		{name: "plain error returned", handler: func(c *fiber.Ctx) error {
+			return io.ErrUnexpectedEOF
+		}, wantRefund: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeRateLimitStore()
+			app := newRateLimitTestApp(store, caller{role: "user"}, (*RateLimitMiddleware).SearchLimit, tt.handler)
+
+			if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			key := "ratelimit:search:user:" + testUserID.String()
+			wantHits := int64(1)
+			if tt.wantRefund {
+				wantHits = 0
+			}
+			if store.hits[key] != wantHits {
+				t.Errorf("hits = %d, want %d", store.hits[key], wantHits)
+			}
+		})
+	}
+}
@@ -4,25 +4,32 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"gofiber-template/application/serviceimpl"
+	"gofiber-template/domain/models"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/interfaces/api/middleware"
 )
 
-// SetupAdminRoutes sets up admin routes for API statistics
-func SetupAdminRoutes(api fiber.Router, logger *serviceimpl.APILoggerService) {
+// SetupAdminRoutes sets up admin routes for API statistics and role permissions
+func SetupAdminRoutes(api fiber.Router, h *handlers.Handlers, logger *serviceimpl.APILoggerService) {
 	statsHandler := handlers.NewAPIStatsHandler(logger)
 
-	// Admin routes - require authentication and admin role
+	// Admin routes - require authentication, each group checks its own permission
 	admin := api.Group("/admin")
 	admin.Use(middleware.Protected())
-	admin.Use(middleware.AdminOnly())
 
 	// API Statistics routes
-	stats := admin.Group("/api-stats")
+	stats := admin.Group("/api-stats", middleware.RequirePermission(models.PermStatsRead))
 	stats.Get("/summary", statsHandler.GetSummary)
 	stats.Get("/services", statsHandler.GetServiceStats)
 	stats.Get("/endpoints", statsHandler.GetEndpointStats)
 	stats.Get("/daily", statsHandler.GetDailyStats)
 	stats.Get("/costs", statsHandler.GetCostBreakdown)
-	stats.Delete("/cleanup", statsHandler.CleanupOldLogs)
+	stats.Get("/llm", statsHandler.GetLLMUsage)
+	stats.Delete("/cleanup", middleware.RequirePermission(models.PermStatsManage), statsHandler.CleanupOldLogs)
+
+	// Role permissions
+	roles := admin.Group("/roles", middleware.RequirePermission(models.PermRolesManage))
+	roles.Get("/", h.RoleHandler.ListPermissions)
+	roles.Put("/:role/permissions/:permission", h.RoleHandler.GrantPermission)
+	roles.Delete("/:role/permissions/:permission", h.RoleHandler.RevokePermission)
 }
@@ -15,6 +15,7 @@ func SetupAIRoutes(api fiber.Router, h *handlers.Handlers) {
 
 	// AI search with summary
 	ai.Get("/search", h.AIHandler.AISearch)
+	ai.Get("/search/stream", h.AIHandler.AISearchStream)
 
 	// AI chat endpoints
 	chat := ai.Group("/chat")
@@ -24,4 +25,5 @@ func SetupAIRoutes(api fiber.Router, h *handlers.Handlers) {
 	chat.Get("/:sessionId", h.AIHandler.GetChatSession)
 	chat.Delete("/:sessionId", h.AIHandler.DeleteChatSession)
 	chat.Post("/:sessionId/messages", h.AIHandler.SendMessage)
+	chat.Post("/:sessionId/messages/stream", h.AIHandler.SendMessageStream)
 }
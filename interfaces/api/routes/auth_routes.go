@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"gofiber-template/interfaces/api/handlers"
+	"gofiber-template/interfaces/api/middleware"
 )
 
 func SetupAuthRoutes(api fiber.Router, h *handlers.Handlers) {
@@ -12,6 +13,20 @@ func SetupAuthRoutes(api fiber.Router, h *handlers.Handlers) {
 	auth.Post("/register", h.UserHandler.Register)
 	auth.Post("/login", h.UserHandler.Login)
 
+	// Two-factor login step (after /login or OAuth returns a challenge)
+	auth.Post("/2fa/verify", h.UserHandler.VerifyTwoFactorLogin)
+	auth.Post("/2fa/setup", h.UserHandler.SetupTwoFactorForLogin)
+
+	// Sessions
+	auth.Post("/refresh", h.UserHandler.RefreshToken)
+	auth.Post("/logout", middleware.Optional(), h.UserHandler.Logout)
+
+	// Email verification and password reset
+	auth.Post("/verify-email", h.UserHandler.VerifyEmail)
+	auth.Post("/verify-email/resend", middleware.Protected(), h.UserHandler.ResendVerificationEmail)
+	auth.Post("/forgot-password", h.UserHandler.ForgotPassword)
+	auth.Post("/reset-password", h.UserHandler.ResetPassword)
+
 	// Google OAuth
 	auth.Get("/google", h.UserHandler.GoogleAuth)
 	auth.Get("/google/callback", h.UserHandler.GoogleCallback)
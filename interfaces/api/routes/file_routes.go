@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"gofiber-template/domain/models"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/interfaces/api/middleware"
 )
@@ -10,8 +11,8 @@ func SetupFileRoutes(api fiber.Router, h *handlers.Handlers) {
 	files := api.Group("/files")
 	files.Use(middleware.Protected())
 	files.Post("/upload", h.FileHandler.UploadFile)
-	files.Get("/", middleware.AdminOnly(), h.FileHandler.ListFiles)
+	files.Get("/", middleware.RequirePermission(models.PermFilesReadAny), h.FileHandler.ListFiles)
 	files.Get("/my", h.FileHandler.GetUserFiles)
-	files.Get("/:id", h.FileHandler.GetFile)
-	files.Delete("/:id", middleware.OwnerOnly(), h.FileHandler.DeleteFile)
+	files.Get("/:id", h.FileHandler.GetFile)       // owner, or files:read:any
+	files.Delete("/:id", h.FileHandler.DeleteFile) // owner, or files:delete:any
 }
\ No newline at end of file
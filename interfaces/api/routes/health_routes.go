@@ -2,16 +2,19 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"gofiber-template/interfaces/api/handlers"
 )
 
-func SetupHealthRoutes(app *fiber.App) {
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"message": "Server is running",
-			"service": "GoFiber Template API",
-		})
-	})
+func SetupHealthRoutes(app *fiber.App, h *handlers.Handlers) {
+	// Liveness: the process is up; restart it when this fails
+	app.Get("/livez", h.HealthHandler.Livez)
+
+	// Readiness: dependencies are reachable; 503 takes the instance out of rotation
+	app.Get("/readyz", h.HealthHandler.Readyz)
+
+	// Kept for existing monitors, same as /livez
+	app.Get("/health", h.HealthHandler.Livez)
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -19,6 +22,8 @@ func SetupHealthRoutes(app *fiber.App) {
 			"version": "1.0.0",
 			"docs":    "/api/v1",
 			"health":  "/health",
+			"livez":   "/livez",
+			"readyz":  "/readyz",
 		})
 	})
 }
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"gofiber-template/domain/models"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/interfaces/api/middleware"
 )
@@ -9,7 +10,7 @@ import (
 func SetupJobRoutes(api fiber.Router, h *handlers.Handlers) {
 	jobs := api.Group("/jobs")
 	jobs.Use(middleware.Protected())
-	jobs.Use(middleware.AdminOnly()) // All job operations require admin access
+	jobs.Use(middleware.RequirePermission(models.PermJobsManage))
 	jobs.Post("/", h.JobHandler.CreateJob)
 	jobs.Get("/", h.JobHandler.ListJobs)
 	jobs.Get("/:id", h.JobHandler.GetJob)
@@ -17,4 +18,6 @@ func SetupJobRoutes(api fiber.Router, h *handlers.Handlers) {
 	jobs.Delete("/:id", h.JobHandler.DeleteJob)
 	jobs.Post("/:id/start", h.JobHandler.StartJob)
 	jobs.Post("/:id/stop", h.JobHandler.StopJob)
+	jobs.Post("/:id/run", h.JobHandler.RunJob)
+	jobs.Get("/:id/runs", h.JobHandler.ListJobRuns)
 }
\ No newline at end of file
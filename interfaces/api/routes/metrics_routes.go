@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"gofiber-template/pkg/metrics"
+	"gofiber-template/pkg/utils"
+)
+
+// SetupMetricsRoutes serves Prometheus metrics at /metrics. A non-empty token
+// must be sent as a bearer token.
+func SetupMetricsRoutes(app *fiber.App, token string) {
+	handler := adaptor.HTTPHandler(metrics.Handler())
+
+	app.Get("/metrics", func(c *fiber.Ctx) error {
+		if token != "" {
+			expected := "Bearer " + token
+			if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), []byte(expected)) != 1 {
+				return utils.UnauthorizedResponse(c, "Invalid metrics token")
+			}
+		}
+		return handler(c)
+	})
+}
@@ -3,11 +3,12 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"gofiber-template/interfaces/api/handlers"
+	"gofiber-template/interfaces/api/middleware"
 )
 
-func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
+func SetupRoutes(app *fiber.App, h *handlers.Handlers, rateLimiter *middleware.RateLimitMiddleware) {
 	// Setup health and root routes
-	SetupHealthRoutes(app)
+	SetupHealthRoutes(app, h)
 
 	// API version group
 	api := app.Group("/api/v1")
@@ -20,7 +21,7 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 	SetupJobRoutes(api, h)
 
 	// STOU Smart Tour routes
-	SetupSearchRoutes(api, h)
+	SetupSearchRoutes(api, h, rateLimiter)
 	SetupAIRoutes(api, h)
 	SetupFolderRoutes(api, h)
 	SetupFavoriteRoutes(api, h)
@@ -3,27 +3,30 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"gofiber-template/domain/models"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/interfaces/api/middleware"
 )
 
-func SetupSearchRoutes(api fiber.Router, h *handlers.Handlers) {
+func SetupSearchRoutes(api fiber.Router, h *handlers.Handlers, rateLimiter *middleware.RateLimitMiddleware) {
 	search := api.Group("/search")
 
-	// Initialize guest rate limiter
-	guestLimiter := middleware.NewGuestRateLimitMiddleware(middleware.DefaultGuestRateLimitConfig())
-
-	// Public search endpoints with guest rate limiting
-	// OptionalAuth allows logged users to save search history
-	search.Get("/", middleware.OptionalAuth(), guestLimiter.GuestSearchLimit(), h.SearchHandler.Search)
-	search.Get("/websites", middleware.OptionalAuth(), guestLimiter.GuestSearchLimit(), h.SearchHandler.SearchWebsites)
-	search.Get("/images", middleware.OptionalAuth(), guestLimiter.GuestMediaLimit(), h.SearchHandler.SearchImages)
-	search.Get("/videos", middleware.OptionalAuth(), guestLimiter.GuestMediaLimit(), h.SearchHandler.SearchVideos)
+	// Public search endpoints with daily budgets (guests per IP, users per account and tier)
+	// OptionalAuth allows logged users to save search history and get their tier's budget
+	// APIKey lets partner backends call with X-API-Key; the key's daily limit replaces the budgets
+	searchKey := middleware.APIKey(models.APIKeyScopeSearch)
+	placesKey := middleware.APIKey(models.APIKeyScopePlaces)
+	search.Get("/", searchKey, middleware.OptionalAuth(), rateLimiter.SearchLimit(), h.SearchHandler.Search)
+	search.Get("/websites", searchKey, middleware.OptionalAuth(), rateLimiter.SearchLimit(), h.SearchHandler.SearchWebsites)
+	search.Get("/images", searchKey, middleware.OptionalAuth(), rateLimiter.MediaLimit(), h.SearchHandler.SearchImages)
+	search.Get("/videos", searchKey, middleware.OptionalAuth(), rateLimiter.MediaLimit(), h.SearchHandler.SearchVideos)
 	search.Get("/videos/:videoId", h.SearchHandler.GetVideoDetails)
-	search.Get("/places", middleware.OptionalAuth(), h.SearchHandler.SearchPlaces) // No rate limit - public
-	search.Get("/places/:placeId", h.SearchHandler.GetPlaceDetails)
-	search.Get("/places/:placeId/enhanced", middleware.OptionalAuth(), h.SearchHandler.GetPlaceDetailsEnhanced)
-	search.Get("/nearby", middleware.OptionalAuth(), guestLimiter.GuestPlacesLimit(), h.SearchHandler.SearchNearbyPlaces)
+	search.Get("/places", searchKey, middleware.OptionalAuth(), rateLimiter.APIKeyLimit(), h.SearchHandler.SearchPlaces) // No rate limit - public (API keys count)
+	search.Get("/places/map", h.SearchHandler.SearchPlacesInBounds)                                                      // Stored places only - no Google call
+	search.Get("/places/nearest", h.SearchHandler.SearchNearestPlaces)                                                   // Stored places only - no Google call
+	search.Get("/places/:placeId", placesKey, rateLimiter.APIKeyLimit(), h.SearchHandler.GetPlaceDetails)
+	search.Get("/places/:placeId/enhanced", placesKey, middleware.OptionalAuth(), rateLimiter.APIKeyLimit(), h.SearchHandler.GetPlaceDetailsEnhanced)
+	search.Get("/nearby", searchKey, middleware.OptionalAuth(), rateLimiter.PlacesLimit(), h.SearchHandler.SearchNearbyPlaces)
 
 	// Protected search history endpoints (login required)
 	history := search.Group("/history")
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"gofiber-template/domain/models"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/interfaces/api/middleware"
 )
@@ -10,9 +11,9 @@ func SetupTaskRoutes(api fiber.Router, h *handlers.Handlers) {
 	tasks := api.Group("/tasks")
 	tasks.Use(middleware.Protected())
 	tasks.Post("/", h.TaskHandler.CreateTask)
-	tasks.Get("/", middleware.AdminOnly(), h.TaskHandler.ListTasks)
+	tasks.Get("/", middleware.RequirePermission(models.PermTasksReadAny), h.TaskHandler.ListTasks)
 	tasks.Get("/my", h.TaskHandler.GetUserTasks)
-	tasks.Get("/:id", h.TaskHandler.GetTask)
-	tasks.Put("/:id", middleware.OwnerOnly(), h.TaskHandler.UpdateTask)
-	tasks.Delete("/:id", middleware.OwnerOnly(), h.TaskHandler.DeleteTask)
+	tasks.Get("/:id", h.TaskHandler.GetTask)       // owner, or tasks:read:any
+	tasks.Put("/:id", h.TaskHandler.UpdateTask)    // owner, or tasks:update:any
+	tasks.Delete("/:id", h.TaskHandler.DeleteTask) // owner, or tasks:delete:any
 }
\ No newline at end of file
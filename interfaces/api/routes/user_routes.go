@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"gofiber-template/domain/models"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/interfaces/api/middleware"
 )
@@ -14,12 +15,42 @@ func SetupUserRoutes(api fiber.Router, h *handlers.Handlers) {
 	users.Get("/profile", h.UserHandler.GetProfile)
 	users.Put("/profile", h.UserHandler.UpdateProfile)
 	users.Patch("/profile", h.UserHandler.UpdateProfileInfo) // For partial updates (firstName, lastName, studentId, etc.)
-	users.Delete("/profile", h.UserHandler.DeleteUser)
+	users.Delete("/profile", h.UserHandler.DeleteUser)       // Scheduled; purged after the grace period
+	users.Post("/profile/restore", h.UserHandler.RestoreAccount)
 
 	// Avatar
 	users.Post("/avatar", h.UserHandler.UpdateAvatar)
 	users.Delete("/avatar", h.UserHandler.DeleteAvatar)
 
-	// Admin only
-	users.Get("/", middleware.AdminOnly(), h.UserHandler.ListUsers)
+	// Sessions (signed-in devices)
+	users.Get("/sessions", h.UserHandler.ListSessions)
+	users.Delete("/sessions", h.UserHandler.RevokeOtherSessions)
+	users.Delete("/sessions/:id", h.UserHandler.RevokeSession)
+
+	// Two-factor authentication
+	users.Get("/2fa", h.UserHandler.GetTwoFactorStatus)
+	users.Post("/2fa/setup", h.UserHandler.SetupTwoFactor)
+	users.Post("/2fa/enable", h.UserHandler.EnableTwoFactor)
+	users.Post("/2fa/disable", h.UserHandler.DisableTwoFactor)
+	users.Post("/2fa/recovery-codes", h.UserHandler.RegenerateRecoveryCodes)
+
+	// Linked Google/LINE accounts
+	users.Get("/identities", h.UserHandler.ListIdentities)
+	users.Post("/identities/:provider/link", h.UserHandler.LinkIdentity)
+	users.Delete("/identities/:id", h.UserHandler.UnlinkIdentity)
+	users.Post("/merge", h.UserHandler.MergeAccount)
+
+	// Personal data export ("download my data")
+	users.Get("/data-exports", h.UserHandler.ListDataExports)
+	users.Post("/data-exports", h.UserHandler.RequestDataExport)
+	users.Get("/data-exports/:id/download", h.UserHandler.DownloadDataExport)
+
+	// Personal API keys for server-to-server access
+	users.Get("/api-keys", h.APIKeyHandler.ListAPIKeys)
+	users.Post("/api-keys", h.APIKeyHandler.CreateAPIKey)
+	users.Delete("/api-keys/:id", h.APIKeyHandler.RevokeAPIKey)
+
+	// Administration
+	users.Get("/", middleware.RequirePermission(models.PermUsersRead), h.UserHandler.ListUsers)
+	users.Patch("/:id/status", middleware.RequirePermission(models.PermUsersManage), h.UserHandler.SetUserActive)
 }
\ No newline at end of file
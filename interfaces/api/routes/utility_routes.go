@@ -21,4 +21,8 @@ func SetupUtilityRoutes(api fiber.Router, h *handlers.Handlers) {
 
 	// Distance calculation (public)
 	utils.Get("/distance", h.UtilityHandler.CalculateDistance)
+
+	// Geocoding (public) - Thai and English province/amphoe/tambon
+	utils.Get("/geocode", h.UtilityHandler.Geocode)
+	utils.Get("/reverse-geocode", h.UtilityHandler.ReverseGeocode)
 }
@@ -23,13 +23,14 @@ func (h *WebSocketHandler) WebSocketUpgrade(c *fiber.Ctx) error {
 }
 
 func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
-	var userID uuid.UUID
+	var userID, sessionID uuid.UUID
 	var roomID string
 
 	// Try to get user from context (set by Optional middleware)
 	if userContext := c.Locals("user"); userContext != nil {
 		if user, ok := userContext.(*utils.UserContext); ok {
 			userID = user.ID
+			sessionID = user.SessionID
 		}
 	}
 
@@ -43,7 +44,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *websocket.Conn) {
 
 	roomID = c.Query("room", "")
 
-	websocketManager.Manager.RegisterClient(c, userID, roomID)
+	websocketManager.Manager.RegisterClient(c, userID, sessionID, roomID)
 
 	defer func() {
 		websocketManager.Manager.UnregisterClient(c)
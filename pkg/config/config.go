@@ -1,8 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
 	"github.com/joho/godotenv"
 )
 
@@ -11,16 +16,28 @@ type Config struct {
 	Database  DatabaseConfig
 	Redis     RedisConfig
 	JWT       JWTConfig
+	TwoFactor TwoFactorConfig
+	APIKey    APIKeyConfig
+	Privacy   PrivacyConfig
 	R2        R2Config
 	Google    GoogleConfig
 	OpenAI    OpenAIConfig
+	LLM       LLMConfig
+	Search    SearchConfig
+	Geo       GeoConfig
 	RateLimit RateLimitConfig
+	Budget    BudgetConfig
+	Mail      MailConfig
+	Metrics   MetricsConfig
+	Tracing   TracingConfig
+	Health    HealthConfig
 }
 
 type AppConfig struct {
-	Name string
-	Port string
-	Env  string
+	Name        string
+	Port        string
+	Env         string
+	FrontendURL string // base URL for links in emails and OAuth redirects
 }
 
 type DatabaseConfig struct {
@@ -30,6 +47,10 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// MigrateOnStart applies pending migrations at startup instead of
+	// requiring `api migrate up` as a release step
+	MigrateOnStart bool
 }
 
 type RedisConfig struct {
@@ -40,7 +61,43 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string
+	Secret     string
+	AccessTTL  time.Duration // lifetime of access tokens; also how long a revoked session stays denylisted
+	RefreshTTL time.Duration // idle lifetime of a session; every refresh extends it
+}
+
+type TwoFactorConfig struct {
+	Issuer        string // shown next to the code in authenticator apps
+	EncryptionKey string // encrypts TOTP secrets at rest; defaults to the JWT secret
+}
+
+// APIKeyConfig limits personal API keys
+type APIKeyConfig struct {
+	DailyLimit int           // default and maximum requests per key per day
+	MaxPerUser int           // keys a user can have at once
+	DefaultTTL time.Duration // lifetime when none is requested
+	MaxTTL     time.Duration
+}
+
+// PrivacyConfig covers personal data exports and account deletion
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration // deleted accounts can be restored until this has passed
+	ExportTTL           time.Duration // how long a data export can be downloaded
+}
+
+type MailConfig struct {
+	Driver       string // smtp or log
+	From         string
+	FromName     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	LogDir       string // log driver saves .eml files here; empty only logs
+
+	// Lifetime of the single-use links sent by email
+	VerifyEmailTTL   time.Duration
+	ResetPasswordTTL time.Duration
 }
 
 type R2Config struct {
@@ -58,14 +115,124 @@ type GoogleConfig struct {
 }
 
 type OpenAIConfig struct {
-	APIKey string
-	Model  string
+	APIKey  string
+	Model   string
+	BaseURL string
+}
+
+// LLMConfig selects the provider and model per AI feature.
+// Providers: "openai", "compatible" (Azure, vLLM, Ollama) and "fake".
+type LLMConfig struct {
+	Compatible    LLMCompatibleConfig
+	Chat          LLMFeatureConfig
+	TravelSummary LLMFeatureConfig
+	PlaceOverview LLMFeatureConfig
+
+	// Prices per model name; a model also matches a price for a prefix of
+	// its name, so "gpt-4o-mini" prices "gpt-4o-mini-2024-07-18"
+	Prices map[string]LLMPrice
+}
+
+// LLMPrice is what a model costs in USD per 1M tokens
+type LLMPrice struct {
+	Input  float64 // prompt tokens
+	Output float64 // completion tokens
+}
+
+// LLMCompatibleConfig configures a second OpenAI-compatible endpoint
+type LLMCompatibleConfig struct {
+	BaseURL     string
+	APIKey      string
+	Model       string
+	AuthHeader  string
+	APIVersion  string
+	StreamUsage bool // the endpoint reports token usage on streams (not older Azure API versions)
+}
+
+type LLMFeatureConfig struct {
+	Provider string
+	Model    string // empty uses the provider default
+}
+
+// SearchConfig holds per-source deadlines and ranking weights for the mixed ("all") search
+type SearchConfig struct {
+	PlacesTimeout   time.Duration
+	VideosTimeout   time.Duration
+	WebsitesTimeout time.Duration
+
+	// Interleaving weights per result type; higher pulls results of that type up
+	PlaceWeight   float64
+	VideoWeight   float64
+	WebsiteWeight float64
+
+	// How long a stored place is served from the places table before Google is asked again
+	PlaceStaleAfter time.Duration
+	// Fresh stored places needed before nearby and text place search skip Google
+	LocalMinResults int
+}
+
+// GeoConfig locates the offline Thai administrative boundary files
+type GeoConfig struct {
+	BoundariesDir string // empty uses the files embedded from data/boundaries
 }
 
 type RateLimitConfig struct {
 	Search  int
 	AI      int
 	General int
+
+	// Daily budgets: guests are counted per IP, signed-in users per account
+	// with the tier named after their role (e.g. user, premium, admin).
+	// Roles without a tier get DefaultTier.
+	Guest       RateLimitTier
+	Tiers       map[string]RateLimitTier
+	DefaultTier string
+}
+
+// RateLimitTier is a set of daily budgets; a negative budget is unlimited
+type RateLimitTier struct {
+	Search int
+	Places int
+	Media  int
+}
+
+// BudgetConfig caps the estimated cost of external API calls per service.
+// Days and months follow the server's time zone.
+type BudgetConfig struct {
+	Services map[string]ServiceBudget // keyed by service name, e.g. google_places
+
+	// Share of a limit (in percent) from which the service answers only from
+	// cache or the database; at 100% it is blocked
+	SoftPercent float64
+
+	AlertWebhookURL string // receives a JSON POST when a service degrades or is blocked
+}
+
+// ServiceBudget is one service's spend limit in USD; 0 means no limit
+type ServiceBudget struct {
+	Daily   float64
+	Monthly float64
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint
+type MetricsConfig struct {
+	Token string // bearer token scrapers must send; empty leaves /metrics open
+}
+
+// TracingConfig controls OpenTelemetry tracing
+type TracingConfig struct {
+	Exporter     string  // "otlp", "stdout" or "none"
+	OTLPEndpoint string  // host:port of the OTLP/HTTP collector
+	OTLPInsecure bool    // plain HTTP to the collector
+	SampleRatio  float64 // share of new traces that are recorded, 0-1
+	ServiceName  string
+}
+
+// HealthConfig controls the /readyz dependency checks
+type HealthConfig struct {
+	CheckTimeout  time.Duration // per check; a check that takes longer fails
+	SlowThreshold time.Duration // a passing check slower than this is degraded
+	ProbeCacheTTL time.Duration // how long Google and OpenAI probe results are reused
 }
 
 func LoadConfig() (*Config, error) {
@@ -77,9 +244,10 @@ func LoadConfig() (*Config, error) {
 
 	config := &Config{
 		App: AppConfig{
-			Name: getEnv("APP_NAME", "GoFiber Template"),
-			Port: getEnv("APP_PORT", "3000"),
-			Env:  getEnv("APP_ENV", "development"),
+			Name:        getEnv("APP_NAME", "GoFiber Template"),
+			Port:        getEnv("APP_PORT", "3000"),
+			Env:         getEnv("APP_ENV", "development"),
+			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:5173"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -88,6 +256,8 @@ func LoadConfig() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", ""),
 			DBName:   getEnv("DB_NAME", "gofiber_template"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			MigrateOnStart: getEnv("DB_MIGRATE_ON_START", "false") == "true",
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -96,7 +266,23 @@ func LoadConfig() (*Config, error) {
 			DB:       redisDB,
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-secret-key"),
+			Secret:     getEnv("JWT_SECRET", "your-secret-key"),
+			AccessTTL:  time.Duration(getEnvInt("JWT_ACCESS_TTL_MINUTES", 15)) * time.Minute,
+			RefreshTTL: time.Duration(getEnvInt("JWT_REFRESH_TTL_DAYS", 30)) * 24 * time.Hour,
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer:        getEnv("TOTP_ISSUER", "STOU Smart Tour"),
+			EncryptionKey: getEnv("TOTP_ENCRYPTION_KEY", getEnv("JWT_SECRET", "your-secret-key")),
+		},
+		APIKey: APIKeyConfig{
+			DailyLimit: getEnvInt("API_KEY_DAILY_LIMIT", 1000),
+			MaxPerUser: getEnvInt("API_KEY_MAX_PER_USER", 10),
+			DefaultTTL: time.Duration(getEnvInt("API_KEY_DEFAULT_TTL_DAYS", 90)) * 24 * time.Hour,
+			MaxTTL:     time.Duration(getEnvInt("API_KEY_MAX_TTL_DAYS", 365)) * 24 * time.Hour,
+		},
+		Privacy: PrivacyConfig{
+			DeletionGracePeriod: time.Duration(getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 30)) * 24 * time.Hour,
+			ExportTTL:           time.Duration(getEnvInt("DATA_EXPORT_TTL_DAYS", 7)) * 24 * time.Hour,
 		},
 		R2: R2Config{
 			AccountID:       getEnv("R2_ACCOUNT_ID", ""),
@@ -111,16 +297,98 @@ func LoadConfig() (*Config, error) {
 			MapsAPIKey:     getEnv("GOOGLE_MAPS_API_KEY", ""),
 		},
 		OpenAI: OpenAIConfig{
-			APIKey: getEnv("OPENAI_API_KEY", ""),
-			Model:  getEnv("OPENAI_MODEL", "gpt-4-turbo-preview"),
+			APIKey:  getEnv("OPENAI_API_KEY", ""),
+			Model:   getEnv("OPENAI_MODEL", "gpt-4-turbo-preview"),
+			BaseURL: getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		},
+		LLM: LLMConfig{
+			Compatible: LLMCompatibleConfig{
+				BaseURL:     getEnv("LLM_COMPATIBLE_BASE_URL", ""),
+				APIKey:      getEnv("LLM_COMPATIBLE_API_KEY", ""),
+				Model:       getEnv("LLM_COMPATIBLE_MODEL", ""),
+				AuthHeader:  getEnv("LLM_COMPATIBLE_AUTH_HEADER", "Authorization"),
+				APIVersion:  getEnv("LLM_COMPATIBLE_API_VERSION", ""),
+				StreamUsage: getEnv("LLM_COMPATIBLE_STREAM_USAGE", "false") == "true",
+			},
+			Chat: LLMFeatureConfig{
+				Provider: getEnv("LLM_CHAT_PROVIDER", "openai"),
+				Model:    getEnv("LLM_CHAT_MODEL", ""),
+			},
+			TravelSummary: LLMFeatureConfig{
+				Provider: getEnv("LLM_TRAVEL_SUMMARY_PROVIDER", "openai"),
+				Model:    getEnv("LLM_TRAVEL_SUMMARY_MODEL", ""),
+			},
+			PlaceOverview: LLMFeatureConfig{
+				Provider: getEnv("LLM_PLACE_OVERVIEW_PROVIDER", "openai"),
+				Model:    getEnv("LLM_PLACE_OVERVIEW_MODEL", ""),
+			},
+			Prices: parseLLMPrices(getEnv("LLM_PRICES", "gpt-4o-mini=0.15/0.60,gpt-4o=2.50/10,gpt-4.1-mini=0.40/1.60,gpt-4.1=2/8,gpt-4-turbo=10/30")),
+		},
+		Search: SearchConfig{
+			PlacesTimeout:   time.Duration(getEnvInt("SEARCH_TIMEOUT_PLACES_MS", 4000)) * time.Millisecond,
+			VideosTimeout:   time.Duration(getEnvInt("SEARCH_TIMEOUT_VIDEOS_MS", 3000)) * time.Millisecond,
+			WebsitesTimeout: time.Duration(getEnvInt("SEARCH_TIMEOUT_WEBSITES_MS", 4000)) * time.Millisecond,
+			PlaceWeight:     getEnvFloat("SEARCH_WEIGHT_PLACE", 3),
+			VideoWeight:     getEnvFloat("SEARCH_WEIGHT_VIDEO", 1),
+			WebsiteWeight:   getEnvFloat("SEARCH_WEIGHT_WEBSITE", 2),
+			PlaceStaleAfter: time.Duration(getEnvInt("SEARCH_PLACE_STALE_AFTER_HOURS", 168)) * time.Hour,
+			LocalMinResults: getEnvInt("SEARCH_LOCAL_MIN_RESULTS", 20),
+		},
+		Geo: GeoConfig{
+			BoundariesDir: getEnv("GEO_BOUNDARIES_DIR", ""),
 		},
 		RateLimit: RateLimitConfig{
 			Search:  getEnvInt("RATE_LIMIT_SEARCH", 30),
 			AI:      getEnvInt("RATE_LIMIT_AI", 10),
 			General: getEnvInt("RATE_LIMIT_GENERAL", 100),
+			Guest: RateLimitTier{
+				Search: getEnvInt("RATE_LIMIT_GUEST_SEARCH", 10),
+				Places: getEnvInt("RATE_LIMIT_GUEST_PLACES", 5),
+				Media:  getEnvInt("RATE_LIMIT_GUEST_MEDIA", 5),
+			},
+			Tiers:       parseRateLimitTiers(getEnv("RATE_LIMIT_TIERS", "user=200/100/100,admin=unlimited")),
+			DefaultTier: getEnv("RATE_LIMIT_DEFAULT_TIER", "user"),
+		},
+		Budget: BudgetConfig{
+			Services:        parseServiceBudgets(getEnv("API_BUDGETS", "google_places=20/400,google_search=5/100,google_geocoding=5/100,youtube=1/20,openai=10/200")),
+			SoftPercent:     getEnvFloat("API_BUDGET_SOFT_PERCENT", 80),
+			AlertWebhookURL: getEnv("API_BUDGET_ALERT_WEBHOOK_URL", ""),
+		},
+		Mail: MailConfig{
+			Driver:           getEnv("MAIL_DRIVER", "log"),
+			From:             getEnv("MAIL_FROM", "no-reply@localhost"),
+			FromName:         getEnv("MAIL_FROM_NAME", "STOU Smart Tour"),
+			SMTPHost:         getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:         getEnv("SMTP_PORT", "587"),
+			SMTPUsername:     getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:     getEnv("SMTP_PASSWORD", ""),
+			LogDir:           getEnv("MAIL_LOG_DIR", ""),
+			VerifyEmailTTL:   time.Duration(getEnvInt("MAIL_VERIFY_EMAIL_TTL_HOURS", 48)) * time.Hour,
+			ResetPasswordTTL: time.Duration(getEnvInt("MAIL_RESET_PASSWORD_TTL_MINUTES", 60)) * time.Minute,
+		},
+		Metrics: MetricsConfig{
+			Token: getEnv("METRICS_TOKEN", ""),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getEnv("OTEL_EXPORTER_OTLP_INSECURE", "true") == "true",
+			SampleRatio:  getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+			ServiceName:  getEnv("OTEL_SERVICE_NAME", "stou-smart-tour-api"),
+		},
+		Health: HealthConfig{
+			CheckTimeout:  time.Duration(getEnvInt("HEALTH_CHECK_TIMEOUT_MS", 2000)) * time.Millisecond,
+			SlowThreshold: time.Duration(getEnvInt("HEALTH_SLOW_THRESHOLD_MS", 500)) * time.Millisecond,
+			ProbeCacheTTL: time.Duration(getEnvInt("HEALTH_PROBE_CACHE_SECONDS", 60)) * time.Second,
 		},
 	}
 
+	// Roles without a tier fall back to the default tier, so it must exist or
+	// every signed-in request would be over budget
+	if _, ok := config.RateLimit.Tiers[config.RateLimit.DefaultTier]; !ok {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT_TIER %q is not a tier in RATE_LIMIT_TIERS", config.RateLimit.DefaultTier)
+	}
+
 	return config, nil
 }
 
@@ -142,4 +410,106 @@ func getEnvInt(key string, defaultValue int) int {
 		return defaultValue
 	}
 	return intVal
-}
\ No newline at end of file
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	floatVal, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return floatVal
+}
+
+// parseRateLimitTiers parses "name=search/places/media" entries separated by
+// commas, e.g. "user=200/100/100,premium=1000/500/500,admin=unlimited"
+func parseRateLimitTiers(value string) map[string]RateLimitTier {
+	tiers := make(map[string]RateLimitTier)
+	for _, entry := range strings.Split(value, ",") {
+		name, budgets, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			continue
+		}
+		if budgets == "unlimited" {
+			tiers[name] = RateLimitTier{Search: -1, Places: -1, Media: -1}
+			continue
+		}
+
+		parts := strings.Split(budgets, "/")
+		if len(parts) != 3 {
+			log.Printf("Warning: ignoring rate limit tier %q, expected name=search/places/media", entry)
+			continue
+		}
+		var limits [3]int
+		valid := true
+		for i, part := range parts {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				valid = false
+				break
+			}
+			limits[i] = n
+		}
+		if !valid {
+			log.Printf("Warning: ignoring rate limit tier %q, budgets must be numbers", entry)
+			continue
+		}
+		tiers[name] = RateLimitTier{Search: limits[0], Places: limits[1], Media: limits[2]}
+	}
+	return tiers
+}
+
+// parseServiceBudgets parses "service=daily/monthly" entries separated by
+// commas, e.g. "google_places=20/400,youtube=1/20"
+func parseServiceBudgets(value string) map[string]ServiceBudget {
+	budgets := make(map[string]ServiceBudget)
+	for _, entry := range strings.Split(value, ",") {
+		name, limits, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			continue
+		}
+
+		daily, monthly, ok := strings.Cut(limits, "/")
+		if !ok {
+			log.Printf("Warning: ignoring API budget %q, expected service=daily/monthly", entry)
+			continue
+		}
+		d, errDaily := strconv.ParseFloat(strings.TrimSpace(daily), 64)
+		m, errMonthly := strconv.ParseFloat(strings.TrimSpace(monthly), 64)
+		if errDaily != nil || errMonthly != nil || d < 0 || m < 0 {
+			log.Printf("Warning: ignoring API budget %q, limits must be non-negative numbers", entry)
+			continue
+		}
+		budgets[name] = ServiceBudget{Daily: d, Monthly: m}
+	}
+	return budgets
+}
+
+// parseLLMPrices parses "model=input/output" entries in USD per 1M tokens,
+// separated by commas, e.g. "gpt-4o-mini=0.15/0.60,gpt-4o=2.50/10"
+func parseLLMPrices(value string) map[string]LLMPrice {
+	prices := make(map[string]LLMPrice)
+	for _, entry := range strings.Split(value, ",") {
+		model, price, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || model == "" {
+			continue
+		}
+
+		input, output, ok := strings.Cut(price, "/")
+		if !ok {
+			log.Printf("Warning: ignoring LLM price %q, expected model=input/output", entry)
+			continue
+		}
+		in, errInput := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		out, errOutput := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if errInput != nil || errOutput != nil || in < 0 || out < 0 {
+			log.Printf("Warning: ignoring LLM price %q, prices must be non-negative numbers", entry)
+			continue
+		}
+		prices[model] = LLMPrice{Input: in, Output: out}
+	}
+	return prices
+}
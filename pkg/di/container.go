@@ -2,46 +2,79 @@ package di
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
+	"os"
 
 	"gorm.io/gorm"
 
 	"gofiber-template/application/serviceimpl"
+	boundarydata "gofiber-template/data/boundaries"
 	"gofiber-template/domain/repositories"
 	"gofiber-template/domain/services"
 	"gofiber-template/infrastructure/external/google"
+	"gofiber-template/infrastructure/external/llm"
 	"gofiber-template/infrastructure/external/openai"
+	"gofiber-template/infrastructure/mail"
 	"gofiber-template/infrastructure/postgres"
+	"gofiber-template/infrastructure/postgres/migrations"
 	"gofiber-template/infrastructure/redis"
 	"gofiber-template/infrastructure/storage"
+	"gofiber-template/infrastructure/thaigeo"
+	"gofiber-template/infrastructure/websocket"
 	"gofiber-template/interfaces/api/handlers"
 	"gofiber-template/pkg/config"
+	"gofiber-template/pkg/metrics"
 	"gofiber-template/pkg/oauth"
 	"gofiber-template/pkg/scheduler"
+	"gofiber-template/pkg/tracing"
 )
 
 type Container struct {
 	// Configuration
 	Config *config.Config
 
+	// Tracing exporter flush, called on shutdown
+	ShutdownTracing func(context.Context) error
+
 	// Infrastructure
 	DB             *gorm.DB
 	RedisClient    *redis.RedisClient
 	R2Storage      storage.R2Storage
+	Mailer         mail.Mailer
 	EventScheduler scheduler.EventScheduler
+	Boundaries     *thaigeo.Boundaries
 
 	// External API Clients
 	GoogleSearchClient    *google.SearchClient
 	GooglePlacesClient    *google.PlacesClient
 	GoogleYouTubeClient   *google.YouTubeClient
 	GoogleTranslateClient *google.TranslateClient
+	GoogleGeocodingClient *google.GeocodingClient
 	OpenAIClient          *openai.AIClient
+	LLMRegistry           *llm.Registry
+
+	// LLM providers per feature
+	ChatLLM          services.LLMProvider
+	TravelSummaryLLM services.LLMProvider
+	PlaceOverviewLLM services.LLMProvider
 
 	// Repositories
 	UserRepository           repositories.UserRepository
+	SessionRepository        repositories.SessionRepository
+	EmailTokenRepository     repositories.EmailTokenRepository
+	UserIdentityRepository   repositories.UserIdentityRepository
+	RecoveryCodeRepository   repositories.RecoveryCodeRepository
+	PermissionRepository     repositories.PermissionRepository
+	APIKeyRepository         repositories.APIKeyRepository
+	DataExportRepository     repositories.DataExportRepository
+	PersonalDataRepository   repositories.PersonalDataRepository
 	TaskRepository           repositories.TaskRepository
 	FileRepository           repositories.FileRepository
 	JobRepository            repositories.JobRepository
+	JobRunRepository         repositories.JobRunRepository
 	FolderRepository         repositories.FolderRepository
 	FolderItemRepository     repositories.FolderItemRepository
 	FavoriteRepository       repositories.FavoriteRepository
@@ -49,21 +82,26 @@ type Container struct {
 	AIChatSessionRepository  repositories.AIChatSessionRepository
 	AIChatMessageRepository  repositories.AIChatMessageRepository
 	PlaceAIContentRepository repositories.PlaceAIContentRepository
+	PlaceRepository          repositories.PlaceRepository
 	APIRequestLogRepository  repositories.APIRequestLogRepository
 
 	// Services
+	APIBudgetGuard   *serviceimpl.APIBudgetGuard
 	APILoggerService *serviceimpl.APILoggerService
 
 	// Domain Services
-	UserService     services.UserService
-	TaskService     services.TaskService
-	FileService     services.FileService
-	JobService      services.JobService
-	SearchService   services.SearchService
-	AIService       services.AIService
-	FolderService   services.FolderService
-	FavoriteService services.FavoriteService
-	UtilityService  services.UtilityService
+	PermissionService services.PermissionService
+	APIKeyService     services.APIKeyService
+	UserService       services.UserService
+	TaskService       services.TaskService
+	FileService       services.FileService
+	JobService        services.JobService
+	SearchService     services.SearchService
+	AIService         services.AIService
+	FolderService     services.FolderService
+	FavoriteService   services.FavoriteService
+	UtilityService    services.UtilityService
+	HealthService     services.HealthService
 }
 
 func NewContainer() *Container {
@@ -91,6 +129,8 @@ func (c *Container) Initialize() error {
 		return err
 	}
 
+	c.initMetrics()
+
 	return nil
 }
 
@@ -101,6 +141,13 @@ func (c *Container) initConfig() error {
 	}
 	c.Config = cfg
 	log.Println("✓ Configuration loaded")
+
+	shutdown, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	c.ShutdownTracing = shutdown
+	log.Printf("✓ Tracing initialized (exporter=%s)", cfg.Tracing.Exporter)
 	return nil
 }
 
@@ -122,11 +169,13 @@ func (c *Container) initInfrastructure() error {
 	c.DB = db
 	log.Println("✓ Database connected")
 
-	// Run migrations
-	if err := postgres.Migrate(db); err != nil {
+	if err := postgres.RegisterTracing(db); err != nil {
+		return fmt.Errorf("register database tracing: %w", err)
+	}
+
+	if err := c.migrateDatabase(); err != nil {
 		return err
 	}
-	log.Println("✓ Database migrated")
 
 	// Initialize Redis
 	redisConfig := redis.RedisConfig{
@@ -160,6 +209,45 @@ func (c *Container) initInfrastructure() error {
 		log.Println("✓ Cloudflare R2 Storage initialized")
 	}
 
+	// Initialize Mailer
+	if c.Config.Mail.Driver == "smtp" {
+		c.Mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     c.Config.Mail.SMTPHost,
+			Port:     c.Config.Mail.SMTPPort,
+			Username: c.Config.Mail.SMTPUsername,
+			Password: c.Config.Mail.SMTPPassword,
+			From:     c.Config.Mail.From,
+			FromName: c.Config.Mail.FromName,
+		})
+		log.Printf("✓ SMTP mailer initialized (%s:%s)", c.Config.Mail.SMTPHost, c.Config.Mail.SMTPPort)
+	} else {
+		c.Mailer = mail.NewLogMailer(mail.LogConfig{
+			Dir:      c.Config.Mail.LogDir,
+			From:     c.Config.Mail.From,
+			FromName: c.Config.Mail.FromName,
+		})
+		log.Println("✓ Log mailer initialized (emails are not delivered)")
+	}
+
+	// Load Thai admin boundaries for offline reverse geocoding, embedded
+	// unless GEO_BOUNDARIES_DIR points at other files
+	var boundaryFiles fs.FS = boundarydata.Files
+	if c.Config.Geo.BoundariesDir != "" {
+		boundaryFiles = os.DirFS(c.Config.Geo.BoundariesDir)
+	}
+	boundaries, err := thaigeo.LoadBoundaries(boundaryFiles)
+	switch {
+	case errors.Is(err, thaigeo.ErrNoBoundaries):
+		log.Printf("Warning: Thai admin boundaries not loaded, reverse geocoding uses Google and province names aren't recognized: %v", err)
+	case err != nil:
+		return fmt.Errorf("failed to load Thai admin boundaries: %w", err)
+	default:
+		c.Boundaries = boundaries
+		thaigeo.UseProvinces(boundaries.Provinces())
+		provinces, amphoes, tambons := boundaries.Counts()
+		log.Printf("✓ Thai admin boundaries loaded (%d provinces, %d amphoes, %d tambons)", provinces, amphoes, tambons)
+	}
+
 	// Initialize External API Clients
 	if err := c.initExternalClients(); err != nil {
 		return err
@@ -168,6 +256,38 @@ func (c *Container) initInfrastructure() error {
 	return nil
 }
 
+// migrateDatabase applies pending migrations when DB_MIGRATE_ON_START is set,
+// otherwise it refuses to start on an outdated schema, and then seeds
+// permissions
+func (c *Container) migrateDatabase() error {
+	migrator, err := postgres.NewMigrator(c.DB, migrations.Files)
+	if err != nil {
+		return err
+	}
+
+	if c.Config.Database.MigrateOnStart {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			return err
+		}
+		log.Printf("✓ Database migrated (%d applied)", len(applied))
+	} else {
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d database migrations pending (first %04d_%s), run `api migrate up` or set DB_MIGRATE_ON_START=true",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+	}
+
+	if err := postgres.SeedPermissions(c.DB); err != nil {
+		return fmt.Errorf("seed permissions: %w", err)
+	}
+	return nil
+}
+
 func (c *Container) initExternalClients() error {
 	// Initialize Google API Clients
 	apiKey := c.Config.Google.APIKey
@@ -180,12 +300,23 @@ func (c *Container) initExternalClients() error {
 	c.GooglePlacesClient = google.NewPlacesClient(mapsAPIKey)
 	c.GoogleYouTubeClient = google.NewYouTubeClient(apiKey)
 	c.GoogleTranslateClient = google.NewTranslateClient(apiKey)
+	c.GoogleGeocodingClient = google.NewGeocodingClient(mapsAPIKey)
 	log.Println("✓ Google API clients initialized")
 
 	// Initialize OpenAI Client
-	c.OpenAIClient = openai.NewAIClient(c.Config.OpenAI.APIKey, c.Config.OpenAI.Model)
+	c.OpenAIClient = openai.NewCompatibleClient(openai.Config{
+		APIKey:      c.Config.OpenAI.APIKey,
+		Model:       c.Config.OpenAI.Model,
+		BaseURL:     c.Config.OpenAI.BaseURL,
+		StreamUsage: true,
+	})
 	log.Println("✓ OpenAI client initialized")
 
+	// Initialize LLM providers
+	if err := c.initLLMProviders(); err != nil {
+		return err
+	}
+
 	// Initialize OAuth Clients
 	oauth.InitGoogleOAuth()
 	oauth.InitLineOAuth()
@@ -194,11 +325,53 @@ func (c *Container) initExternalClients() error {
 	return nil
 }
 
+func (c *Container) initLLMProviders() error {
+	c.LLMRegistry = llm.NewRegistry()
+	c.LLMRegistry.Register(c.OpenAIClient)
+	c.LLMRegistry.Register(llm.NewFakeProvider())
+
+	if compatible := c.Config.LLM.Compatible; compatible.BaseURL != "" {
+		c.LLMRegistry.Register(openai.NewCompatibleClient(openai.Config{
+			Name:        "compatible",
+			APIKey:      compatible.APIKey,
+			Model:       compatible.Model,
+			BaseURL:     compatible.BaseURL,
+			AuthHeader:  compatible.AuthHeader,
+			APIVersion:  compatible.APIVersion,
+			StreamUsage: compatible.StreamUsage,
+		}))
+	}
+
+	var err error
+	if c.ChatLLM, err = c.LLMRegistry.ForFeature(c.Config.LLM.Chat.Provider, c.Config.LLM.Chat.Model); err != nil {
+		return fmt.Errorf("chat: %w", err)
+	}
+	if c.TravelSummaryLLM, err = c.LLMRegistry.ForFeature(c.Config.LLM.TravelSummary.Provider, c.Config.LLM.TravelSummary.Model); err != nil {
+		return fmt.Errorf("travel summary: %w", err)
+	}
+	if c.PlaceOverviewLLM, err = c.LLMRegistry.ForFeature(c.Config.LLM.PlaceOverview.Provider, c.Config.LLM.PlaceOverview.Model); err != nil {
+		return fmt.Errorf("place overview: %w", err)
+	}
+
+	log.Printf("✓ LLM providers initialized (chat=%s, travel_summary=%s, place_overview=%s)",
+		c.ChatLLM.Name(), c.TravelSummaryLLM.Name(), c.PlaceOverviewLLM.Name())
+	return nil
+}
+
 func (c *Container) initRepositories() error {
 	c.UserRepository = postgres.NewUserRepository(c.DB)
+	c.SessionRepository = postgres.NewSessionRepository(c.DB)
+	c.EmailTokenRepository = postgres.NewEmailTokenRepository(c.DB)
+	c.UserIdentityRepository = postgres.NewUserIdentityRepository(c.DB)
+	c.RecoveryCodeRepository = postgres.NewRecoveryCodeRepository(c.DB)
+	c.PermissionRepository = postgres.NewPermissionRepository(c.DB)
+	c.APIKeyRepository = postgres.NewAPIKeyRepository(c.DB)
+	c.DataExportRepository = postgres.NewDataExportRepository(c.DB)
+	c.PersonalDataRepository = postgres.NewPersonalDataRepository(c.DB)
 	c.TaskRepository = postgres.NewTaskRepository(c.DB)
 	c.FileRepository = postgres.NewFileRepository(c.DB)
 	c.JobRepository = postgres.NewJobRepository(c.DB)
+	c.JobRunRepository = postgres.NewJobRunRepository(c.DB)
 
 	// STOU Smart Tour repositories
 	c.FolderRepository = postgres.NewFolderRepository(c.DB)
@@ -208,6 +381,7 @@ func (c *Container) initRepositories() error {
 	c.AIChatSessionRepository = postgres.NewAIChatSessionRepository(c.DB)
 	c.AIChatMessageRepository = postgres.NewAIChatMessageRepository(c.DB)
 	c.PlaceAIContentRepository = postgres.NewPlaceAIContentRepository(c.DB)
+	c.PlaceRepository = postgres.NewPlaceRepository(c.DB)
 	c.APIRequestLogRepository = postgres.NewAPIRequestLogRepository(c.DB)
 
 	log.Println("✓ Repositories initialized")
@@ -216,32 +390,60 @@ func (c *Container) initRepositories() error {
 
 func (c *Container) initServices() error {
 	// Initialize API Logger Service first (other services may use it)
-	c.APILoggerService = serviceimpl.NewAPILoggerService(c.APIRequestLogRepository)
+	c.APIBudgetGuard = serviceimpl.NewAPIBudgetGuard(c.RedisClient, c.APIRequestLogRepository, c.UserRepository, c.Config.Budget)
+	c.APILoggerService = serviceimpl.NewAPILoggerService(c.APIRequestLogRepository, c.APIBudgetGuard)
 	log.Println("✓ API Logger Service initialized")
 
-	c.UserService = serviceimpl.NewUserService(c.UserRepository, c.Config.JWT.Secret, c.R2Storage, c.Config.R2.PublicURL)
-	c.TaskService = serviceimpl.NewTaskService(c.TaskRepository, c.UserRepository)
-	c.FileService = serviceimpl.NewFileService(c.FileRepository, c.UserRepository, c.R2Storage)
+	// Every LLM call is logged with its tokens and price, per feature
+	prices := c.Config.LLM.Prices
+	c.ChatLLM = serviceimpl.NewMeteredLLM(c.ChatLLM, c.APILoggerService, prices)
+	c.TravelSummaryLLM = serviceimpl.NewMeteredLLM(c.TravelSummaryLLM, c.APILoggerService, prices)
+	c.PlaceOverviewLLM = serviceimpl.NewMeteredLLM(c.PlaceOverviewLLM, c.APILoggerService, prices)
+
+	c.PermissionService = serviceimpl.NewPermissionService(c.PermissionRepository)
+	c.APIKeyService = serviceimpl.NewAPIKeyService(c.APIKeyRepository, c.Config.APIKey)
+
+	c.UserService = serviceimpl.NewUserService(
+		c.UserRepository,
+		c.SessionRepository,
+		c.EmailTokenRepository,
+		c.UserIdentityRepository,
+		c.RecoveryCodeRepository,
+		c.DataExportRepository,
+		c.PersonalDataRepository,
+		c.RedisClient.GetClient(),
+		c.R2Storage,
+		c.Mailer,
+		c.Config,
+	)
+	c.TaskService = serviceimpl.NewTaskService(c.TaskRepository, c.UserRepository, c.PermissionService)
+	c.FileService = serviceimpl.NewFileService(c.FileRepository, c.UserRepository, c.R2Storage, c.PermissionService)
 
 	// STOU Smart Tour services
 	c.SearchService = serviceimpl.NewSearchService(
 		c.SearchHistoryRepository,
 		c.PlaceAIContentRepository,
+		c.PlaceRepository,
 		c.GoogleSearchClient,
 		c.GooglePlacesClient,
 		c.GoogleYouTubeClient,
-		c.OpenAIClient,
+		c.PlaceOverviewLLM,
+		c.Boundaries,
 		c.RedisClient.GetClient(),
 		c.APILoggerService,
+		c.Config.Search,
+		c.Config.JWT,
 	)
 
 	c.AIService = serviceimpl.NewAIService(
 		c.AIChatSessionRepository,
 		c.AIChatMessageRepository,
 		c.SearchHistoryRepository,
-		c.OpenAIClient,
+		c.ChatLLM,
+		c.TravelSummaryLLM,
 		c.GoogleSearchClient,
 		c.RedisClient.GetClient(),
+		c.APILoggerService,
 	)
 
 	c.FolderService = serviceimpl.NewFolderService(
@@ -254,24 +456,123 @@ func (c *Container) initServices() error {
 
 	c.UtilityService = serviceimpl.NewUtilityService(
 		c.GoogleTranslateClient,
+		c.GoogleGeocodingClient,
+		c.Boundaries,
 		c.RedisClient.GetClient(),
+		c.APILoggerService,
 		c.Config,
 	)
 
+	c.HealthService = serviceimpl.NewHealthService(c.healthProbes(), c.Config.Health)
+
 	log.Println("✓ Services initialized")
 	return nil
 }
 
+// healthProbes lists the dependencies /readyz checks. Postgres and Redis
+// are critical; without the others the API still serves most requests.
+func (c *Container) healthProbes() []services.HealthProbe {
+	probeCacheTTL := c.Config.Health.ProbeCacheTTL
+
+	return []services.HealthProbe{
+		{
+			Name:     "postgres",
+			Critical: true,
+			Check: func(ctx context.Context) (string, error) {
+				version, err := postgres.Ping(ctx, c.DB)
+				if err != nil {
+					return "", err
+				}
+				return "schema " + version, nil
+			},
+		},
+		{
+			Name:     "redis",
+			Critical: true,
+			Check: func(ctx context.Context) (string, error) {
+				return "", c.RedisClient.Ping(ctx)
+			},
+		},
+		{
+			Name: "r2",
+			Check: func(ctx context.Context) (string, error) {
+				if c.R2Storage == nil {
+					return "", errors.New("not configured")
+				}
+				return c.Config.R2.Bucket, c.R2Storage.Ping(ctx)
+			},
+		},
+		{
+			Name: "scheduler",
+			Check: func(ctx context.Context) (string, error) {
+				if c.EventScheduler == nil || !c.EventScheduler.IsRunning() {
+					return "", errors.New("not running")
+				}
+				return fmt.Sprintf("%d jobs", len(c.EventScheduler.ListJobs())), nil
+			},
+		},
+		{
+			Name:     "google_api",
+			CacheFor: probeCacheTTL,
+			Check: func(ctx context.Context) (string, error) {
+				return "", c.GoogleSearchClient.Probe(ctx)
+			},
+		},
+		{
+			Name:     "openai_api",
+			CacheFor: probeCacheTTL,
+			Check: func(ctx context.Context) (string, error) {
+				return c.OpenAIClient.Name(), c.OpenAIClient.Probe(ctx)
+			},
+		},
+	}
+}
+
 func (c *Container) initScheduler() error {
 	c.EventScheduler = scheduler.NewEventScheduler()
-	c.JobService = serviceimpl.NewJobService(c.JobRepository, c.EventScheduler)
+
+	// Handlers that jobs created through the admin API can name
+	jobRegistry := serviceimpl.NewJobRegistry(
+		serviceimpl.NewCacheWarmJob(c.SearchService),
+		serviceimpl.NewCleanupAPILogsJob(c.APILoggerService),
+		serviceimpl.NewRefreshPlaceAIContentJob(c.PlaceAIContentRepository, c.SearchService),
+		serviceimpl.NewPurgeSearchHistoryJob(c.SearchHistoryRepository),
+	)
+	c.JobService = serviceimpl.NewJobService(c.JobRepository, c.JobRunRepository, c.EventScheduler, jobRegistry)
 
 	// Start the scheduler
 	c.EventScheduler.Start()
 	log.Println("✓ Event scheduler started")
 
-	// Load and schedule existing active jobs
 	ctx := context.Background()
+
+	// Purge accounts whose deletion grace period is over and drop expired data exports
+	err := c.EventScheduler.AddJob("privacy-maintenance", "*/15 * * * *", func() {
+		if purged, err := c.UserService.PurgeDueAccounts(ctx); err != nil {
+			log.Printf("Warning: Failed to purge deleted accounts: %v", err)
+		} else if purged > 0 {
+			log.Printf("✓ Purged %d deleted accounts", purged)
+		}
+		if _, err := c.UserService.CleanupDataExports(ctx); err != nil {
+			log.Printf("Warning: Failed to clean up data exports: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to schedule privacy maintenance: %v", err)
+	}
+
+	// Bring the API budget counters up to the logged spend, now and every few minutes
+	syncBudgets := func() {
+		if err := c.APIBudgetGuard.Sync(ctx); err != nil {
+			log.Printf("Warning: Failed to sync API budgets: %v", err)
+		}
+	}
+	syncBudgets()
+	if err := c.EventScheduler.AddJob("api-budget-sync", "*/5 * * * *", syncBudgets); err != nil {
+		log.Printf("Warning: Failed to schedule API budget sync: %v", err)
+	}
+
+	// Load and schedule existing active jobs
 	jobs, _, err := c.JobService.ListJobs(ctx, 0, 1000)
 	if err != nil {
 		log.Printf("Warning: Failed to load existing jobs: %v", err)
@@ -299,6 +600,17 @@ func (c *Container) initScheduler() error {
 	return nil
 }
 
+// initMetrics exposes state the container's components already track
+func (c *Container) initMetrics() {
+	metrics.RegisterGaugeFunc("websocket_clients", "Connected WebSocket clients.", func() float64 {
+		return float64(websocket.Manager.GetTotalClients())
+	})
+	metrics.RegisterGaugeFunc("api_logger_buffer_size", "API request logs waiting to be flushed to the database.", func() float64 {
+		return float64(c.APILoggerService.BufferSize())
+	})
+	log.Println("✓ Metrics registered")
+}
+
 func (c *Container) Cleanup() error {
 	log.Println("Starting cleanup...")
 
@@ -339,6 +651,15 @@ func (c *Container) Cleanup() error {
 		}
 	}
 
+	// Export the spans still buffered
+	if c.ShutdownTracing != nil {
+		if err := c.ShutdownTracing(context.Background()); err != nil {
+			log.Printf("Warning: Failed to flush traces: %v", err)
+		} else {
+			log.Println("✓ Tracing stopped")
+		}
+	}
+
 	log.Println("✓ Cleanup completed")
 	return nil
 }
@@ -353,18 +674,21 @@ func (c *Container) GetConfig() *config.Config {
 
 func (c *Container) GetHandlerServices() *handlers.Services {
 	return &handlers.Services{
-		UserService:     c.UserService,
-		TaskService:     c.TaskService,
-		FileService:     c.FileService,
-		JobService:      c.JobService,
-		SearchService:   c.SearchService,
-		AIService:       c.AIService,
-		FolderService:   c.FolderService,
-		FavoriteService: c.FavoriteService,
-		UtilityService:  c.UtilityService,
+		UserService:       c.UserService,
+		TaskService:       c.TaskService,
+		FileService:       c.FileService,
+		JobService:        c.JobService,
+		SearchService:     c.SearchService,
+		AIService:         c.AIService,
+		FolderService:     c.FolderService,
+		FavoriteService:   c.FavoriteService,
+		UtilityService:    c.UtilityService,
+		PermissionService: c.PermissionService,
+		APIKeyService:     c.APIKeyService,
+		HealthService:     c.HealthService,
 	}
 }
 
 func (c *Container) GetAPILoggerService() *serviceimpl.APILoggerService {
 	return c.APILoggerService
-}
\ No newline at end of file
+}
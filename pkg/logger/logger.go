@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -132,11 +133,15 @@ func DebugContext(ctx context.Context, msg string, args ...any) {
 	GetLogger().Debug(msg, args...)
 }
 
-// appendRequestID appends request_id to args if present in context
+// appendRequestID appends request_id, trace_id and span_id to args if
+// present in context
 func appendRequestID(ctx context.Context, args []any) []any {
 	if requestID := GetRequestID(ctx); requestID != "" {
 		args = append(args, "request_id", requestID)
 	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		args = append(args, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
 	return args
 }
 
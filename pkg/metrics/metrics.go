@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Cache lookup results
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// External API error reasons
+const (
+	ErrorTransport = "transport" // request failed or the body couldn't be read
+	ErrorStatus    = "status"    // non-200 response
+	ErrorDecode    = "decode"    // the response wasn't what we expected
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route pattern and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Redis cache lookups by key prefix and result (hit, miss, error).",
+	}, []string{"prefix", "result"})
+
+	externalAPIDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "external_api_request_duration_seconds",
+		Help:    "Latency of calls to external APIs (Google, LLM providers) by service.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"service"})
+
+	externalAPIErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "external_api_errors_total",
+		Help: "Failed calls to external APIs by service and reason (transport, status, decode).",
+	}, []string{"service", "reason"})
+
+	jobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduler_job_runs_total",
+		Help: "Scheduled job runs by job ID.",
+	}, []string{"job"})
+
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scheduler_job_duration_seconds",
+		Help:    "How long scheduled jobs ran by job ID.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900},
+	}, []string{"job"})
+)
+
+// ObserveHTTPRequest records a handled request. route is the route pattern
+// (/api/v1/folders/:id), never the raw path, to keep the series bounded.
+func ObserveHTTPRequest(method, route, status string, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// CacheLookup records a cache lookup under a cache_keys.go prefix
+func CacheLookup(prefix, result string) {
+	cacheRequests.WithLabelValues(prefix, result).Inc()
+}
+
+// ObserveExternalAPI records a call to an external API. reason is empty on
+// success, otherwise one of the Error* reasons.
+func ObserveExternalAPI(service string, duration time.Duration, reason string) {
+	externalAPIDuration.WithLabelValues(service).Observe(duration.Seconds())
+	if reason != "" {
+		externalAPIErrors.WithLabelValues(service, reason).Inc()
+	}
+}
+
+// ObserveJobRun records a run of a scheduled job
+func ObserveJobRun(job string, duration time.Duration) {
+	jobRuns.WithLabelValues(job).Inc()
+	jobDuration.WithLabelValues(job).Observe(duration.Seconds())
+}
+
+// RegisterGaugeFunc exposes a value read on every scrape, for state that's
+// already tracked elsewhere (connected clients, buffer sizes)
+func RegisterGaugeFunc(name, help string, fn func() float64) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, fn)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/go-co-op/gocron"
+
+	"gofiber-template/pkg/metrics"
 )
 
 type EventScheduler interface {
@@ -105,6 +107,7 @@ func (s *GocronScheduler) AddJob(id, cronExpr string, task func()) error {
 
 		// Execute the task
 		task()
+		metrics.ObserveJobRun(id, time.Since(now))
 	})
 
 	if err != nil {
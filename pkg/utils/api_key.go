@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader carries a personal API key instead of a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyLocal is the fiber.Locals key of the request's API key. Locals are
+// fasthttp user values, so c.Context().Value finds it too.
+const apiKeyLocal = "apiKey"
+
+// APIKeyContext is the personal API key a request was made with. The key's
+// owner is in the "user" local as usual.
+type APIKeyContext struct {
+	ID         uuid.UUID
+	Scopes     []string
+	DailyLimit int
+}
+
+// HasScope returns true if the key may call endpoints of the scope
+func (k *APIKeyContext) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func SetAPIKeyContext(c *fiber.Ctx, key *APIKeyContext) {
+	c.Locals(apiKeyLocal, key)
+}
+
+// GetAPIKeyFromContext returns the API key of the request, or nil if it was
+// made with a session or anonymously. Pass c.Context() or a context derived
+// from it, so services can tell too.
+func GetAPIKeyFromContext(ctx context.Context) *APIKeyContext {
+	if ctx == nil {
+		return nil
+	}
+	key, _ := ctx.Value(apiKeyLocal).(*APIKeyContext)
+	return key
+}
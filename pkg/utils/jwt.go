@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"log"
 	"strings"
@@ -21,14 +22,18 @@ type JWTClaims struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Role     string `json:"role,omitempty"`
+	// SessionID ties the token to a UserSession so revoking the session
+	// revokes the token
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
 type UserContext struct {
-	ID       uuid.UUID
-	Username string
-	Email    string
-	Role     string
+	ID        uuid.UUID
+	Username  string
+	Email     string
+	Role      string
+	SessionID uuid.UUID
 }
 
 func ValidateTokenStringToUUID(tokenString, jwtSecret string) (*UserContext, error) {
@@ -65,11 +70,18 @@ func ValidateTokenStringToUUID(tokenString, jwtSecret string) (*UserContext, err
 		return nil, ErrInvalidToken
 	}
 
+	// Tokens without a session can't be revoked, so they aren't accepted
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
 	return &UserContext{
-		ID:       userID,
-		Username: claims.Username,
-		Email:    claims.Email,
-		Role:     claims.Role,
+		ID:        userID,
+		Username:  claims.Username,
+		Email:     claims.Email,
+		Role:      claims.Role,
+		SessionID: sessionID,
 	}, nil
 }
 
@@ -107,3 +119,16 @@ func GetUserFromContext(c *fiber.Ctx) (*UserContext, error) {
 	log.Printf("✅ User context valid: ID=%s, Email=%s\n", userCtx.ID, userCtx.Email)
 	return userCtx, nil
 }
+
+// GetUserIDFromContext returns the signed-in user of the request, or nil for
+// guests. Like GetAPIKeyFromContext it reads the "user" local through
+// c.Context() or a context derived from it.
+func GetUserIDFromContext(ctx context.Context) *uuid.UUID {
+	if ctx == nil {
+		return nil
+	}
+	if user, ok := ctx.Value("user").(*UserContext); ok {
+		return &user.ID
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 test vectors ("12345678901234567890")
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	// Codes are the last six digits of the RFC 6238 SHA1 vectors
+	at := time.Unix(1111111109, 0)
+	step := at.Unix() / 30
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		t        time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "rfc vector 59", secret: rfc6238Secret, code: "287082", t: time.Unix(59, 0), wantStep: 1, wantOK: true},
+		{name: "rfc vector 1234567890", secret: rfc6238Secret, code: "005924", t: time.Unix(1234567890, 0), wantStep: 1234567890 / 30, wantOK: true},
+		{name: "rfc vector 2000000000", secret: rfc6238Secret, code: "279037", t: time.Unix(2000000000, 0), wantStep: 2000000000 / 30, wantOK: true},
+		{name: "current step", secret: rfc6238Secret, code: "081804", t: at, wantStep: step, wantOK: true},
+		{name: "phone clock one step behind", secret: rfc6238Secret, code: "081804", t: at.Add(30 * time.Second), wantStep: step, wantOK: true},
+		{name: "phone clock one step ahead", secret: rfc6238Secret, code: "081804", t: at.Add(-30 * time.Second), wantStep: step, wantOK: true},
+		{name: "two steps behind", secret: rfc6238Secret, code: "081804", t: at.Add(60 * time.Second)},
+		{name: "two steps ahead", secret: rfc6238Secret, code: "081804", t: at.Add(-60 * time.Second)},
+		{name: "next step's code", secret: rfc6238Secret, code: "050471", t: time.Unix(1111111111, 0), wantStep: 1111111111 / 30, wantOK: true},
+		{name: "lowercase secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: "081804", t: at, wantStep: step, wantOK: true},
+		{name: "surrounding spaces", secret: rfc6238Secret, code: " 081804 ", t: at, wantStep: step, wantOK: true},
+		{name: "wrong code", secret: rfc6238Secret, code: "081805", t: at},
+		{name: "too short", secret: rfc6238Secret, code: "08180", t: at},
+		{name: "eight digits", secret: rfc6238Secret, code: "07081804", t: at},
+		{name: "empty", secret: rfc6238Secret, code: "", t: at},
+		{name: "invalid secret", secret: "not base32!", code: "081804", t: at},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := ValidateTOTP(tt.secret, tt.code, tt.t)
+			if gotOK != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTP() = (%d, %v), want (%d, %v)", gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// A code must report the step it was generated for wherever in the drift
+// window it is entered, or a replay in the next period would look new
+func TestValidateTOTPReplayReportsSameStep(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issued := time.Unix(1700000000, 0)
+	issuedStep := issued.Unix() / 30
+	code := totpCode(key, issuedStep)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		wantOK bool
+	}{
+		{name: "issuing period", offset: 0, wantOK: true},
+		{name: "end of issuing period", offset: 29 * time.Second, wantOK: true},
+		{name: "next period", offset: 30 * time.Second, wantOK: true},
+		{name: "previous period", offset: -30 * time.Second, wantOK: true},
+		{name: "expired", offset: 60 * time.Second, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Align to the start of the issuing period first
+			at := time.Unix(issuedStep*30, 0).Add(tt.offset)
+			step, ok := ValidateTOTP(secret, code, at)
+			if ok != tt.wantOK {
+				t.Fatalf("ValidateTOTP() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != issuedStep {
+				t.Errorf("ValidateTOTP() step = %d, want %d", step, issuedStep)
+			}
+		})
+	}
+}
+
+func TestTOTPURL(t *testing.T) {
+	got := TOTPURL("Travel App", "user@example.com", "ABC")
+	want := "otpauth://totp/Travel%20App:user@example.com?algorithm=SHA1&digits=6&issuer=Travel%20App&period=30&secret=ABC"
+	if got != want {
+		t.Errorf("TOTPURL() = %q, want %q", got, want)
+	}
+}